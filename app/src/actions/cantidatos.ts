@@ -4,10 +4,11 @@ import { PosCandidate, UpdateCandidate } from "@/components/types/cantidates";
 
 const API = process.env.API_BASE_URL;
 
-export async function GetCantidatosAction() {
+export async function GetCantidatosAction(token: string) {
   try {
     const res = await fetch(`${API}/candidates/`, {
       method: "GET",
+      headers: { Authorization: `Bearer ${token}` },
       cache: "no-store",
     });
     const json = await res.json();
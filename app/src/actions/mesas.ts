@@ -0,0 +1,21 @@
+"use server";
+
+const API = process.env.API_BASE_URL;
+
+export async function GetMesasAction() {
+  try {
+    const res = await fetch(`${API}/mesas`, {
+      method: "GET",
+      cache: "no-store",
+    });
+    const json = await res.json();
+    if (!res.ok)
+      return {
+        success: false,
+        error: json.message || "Error al obtener las mesas",
+      };
+    return { success: true, data: json.data ?? [] };
+  } catch (error) {
+    return { success: false, error };
+  }
+}
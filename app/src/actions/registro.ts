@@ -6,10 +6,11 @@ import { renderToBuffer } from "@react-pdf/renderer";
 
 const API = process.env.API_BASE_URL;
 
-export async function GetRecordAction() {
+export async function GetRecordAction(token: string) {
   try {
     const res = await fetch(`${API}/votes/`, {
       method: "GET",
+      headers: { Authorization: `Bearer ${token}` },
       cache: "no-store",
     });
     const json = await res.json();
@@ -24,10 +25,11 @@ export async function GetRecordAction() {
   }
 }
 
-export async function GetOneRecordAction(Id: string) {
+export async function GetOneRecordAction(Id: string, token: string) {
   try {
     const res = await fetch(`${API}/votes/candidate/${Id}`, {
       method: "GET",
+      headers: { Authorization: `Bearer ${token}` },
       cache: "no-store",
     });
     const json = await res.json();
@@ -42,13 +44,19 @@ export async function GetOneRecordAction(Id: string) {
   }
 }
 
-export async function PostRecordAction(values: PosRecord, token: string) {
+export async function PostRecordAction(
+  values: PosRecord,
+  token: string,
+  mesaLockToken?: string,
+) {
   try {
     const res = await fetch(`${API}/votes/`, {
       method: "POST",
       headers: {
         Authorization: `Bearer ${token}`,
         "Content-Type": "application/json",
+        // token del bloqueo de edición obtenido por el WebSocket /ws/mesas
+        ...(mesaLockToken ? { "X-Mesa-Lock": mesaLockToken } : {}),
       },
       body: JSON.stringify(values),
     });
@@ -71,9 +79,9 @@ export async function PostRecordAction(values: PosRecord, token: string) {
   }
 }
 
-export async function GenerarReportePDF(candidateID: string) {
+export async function GenerarReportePDF(candidateID: string, token: string) {
   try {
-    const res = await GetOneRecordAction(candidateID);
+    const res = await GetOneRecordAction(candidateID, token);
 
     if (!res.success) throw new Error("No se pudieron obtener registros");
 
@@ -15,7 +15,9 @@ export default function Page() {
 
   useEffect(() => {
     const loadData = async () => {
-      const res = await GetCantidatosAction();
+      if (!session?.user?.token) return;
+
+      const res = await GetCantidatosAction(session.user.token);
 
       if (res?.data && Array.isArray(res.data)) {
         setCandidates(res.data);
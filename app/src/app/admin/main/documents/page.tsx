@@ -38,7 +38,7 @@ export default function Page() {
     const loadData = async () => {
       if (!session?.user?.token) return;
 
-      const res = await GetRecordAction();
+      const res = await GetRecordAction(session.user.token);
 
       if (res?.data && Array.isArray(res.data)) {
         setRecord(res.data);
@@ -72,11 +72,11 @@ export default function Page() {
   );
 
   const handleGenerarReporte = async () => {
-    if (!selectedCandidate) return;
+    if (!selectedCandidate || !session?.user?.token) return;
 
     try {
 
-      const base64PDF = await GenerarReportePDF(selectedCandidate);
+      const base64PDF = await GenerarReportePDF(selectedCandidate, session.user.token);
       const binaryString = atob(base64PDF);
       const bytes = new Uint8Array(binaryString.length);
       for (let i = 0; i < binaryString.length; i++) {
@@ -7,8 +7,10 @@ import { useForm } from "react-hook-form";
 import { zodResolver } from "@hookform/resolvers/zod";
 
 import { GetCantidatosAction } from "@/actions/cantidatos";
+import { GetMesasAction } from "@/actions/mesas";
 import { GetPositionAction } from "@/actions/position";
 import { PostRecordAction } from "@/actions/registro";
+import { useMesaLock, type MesaLockState } from "@/hooks/use-mesa-lock";
 
 import { toast } from "sonner";
 
@@ -24,9 +26,17 @@ import {
   FormMessage,
 } from "@/components/ui/form";
 import { Input } from "@/components/ui/input";
+import {
+  Select,
+  SelectTrigger,
+  SelectValue,
+  SelectContent,
+  SelectItem,
+} from "@/components/ui/select";
 import { User } from "lucide-react";
 
 import type { GetCantidatos } from "@/components/types/cantidates";
+import type { GetMesa } from "@/components/types/mesa";
 import type { GetPosition } from "@/components/types/position";
 import {
   formSchemaRegister,
@@ -39,6 +49,7 @@ const API = process.env.NEXT_PUBLIC_API_URL;
 export default function Page() {
   const [positions, setPositions] = useState<GetPosition[]>([]);
   const [candidates, setCandidates] = useState<GetCantidatos[]>([]);
+  const [mesas, setMesas] = useState<GetMesa[]>([]);
   const [activeTab, setActiveTab] = useState<string>("");
   const [isSubmitting, setIsSubmitting] = useState(false);
   const [selectedTypeVote, setSelectedTypeVote] = useState<
@@ -52,15 +63,24 @@ export default function Page() {
 
   const form = useForm<FormValuesRegister>({
     resolver: zodResolver(formSchemaRegister),
-    defaultValues: { mesa: "", votes: {} },
+    defaultValues: { mesaId: "", votes: {} },
   });
 
+  // bloqueo de edición de la mesa elegida; el backend lo exige al registrar
+  const selectedMesaId = form.watch("mesaId");
+  const mesaLock = useMesaLock(selectedMesaId, session?.user?.token);
+
   useEffect(() => {
     (async () => {
+      if (!session?.user?.token) return;
 
-      const persons = await GetCantidatosAction();
+      const persons = await GetCantidatosAction(session.user.token);
       const cargos = await GetPositionAction();
+      const tables = await GetMesasAction();
       if (Array.isArray(persons?.data)) setCandidates(persons.data);
+      // solo las mesas abiertas aceptan votos
+      if (Array.isArray(tables?.data))
+        setMesas(tables.data.filter((m: GetMesa) => m.status === "OPEN"));
       if (Array.isArray(cargos?.data)) {
         setPositions(cargos.data);
         setActiveTab(cargos.data[0]?.id ?? "");
@@ -80,6 +100,8 @@ export default function Page() {
     setOpenConfirm(false);
 
     if (!session?.user?.token) return toast.error("Token inválido");
+    if (!mesaLock.lockToken)
+      return toast.error("No tienes el bloqueo de edición de esta mesa");
 
     const currentPosition = positions.find((p) => p.id === activeTab);
     if (!currentPosition) return toast.error("Posición no encontrada");
@@ -109,8 +131,9 @@ export default function Page() {
 
     for (const { candidateId, totalVotes } of votesToSubmit) {
       const res = await PostRecordAction(
-        { mesa: values.mesa.toUpperCase(), candidateId, totalVotes, typeVote },
-        session.user.token
+        { mesaId: values.mesaId, candidateId, totalVotes, typeVote },
+        session.user.token,
+        mesaLock.lockToken
       );
 
       if (!res.success) {
@@ -126,7 +149,7 @@ export default function Page() {
     );
 
     form.reset({
-      mesa: "",
+      mesaId: "",
       votes: {},
     });
 
@@ -190,17 +213,26 @@ export default function Page() {
 
                       <FormField
                         control={form.control}
-                        name="mesa"
+                        name="mesaId"
                         render={({ field }) => (
                           <FormItem>
                             <FormLabel>Número de Mesa *</FormLabel>
-                            <FormControl>
-                              <Input
-                                placeholder="Ingrese número de mesa"
-                                {...field}
-                                className="max-w-sm"
-                              />
-                            </FormControl>
+                            <Select onValueChange={field.onChange} value={field.value}>
+                              <FormControl className="max-w-sm">
+                                <SelectTrigger>
+                                  <SelectValue placeholder="Seleccione una mesa abierta" />
+                                </SelectTrigger>
+                              </FormControl>
+                              <SelectContent>
+                                {mesas.map((m) => (
+                                  <SelectItem key={m.id} value={m.id}>
+                                    {m.number}
+                                    {m.location ? ` - ${m.location}` : ""}
+                                  </SelectItem>
+                                ))}
+                              </SelectContent>
+                            </Select>
+                            <MesaLockStatus state={mesaLock.state} />
                             <FormMessage />
                           </FormItem>
                         )}
@@ -321,7 +353,7 @@ export default function Page() {
 
                       <Button
                         type="submit"
-                        disabled={isSubmitting}
+                        disabled={isSubmitting || !mesaLock.lockToken}
                         className="w-full md:w-auto px-8 py-6 text-sm"
                       >
                         {isSubmitting
@@ -347,3 +379,24 @@ export default function Page() {
     </div>
   );
 }
+
+function MesaLockStatus({ state }: { state: MesaLockState }) {
+  switch (state.status) {
+    case "pending":
+      return <p className="text-sm text-gray-500">Solicitando el bloqueo de la mesa...</p>;
+    case "granted":
+      return <p className="text-sm text-green-700">Mesa reservada para tu edición</p>;
+    case "denied":
+      return (
+        <p className="text-sm text-red-600">
+          {state.holder
+            ? `La mesa la está editando ${state.holder.email}`
+            : state.message || "La mesa la está editando otro digitador"}
+        </p>
+      );
+    case "error":
+      return <p className="text-sm text-red-600">{state.message}</p>;
+    default:
+      return null;
+  }
+}
@@ -1,11 +1,19 @@
-"use client";
+import { redirect } from "next/navigation";
 
+import { auth } from "@/auth";
 import VotingDashboard from "@/components/voting-dashboard";
 
-export default function Page() {
+// los votos y candidatos exigen sesión: sin ella se envía al login
+export default async function Page() {
+  const session = await auth();
+
+  if (!session?.user?.token) {
+    redirect("/admin");
+  }
+
   return (
     <main className="max-h-screen h-full bg-background text-foreground">
-      <VotingDashboard />
+      <VotingDashboard token={session.user.token} />
     </main>
   );
 }
@@ -1,15 +1,51 @@
 // ===== auth.config.ts =====
 /* eslint-disable @typescript-eslint/no-explicit-any */
 import Credentials from "next-auth/providers/credentials";
-import type { NextAuthConfig } from "next-auth";
+import { CredentialsSignin, type NextAuthConfig } from "next-auth";
 import Google from "next-auth/providers/google";
 
 const API_BASE = process.env.API_BASE_URL;
 
-async function postJSON(url: string, body: unknown) {
+// Proxies de confianza delante de Next.js que añaden la IP de su cliente a
+// X-Forwarded-For. Sin ninguno la cabecera la escribe el navegador, así que no
+// se reenvía y el backend, que confía en este servidor (TRUSTED_PROXIES), solo
+// limita por email y cuenta.
+const TRUSTED_PROXY_HOPS = Number(process.env.TRUSTED_PROXY_HOPS ?? 0);
+
+// clientIP: la entrada de X-Forwarded-For que añadió el proxy de confianza más
+// externo; las anteriores las pudo escribir el propio cliente
+function clientIP(forwardedFor?: string | null) {
+  if (!forwardedFor || !(TRUSTED_PROXY_HOPS > 0)) return null;
+  const hops = forwardedFor
+    .split(",")
+    .map((h) => h.trim())
+    .filter(Boolean);
+  return hops[hops.length - TRUSTED_PROXY_HOPS] ?? null;
+}
+
+// AuthStepError: el login necesita otro paso; el formulario recibe code en el
+// resultado de signIn y pide el dato que falta
+class AuthStepError extends CredentialsSignin {
+  constructor(code: string) {
+    super();
+    this.code = code;
+  }
+}
+
+async function postJSON(
+  url: string,
+  body: unknown,
+  clientIP?: string | null,
+  accessToken?: string,
+) {
+  const headers: Record<string, string> = { "Content-Type": "application/json" };
+  if (accessToken) headers.Authorization = `Bearer ${accessToken}`;
+  // El backend limita los intentos de login por IP del cliente
+  if (clientIP) headers["X-Forwarded-For"] = clientIP;
+
   const res = await fetch(url, {
     method: "POST",
-    headers: { "Content-Type": "application/json" },
+    headers,
     body: JSON.stringify(body),
   });
   let data: any = null;
@@ -31,24 +67,70 @@ const authConfig: NextAuthConfig = {
       credentials: {
         email: { label: "Email", type: "email" },
         password: { label: "Password", type: "password" },
+        code: { label: "Código 2FA", type: "text" },
+        recoveryCode: { label: "Código de recuperación", type: "text" },
+        newPassword: { label: "Nueva contraseña", type: "password" },
       },
-      authorize: async (credentials) => {
+      authorize: async (credentials, request) => {
         if (!credentials?.email || !credentials?.password) {
           throw new Error("Credenciales incompletas");
         }
 
+        const ip = clientIP(request?.headers?.get("x-forwarded-for"));
         const res = await postJSON(`${API_BASE}/auth/signin`, {
           email: credentials.email,
           password: credentials.password,
           provider: "credentials",
-        });
+        }, ip);
 
         if (!res.ok || res.data?.status !== 200) {
           throw new Error(res.data?.message || "Credenciales inválidas");
         }
 
-        const data = res.data?.data;
-        if (!data?.id) {
+        let data = res.data?.data;
+
+        // Con 2FA el backend no emite tokens: devuelve un challenge que se
+        // canjea junto con el código TOTP o uno de recuperación
+        if (data?.mfaStatus === "mfa_enrollment_required") {
+          throw new AuthStepError("mfa_enrollment_required");
+        }
+        if (data?.mfaStatus === "mfa_required") {
+          const code = (credentials.code as string | undefined)?.trim();
+          const recoveryCode = (credentials.recoveryCode as string | undefined)?.trim();
+          if (!code && !recoveryCode) throw new AuthStepError("mfa_required");
+
+          const challenge = await postJSON(`${API_BASE}/auth/2fa/challenge`, {
+            mfaToken: data.mfaToken,
+            code: code || undefined,
+            recoveryCode: recoveryCode || undefined,
+          }, ip);
+          if (!challenge.ok || challenge.data?.status !== 200) {
+            throw new AuthStepError(challenge.status === 429 ? "rate_limited" : "mfa_invalid");
+          }
+          data = challenge.data?.data;
+        }
+
+        // Contraseña temporal o restablecida: el token solo sirve para
+        // cambiarla, así que se cambia aquí antes de crear la sesión
+        if (data?.mustChangePassword) {
+          const newPassword = credentials.newPassword as string | undefined;
+          if (!newPassword) {
+            // la sesión recién emitida no se usará: se revoca
+            await postJSON(`${API_BASE}/auth/logout`, { refreshToken: data.refreshToken });
+            throw new AuthStepError("password_change_required");
+          }
+
+          const changed = await postJSON(`${API_BASE}/auth/password`, {
+            currentPassword: credentials.password,
+            newPassword,
+          }, ip, data.token);
+          if (!changed.ok || changed.data?.status !== 200) {
+            throw new AuthStepError("password_rejected");
+          }
+          data = changed.data?.data;
+        }
+
+        if (!data?.id || !data?.token) {
           throw new Error("Respuesta inválida del servidor");
         }
 
@@ -58,6 +140,8 @@ const authConfig: NextAuthConfig = {
           name: data.name,
           role: data.role,
           token: data.token,
+          refreshToken: data.refreshToken,
+          expiresAt: data.expiresAt,
         } as any;
       },
     }),
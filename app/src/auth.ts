@@ -18,6 +18,26 @@ async function postJSON(url: string, body: unknown) {
   return { ok: res.ok, status: res.status, data };
 }
 
+// Renueva el access token del backend un minuto antes de que expire
+async function refreshAccessToken(token: any) {
+  const res = await postJSON(`${API_BASE}/auth/refresh`, {
+    refreshToken: token.refreshToken,
+  });
+
+  if (!res.ok || res.data?.status !== 200) {
+    return { ...token, accessToken: null, error: "RefreshTokenError" };
+  }
+
+  const data = res.data?.data;
+  return {
+    ...token,
+    role: data.role,
+    accessToken: data.token,
+    refreshToken: data.refreshToken,
+    expiresAt: data.expiresAt,
+  };
+}
+
 export const { handlers, signIn, signOut, auth } = NextAuth({
   ...authConfig,
 
@@ -30,6 +50,8 @@ export const { handlers, signIn, signOut, auth } = NextAuth({
         token.email = (user as any).email ?? null;
         (token as any).role = (user as any).role ?? "ADMIN";
         (token as any).accessToken = (user as any).token;
+        (token as any).refreshToken = (user as any).refreshToken;
+        (token as any).expiresAt = (user as any).expiresAt;
         return token;
       }
 
@@ -40,6 +62,7 @@ export const { handlers, signIn, signOut, auth } = NextAuth({
         if (email) {
           const signinRes = await postJSON(`${API_BASE}/auth/signin`, {
             email,
+            idToken: account.id_token,
             provider: "google",
           });
 
@@ -50,16 +73,39 @@ export const { handlers, signIn, signOut, auth } = NextAuth({
           }
 
           const data = signinRes.data?.data;
+          // el challenge de 2FA solo se puede completar con email y contraseña
+          if (data?.mfaStatus) {
+            throw new Error(
+              "Tu cuenta usa verificación en dos pasos: inicia sesión con email y contraseña"
+            );
+          }
+          // con la contraseña vencida el token no sirve más que para cambiarla
+          if (data?.mustChangePassword) {
+            throw new Error(
+              "Debes cambiar tu contraseña: inicia sesión con email y contraseña"
+            );
+          }
           token.id = data.id;
           token.email = data.email;
           token.name = data.name;
           (token as any).role = data.role;
           (token as any).accessToken = data.token;
+          (token as any).refreshToken = data.refreshToken;
+          (token as any).expiresAt = data.expiresAt;
           (token as any).provider = "google";
           return token;
         }
       }
 
+      const expiresAt = (token as any).expiresAt as number | undefined;
+      if (
+        (token as any).refreshToken &&
+        expiresAt &&
+        Date.now() / 1000 > expiresAt - 60
+      ) {
+        return refreshAccessToken(token);
+      }
+
       return token;
     },
 
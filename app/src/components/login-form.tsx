@@ -20,21 +20,62 @@ import {
   ShieldUser,
   EyeOff,
   Eye,
+  KeyRound,
 } from "lucide-react";
 import { useRouter } from "next/navigation";
 import { signIn } from "next-auth/react";
 import { toast } from "sonner";
 import { Label } from "./ui/label";
 
+// authStepMessages: mensajes para los code de AuthStepError (auth.config.ts)
+const authStepMessages: Record<string, { title: string; description: string }> = {
+  mfa_required: {
+    title: "Verificación en dos pasos",
+    description: "Ingresa el código de tu aplicación de autenticación.",
+  },
+  mfa_invalid: {
+    title: "Código incorrecto",
+    description: "El código no es válido o ya fue usado. Inténtalo de nuevo.",
+  },
+  mfa_enrollment_required: {
+    title: "Verificación en dos pasos obligatoria",
+    description:
+      "Tu rol exige verificación en dos pasos y tu cuenta aún no la tiene configurada. Contacta al administrador del sistema.",
+  },
+  password_change_required: {
+    title: "Cambio de contraseña obligatorio",
+    description:
+      "Tu contraseña es temporal. Elige una nueva para continuar (y, si usas verificación en dos pasos, ingresa un código nuevo).",
+  },
+  password_rejected: {
+    title: "Contraseña no aceptada",
+    description:
+      "La nueva contraseña no cumple la política de seguridad o es igual a la actual. Elige otra más larga y menos común.",
+  },
+  rate_limited: {
+    title: "Demasiados intentos",
+    description: "Espera unos minutos antes de volver a intentarlo.",
+  },
+};
+
 export default function LoginForm() {
   const router = useRouter();
   const [showPassword, setShowPassword] = useState(false);
   const [isLoading, setIsLoading] = useState(false);
   const [googleLoading, setGoogleLoading] = useState(false);
 
+  // mfaStep: el backend pidió el código de verificación en dos pasos;
+  // passwordStep: la contraseña actual es temporal y hay que cambiarla
+  const [mfaStep, setMfaStep] = useState(false);
+  const [useRecoveryCode, setUseRecoveryCode] = useState(false);
+  const [passwordStep, setPasswordStep] = useState(false);
+
   const [formData, setFormData] = useState({
     email: "",
     password: "",
+    code: "",
+    newPassword: "",
+    confirmPassword: "",
   });
 
   const onChange = (e: React.ChangeEvent<HTMLInputElement>) => {
@@ -43,18 +84,36 @@ export default function LoginForm() {
 
   async function onSubmit(event: React.FormEvent) {
     event.preventDefault();
+
+    if (passwordStep && formData.newPassword !== formData.confirmPassword) {
+      toast.error("Las contraseñas no coinciden");
+      return;
+    }
     setIsLoading(true);
 
     try {
       const result = await signIn("credentials", {
         email: formData.email,
         password: formData.password,
+        ...(mfaStep
+          ? useRecoveryCode
+            ? { recoveryCode: formData.code }
+            : { code: formData.code }
+          : {}),
+        ...(passwordStep ? { newPassword: formData.newPassword } : {}),
         redirect: false,
       });
 
       if (result?.error) {
-        toast.error("Error de autenticación", {
+        const failure = authStepMessages[result.code ?? ""];
+        if (result.code === "mfa_required") setMfaStep(true);
+        if (result.code === "password_change_required") setPasswordStep(true);
+        // el código 2FA ya se consumió: el siguiente envío necesita uno nuevo
+        if (result.code !== "mfa_required") setFormData({ ...formData, code: "" });
+
+        toast.error(failure?.title ?? "Error de autenticación", {
           description:
+            failure?.description ??
             "Credenciales incorrectas. Por favor, verifica tu email y contraseña.",
         });
         setIsLoading(false);
@@ -185,6 +244,85 @@ export default function LoginForm() {
               </div>
             </div>
 
+            {mfaStep && (
+              <div className="space-y-2">
+                <Label htmlFor="code">
+                  {useRecoveryCode
+                    ? "Código de recuperación"
+                    : "Código de verificación"}
+                </Label>
+
+                <div className="relative">
+                  <KeyRound className="absolute left-3 inset-y-0 my-auto h-4 w-4 text-muted-foreground" />
+
+                  <Input
+                    id="code"
+                    placeholder={useRecoveryCode ? "xxxx-xxxx-xxxx" : "123456"}
+                    inputMode={useRecoveryCode ? "text" : "numeric"}
+                    autoComplete="one-time-code"
+                    value={formData.code}
+                    onChange={onChange}
+                    className="pl-9 h-11 border-border/50 focus-visible:ring-primary"
+                    required
+                    autoFocus
+                    disabled={isLoading}
+                  />
+                </div>
+
+                <button
+                  type="button"
+                  className="text-xs text-muted-foreground underline"
+                  onClick={() => {
+                    setUseRecoveryCode(!useRecoveryCode);
+                    setFormData({ ...formData, code: "" });
+                  }}
+                  disabled={isLoading}
+                >
+                  {useRecoveryCode
+                    ? "Usar el código de la aplicación"
+                    : "Usar un código de recuperación"}
+                </button>
+              </div>
+            )}
+
+            {passwordStep && (
+              <>
+                <div className="space-y-2">
+                  <Label htmlFor="newPassword">Nueva contraseña</Label>
+                  <div className="relative">
+                    <Lock className="absolute left-3 inset-y-0 my-auto h-4 w-4 text-muted-foreground" />
+                    <Input
+                      id="newPassword"
+                      type="password"
+                      autoComplete="new-password"
+                      value={formData.newPassword}
+                      onChange={onChange}
+                      className="pl-9 h-11 border-border/50 focus-visible:ring-primary"
+                      required
+                      disabled={isLoading}
+                    />
+                  </div>
+                </div>
+
+                <div className="space-y-2">
+                  <Label htmlFor="confirmPassword">Confirmar contraseña</Label>
+                  <div className="relative">
+                    <Lock className="absolute left-3 inset-y-0 my-auto h-4 w-4 text-muted-foreground" />
+                    <Input
+                      id="confirmPassword"
+                      type="password"
+                      autoComplete="new-password"
+                      value={formData.confirmPassword}
+                      onChange={onChange}
+                      className="pl-9 h-11 border-border/50 focus-visible:ring-primary"
+                      required
+                      disabled={isLoading}
+                    />
+                  </div>
+                </div>
+              </>
+            )}
+
             <Button
               type="submit"
               className="w-full h-12 text-base font-semibold bg-linear-to-r from-primary to-primary/90 hover:from-primary/90 hover:to-primary shadow-lg hover:shadow-xl transition-all duration-200 group"
@@ -1,7 +1,7 @@
 import { z } from "zod";
 
 export const formSchemaRegister = z.object({
-    mesa: z.string().min(1, "La mesa es requerida"),
+    mesaId: z.string().min(1, "La mesa es requerida"),
     votes: z.record(z.string(), z.number().min(0).optional()),
 });
 
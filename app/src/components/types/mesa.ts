@@ -0,0 +1,11 @@
+export interface GetMesa {
+  id: string;
+  electionId: string;
+  number: string;
+  location: string | null;
+  electorsDocentes: number;
+  electorsPublico: number;
+  status: "PENDING" | "OPEN" | "CLOSED" | "COUNTED" | "ANNULLED";
+  createdAt: string;
+  updatedAt: string;
+}
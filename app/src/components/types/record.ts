@@ -2,6 +2,7 @@ export interface GetRecord {
   id: string;
   candidateId: string;
   candidateName: string;
+  mesaId: string;
   mesa: string;
   totalVotes: string;
   typeVote: string;
@@ -15,7 +16,7 @@ interface PositionSimple {
 }
 
 export interface PosRecord {
-  mesa: string;
+  mesaId: string;
   candidateId: string;
   totalVotes: number;
   typeVote: "DOCENTES" | "PUBLICO";
@@ -26,7 +26,7 @@ interface Vote {
   };
 }
 
-export default function VotingDashboardPage() {
+export default function VotingDashboardPage({ token }: { token: string }) {
   const [positions, setPositions] = useState<PositionChip[]>([]);
   const [selectedPositionId, setSelectedPositionId] = useState<string | null>(null);
   const [stats, setStats] = useState({
@@ -40,8 +40,8 @@ export default function VotingDashboardPage() {
 
   const fetchResults = async () => {
     const resPosition = await GetPositionAction();
-    const resCandidatos = await GetCantidatosAction();
-    const resVotes = await GetRecordAction();
+    const resCandidatos = await GetCantidatosAction(token);
+    const resVotes = await GetRecordAction(token);
 
     if (resPosition.data && resCandidatos.data && resVotes.data) {
       const positionsData = resPosition.data;
@@ -159,7 +159,7 @@ export default function VotingDashboardPage() {
     fetchResults();
     const interval = setInterval(fetchResults, 30000);
     return () => clearInterval(interval);
-  }, []);
+  }, [token]);
 
   const selectedPosition = positions.find((p) => p.positionId === selectedPositionId);
 
@@ -0,0 +1,155 @@
+/* eslint-disable @typescript-eslint/no-explicit-any */
+import { useEffect, useRef, useState } from "react";
+
+const API = process.env.NEXT_PUBLIC_API_URL;
+
+// Margen con el que se renueva el bloqueo antes de que expire
+const RENEW_MARGIN_MS = 20_000;
+// Espera antes de reconectar si se cae el WebSocket
+const RECONNECT_MS = 3_000;
+
+export type MesaLockHolder = {
+  email: string;
+  role: string;
+};
+
+export type MesaLockState =
+  | { status: "idle" }
+  | { status: "pending" }
+  | { status: "granted"; token: string; expiresAt: string }
+  | { status: "denied"; holder?: MesaLockHolder; message?: string }
+  | { status: "error"; message: string };
+
+function lockSocketURL() {
+  const base = (API ?? "").replace(/^http/, "ws").replace(/\/$/, "");
+  return `${base}/ws/mesas`;
+}
+
+// sendLockRequest: lock pide (o renueva) el bloqueo de la mesa; sin mesa se
+// libera el que se tuviera
+function sendLockRequest(ws: WebSocket, mesaId: string) {
+  if (ws.readyState !== WebSocket.OPEN) return false;
+  ws.send(JSON.stringify(mesaId ? { type: "lock", mesaId } : { type: "release" }));
+  return true;
+}
+
+// useMesaLock: mantiene abierto el WebSocket /ws/mesas, pide el bloqueo de
+// edición de la mesa seleccionada y lo renueva antes de que expire. El token
+// concedido se envía en la cabecera X-Mesa-Lock al registrar votos.
+export function useMesaLock(mesaId: string, accessToken?: string) {
+  const [state, setState] = useState<MesaLockState>({ status: "idle" });
+  const socketRef = useRef<WebSocket | null>(null);
+  const mesaRef = useRef(mesaId);
+  const tokenRef = useRef(accessToken);
+  const statusRef = useRef<MesaLockState["status"]>("idle");
+
+  const update = (next: MesaLockState) => {
+    statusRef.current = next.status;
+    setState(next);
+  };
+
+  const request = (ws: WebSocket | null, mesa: string) => {
+    if (!ws || !sendLockRequest(ws, mesa)) return;
+    update(mesa ? { status: "pending" } : { status: "idle" });
+  };
+
+  // Una conexión por sesión: el access token renovado se envía con auth en
+  // lugar de reconectar, lo que liberaría el bloqueo
+  const hasToken = Boolean(accessToken);
+  useEffect(() => {
+    if (!hasToken) return;
+
+    let closed = false;
+    let retry: ReturnType<typeof setTimeout> | undefined;
+
+    const connect = () => {
+      const ws = new WebSocket(lockSocketURL(), ["bearer", tokenRef.current ?? ""]);
+      socketRef.current = ws;
+
+      ws.onopen = () => request(ws, mesaRef.current);
+
+      ws.onmessage = (event) => {
+        let msg: any;
+        try {
+          msg = JSON.parse(event.data);
+        } catch {
+          return;
+        }
+
+        switch (msg.type) {
+          case "lock_granted":
+            if (msg.mesaId === mesaRef.current) {
+              update({ status: "granted", token: msg.token, expiresAt: msg.expiresAt });
+            }
+            break;
+          case "lock_denied":
+            if (msg.mesaId === mesaRef.current) {
+              update({ status: "denied", holder: msg.holder, message: msg.message });
+            }
+            break;
+          case "lock_expired":
+            // no se renovó a tiempo (p. ej. pestaña suspendida): se vuelve a pedir
+            if (msg.mesaId === mesaRef.current) request(ws, mesaRef.current);
+            break;
+          case "presence": {
+            // quien tenía la mesa la liberó: se reintenta
+            const taken = (msg.clients ?? []).some(
+              (c: any) => c.mesaId === mesaRef.current && c.locked,
+            );
+            if (statusRef.current === "denied" && !taken) request(ws, mesaRef.current);
+            break;
+          }
+          case "error":
+            update({ status: "error", message: msg.message ?? "Error en el bloqueo de la mesa" });
+            break;
+        }
+      };
+
+      ws.onclose = () => {
+        if (socketRef.current === ws) socketRef.current = null;
+        if (closed) return;
+        update({ status: "error", message: "Conexión de bloqueo perdida, reconectando..." });
+        retry = setTimeout(connect, RECONNECT_MS);
+      };
+    };
+
+    connect();
+    return () => {
+      closed = true;
+      clearTimeout(retry);
+      socketRef.current?.close();
+      socketRef.current = null;
+    };
+    // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, [hasToken]);
+
+  useEffect(() => {
+    tokenRef.current = accessToken;
+    const ws = socketRef.current;
+    if (accessToken && ws?.readyState === WebSocket.OPEN) {
+      ws.send(JSON.stringify({ type: "auth", token: accessToken }));
+    }
+  }, [accessToken]);
+
+  useEffect(() => {
+    mesaRef.current = mesaId;
+    request(socketRef.current, mesaId);
+    // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, [mesaId]);
+
+  const expiresAt = state.status === "granted" ? state.expiresAt : null;
+  useEffect(() => {
+    if (!expiresAt) return;
+    const delay = Math.max(new Date(expiresAt).getTime() - Date.now() - RENEW_MARGIN_MS, 1_000);
+    const timer = setTimeout(() => {
+      const ws = socketRef.current;
+      if (ws && mesaRef.current) sendLockRequest(ws, mesaRef.current);
+    }, delay);
+    return () => clearTimeout(timer);
+  }, [expiresAt]);
+
+  return {
+    state,
+    lockToken: state.status === "granted" ? state.token : undefined,
+  };
+}
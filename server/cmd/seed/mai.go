@@ -31,7 +31,9 @@ func seedAdmin(db *gorm.DB) error {
 		Name:     "Administrador1",
 		Email:    "admin@votos.com",
 		Password: hashedPassword,
-		Rol:      "ADMIN",
+		Rol:      models.RolSuperAdmin,
+		// la contraseña por defecto es conocida: se obliga a cambiarla
+		MustChangePassword: true,
 	}
 	return db.Create(&admin).Error
 }
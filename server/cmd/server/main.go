@@ -11,6 +11,7 @@ import (
 	"github.com/joho/godotenv"
 
 	"server/internal/config"
+	"server/internal/database/migrate"
 	"server/internal/database/seed"
 	"server/internal/middlewares"
 	"server/internal/models"
@@ -52,11 +53,18 @@ func main() {
 		logger.Log.Fatalf("❌ Error al inicializar el validador: %v", err)
 	}
 
-	app := fiber.New(fiber.Config{
+	fiberCfg := fiber.Config{
 		AppName:      "Inventario Server",
 		ErrorHandler: middlewares.JSONErrorHandler,
 		BodyLimit:    10 * 1024 * 1024,
-	})
+	}
+	if proxies := config.GetConfig().TrustedProxies; len(proxies) > 0 {
+		fiberCfg.TrustProxy = true
+		fiberCfg.ProxyHeader = fiber.HeaderXForwardedFor
+		fiberCfg.TrustProxyConfig = fiber.TrustProxyConfig{Proxies: proxies}
+	}
+
+	app := fiber.New(fiberCfg)
 
 	app.Use(middlewares.CORSMiddleware())
 	app.Use(middlewares.LoggerMiddleware())
@@ -129,13 +137,32 @@ func initDatabase() (wasCreated bool, wasReset bool, err error) {
 		return wasCreated, wasReset, fmt.Errorf("error conectando a la base de datos: %w", err)
 	}
 
+	if err := migrate.MigrateLegacy(config.DB); err != nil {
+		return wasCreated, wasReset, fmt.Errorf("error migrando datos existentes: %w", err)
+	}
+
 	err = config.DB.AutoMigrate(
 		&models.User{},
 		&models.Account{},
+		&models.RefreshToken{},
+		&models.RecoveryCode{},
+		&models.LoginAttempt{},
+		&models.Invitation{},
+		&models.Election{},
 		&models.Candidate{},
 		&models.Position{},
+		&models.Mesa{},
+		&models.Acta{},
+		&models.ActaPosition{},
 		&models.Vote{},
+		&models.VoteRevision{},
+		&models.Discrepancy{},
+		&models.VoteCapture{},
 		&models.Image{},
+		&models.ResultCertificate{},
+		&models.AuditEvent{},
+		&models.Webhook{},
+		&models.WebhookDelivery{},
 	)
 
 	if err != nil {
@@ -0,0 +1,66 @@
+// cmd/verify/main.go
+//
+// Verificador offline de resultados certificados. No necesita la API ni la
+// base de datos: basta con el documento JSON, su firma y la clave pública.
+//
+//	go run ./cmd/verify -doc resultados.json -sig resultados.sig -pub certificacion.pub
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"server/internal/dto"
+	"server/pkgs/security"
+)
+
+func main() {
+	docPath := flag.String("doc", "", "documento JSON de resultados certificados")
+	sigPath := flag.String("sig", "", "archivo con la firma en base64")
+	pubPath := flag.String("pub", "", "clave pública Ed25519 (PEM o base64)")
+	flag.Parse()
+
+	if *docPath == "" || *sigPath == "" || *pubPath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	doc, err := os.ReadFile(*docPath)
+	exitOnError("leyendo documento", err)
+	sig, err := os.ReadFile(*sigPath)
+	exitOnError("leyendo firma", err)
+	pubData, err := os.ReadFile(*pubPath)
+	exitOnError("leyendo clave pública", err)
+
+	pub, err := security.ParseEd25519PublicKey(pubData)
+	exitOnError("clave pública", err)
+
+	if err := security.VerifyDocument(pub, doc, string(sig)); err != nil {
+		if errors.Is(err, security.ErrSignatureInvalid) {
+			fmt.Println("❌ NO AUTÉNTICO:", err)
+			os.Exit(1)
+		}
+		exitOnError("verificando", err)
+	}
+
+	canonical, _ := security.CanonicalJSON(doc)
+	var info dto.CertifiedResultsDocument
+	_ = json.Unmarshal(doc, &info)
+
+	fmt.Println("✅ AUTÉNTICO: la firma corresponde al documento y a la clave")
+	fmt.Printf("   elección:    %s (%s)\n", info.ElectionName, info.ElectionID)
+	fmt.Printf("   certificado: %s\n", info.CertifiedAt)
+	fmt.Printf("   clave:       %s\n", security.KeyFingerprint(pub))
+	fmt.Printf("   sha256:      %s\n", security.DocumentHash(canonical))
+}
+
+func exitOnError(what string, err error) {
+	if err == nil {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "error %s: %v\n", what, err)
+	os.Exit(2)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"server/pkgs/security"
+)
+
+// TestMain: con VERIFY_RUN_MAIN el binario de test se comporta como el
+// comando, para poder comprobar su código de salida
+func TestMain(m *testing.M) {
+	if os.Getenv("VERIFY_RUN_MAIN") == "1" {
+		os.Args = append([]string{"verify"}, strings.Fields(os.Getenv("VERIFY_ARGS"))...)
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+func TestVerifyCommand(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc := []byte(`{"electionId":"e1","electionName":"Rectorado","certifiedAt":"2026-10-18T12:00:00Z","positions":[{"id":"p1","votes":10}]}`)
+	_, sig, err := security.NewDocumentSigner(priv).Sign(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	pubPath := write("cert.pub", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	sigPath := write("doc.sig", []byte(sig+"\n"))
+	// el documento publicado se reformatea: la firma sigue valiendo
+	reformatted := write("reformatted.json", []byte("{\n  \"positions\": [{\"votes\": 10, \"id\": \"p1\"}],\n  \"certifiedAt\": \"2026-10-18T12:00:00Z\",\n  \"electionName\": \"Rectorado\",\n  \"electionId\": \"e1\"\n}\n"))
+	tampered := write("tampered.json", []byte(strings.Replace(string(doc), `"votes":10`, `"votes":11`, 1)))
+
+	tests := []struct {
+		name     string
+		args     string
+		wantCode int
+		wantOut  string
+	}{
+		{"reformateado auténtico", "-doc " + reformatted + " -sig " + sigPath + " -pub " + pubPath, 0, "AUTÉNTICO"},
+		{"documento alterado", "-doc " + tampered + " -sig " + sigPath + " -pub " + pubPath, 1, "NO AUTÉNTICO"},
+		{"sin argumentos", "", 2, ""},
+		{"clave inválida", "-doc " + reformatted + " -sig " + sigPath + " -pub " + sigPath, 2, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(os.Args[0])
+			cmd.Env = append(os.Environ(), "VERIFY_RUN_MAIN=1", "VERIFY_ARGS="+tt.args)
+			out, _ := cmd.CombinedOutput()
+
+			if code := cmd.ProcessState.ExitCode(); code != tt.wantCode {
+				t.Fatalf("código %d, want %d\n%s", code, tt.wantCode, out)
+			}
+			if !strings.Contains(string(out), tt.wantOut) {
+				t.Errorf("salida sin %q:\n%s", tt.wantOut, out)
+			}
+		})
+	}
+}
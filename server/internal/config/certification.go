@@ -0,0 +1,31 @@
+package config
+
+import (
+	"sync"
+
+	"server/pkgs/security"
+)
+
+var (
+	certSigner    *security.DocumentSigner
+	certSignerErr error
+	certOnce      sync.Once
+)
+
+// GetCertificationSigner: firmante de los resultados certificados. Devuelve
+// nil sin error si CERT_SIGNING_KEY_FILE no está configurado.
+func GetCertificationSigner() (*security.DocumentSigner, error) {
+	certOnce.Do(func() {
+		path := GetConfig().CertSigningKeyFile
+		if path == "" {
+			return
+		}
+		priv, err := readEd25519PrivateKey(path, "de certificación")
+		if err != nil {
+			certSignerErr = err
+			return
+		}
+		certSigner = security.NewDocumentSigner(priv)
+	})
+	return certSigner, certSignerErr
+}
@@ -2,7 +2,10 @@ package config
 
 import (
 	"os"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 )
 
 type AppConfig struct {
@@ -14,6 +17,79 @@ type AppConfig struct {
 	DBName     string
 	DBSSLMode  string
 	JWTSecret  string
+
+	// Claves JWT: la activa firma (HS256 con JWTSecret o EdDSA con la clave
+	// privada PEM) y las anteriores, "kid=secreto" o "kid=@ruta/publica.pem",
+	// se siguen aceptando hasta que expiren los tokens emitidos con ellas
+	JWTAlgorithm      string
+	JWTKeyID          string
+	JWTPrivateKeyFile string
+	JWTPreviousKeys   string
+	JWTIssuer         string
+	JWTAudience       string
+
+	// DoubleEntry: cada voto debe ser digitado por dos usuarios distintos
+	DoubleEntry bool
+
+	// Login con Google: client ID esperado como audiencia y origen del JWKS.
+	// GoogleJWKSFile tiene prioridad sobre la URL (útil para pruebas).
+	GoogleClientID string
+	GoogleJWKSURL  string
+	GoogleJWKSFile string
+
+	// Vida de los tokens: el access token es corto y se renueva con el refresh token
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+
+	// MFARequiredRoles: roles que deben tener 2FA activo para iniciar sesión
+	// (p. ej. "SUPERADMIN,ADMIN,SUPERVISOR")
+	MFARequiredRoles []string
+
+	// Protección de /auth/signin: intentos por IP y por email dentro de la
+	// ventana, y bloqueo de la cuenta tras LoginMaxFailures fallos seguidos
+	LoginWindow       time.Duration
+	LoginIPLimit      int
+	LoginEmailLimit   int
+	LoginMaxFailures  int
+	LoginLockDuration time.Duration
+
+	// TrustedProxies: IPs/CIDR de proxies (p. ej. el servidor Next.js) cuyo
+	// X-Forwarded-For se usa como IP del cliente
+	TrustedProxies []string
+
+	// Política de contraseñas: longitud mínima y lista local de contraseñas
+	// comunes o filtradas que se rechazan
+	PasswordMinLength     int
+	PasswordBlocklistFile string
+
+	// InviteTTL: validez de los enlaces de invitación para usuarios nuevos
+	InviteTTL time.Duration
+
+	// CertSigningKeyFile: clave privada Ed25519 (PKCS#8 PEM) con la que se
+	// firman los resultados certificados; sin ella no se puede certificar
+	CertSigningKeyFile string
+
+	// Stream de resultados (SSE): ventana en la que se agrupan los votos antes
+	// de recalcular y periodo del heartbeat que mantiene viva la conexión
+	ResultsStreamCoalesce  time.Duration
+	ResultsStreamHeartbeat time.Duration
+
+	// Bloqueo de edición de mesas (WebSocket): duración del bloqueo si el
+	// cliente no lo renueva y si POST /votes y el acta exigen tenerlo
+	// (activo salvo MESA_LOCK_REQUIRED=false)
+	MesaLockTTL      time.Duration
+	MesaLockRequired bool
+
+	// Webhooks: timeout de cada entrega, cada cuánto se revisa la cola y
+	// reintentos con backoff exponencial desde WebhookRetryBase hasta
+	// WebhookRetryMax; tras WebhookMaxAttempts la entrega queda DEAD.
+	// WebhookAllowPrivate permite receptores en direcciones internas.
+	WebhookTimeout      time.Duration
+	WebhookPollInterval time.Duration
+	WebhookRetryBase    time.Duration
+	WebhookRetryMax     time.Duration
+	WebhookMaxAttempts  int
+	WebhookAllowPrivate bool
 }
 
 var (
@@ -31,7 +107,53 @@ func LoadConfig() {
 			DBPassword: getEnv("DB_PASSWORD", ""),
 			DBName:     getEnv("DB_NAME", "votaciones"),
 			DBSSLMode:  getEnv("DB_SSLMODE", "disable"),
-			JWTSecret:  getEnv("JWT_SECRET", "mydefaultsecret"),
+			JWTSecret:  getEnv("JWT_SECRET", ""),
+
+			JWTAlgorithm:      getEnv("JWT_ALGORITHM", "HS256"),
+			JWTKeyID:          getEnv("JWT_KEY_ID", "default"),
+			JWTPrivateKeyFile: getEnv("JWT_PRIVATE_KEY_FILE", ""),
+			JWTPreviousKeys:   getEnv("JWT_PREVIOUS_KEYS", ""),
+			JWTIssuer:         getEnv("JWT_ISSUER", "conteovotos"),
+			JWTAudience:       getEnv("JWT_AUDIENCE", "conteovotos-api"),
+
+			DoubleEntry: getEnv("DOUBLE_ENTRY", "false") == "true",
+
+			GoogleClientID: getEnv("GOOGLE_CLIENT_ID", ""),
+			GoogleJWKSURL:  getEnv("GOOGLE_JWKS_URL", ""),
+			GoogleJWKSFile: getEnv("GOOGLE_JWKS_FILE", ""),
+
+			AccessTokenTTL:  getEnvDuration("ACCESS_TOKEN_TTL", 15*time.Minute),
+			RefreshTokenTTL: getEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+
+			MFARequiredRoles: getEnvList("MFA_REQUIRED_ROLES"),
+
+			LoginWindow:       getEnvDuration("LOGIN_WINDOW", 15*time.Minute),
+			LoginIPLimit:      getEnvInt("LOGIN_IP_LIMIT", 30),
+			LoginEmailLimit:   getEnvInt("LOGIN_EMAIL_LIMIT", 10),
+			LoginMaxFailures:  getEnvInt("LOGIN_MAX_FAILURES", 5),
+			LoginLockDuration: getEnvDuration("LOGIN_LOCK_DURATION", 15*time.Minute),
+
+			TrustedProxies: getEnvList("TRUSTED_PROXIES"),
+
+			PasswordMinLength:     getEnvInt("PASSWORD_MIN_LENGTH", 8),
+			PasswordBlocklistFile: getEnv("PASSWORD_BLOCKLIST_FILE", ""),
+
+			InviteTTL: getEnvDuration("INVITE_TTL", 72*time.Hour),
+
+			CertSigningKeyFile: getEnv("CERT_SIGNING_KEY_FILE", ""),
+
+			ResultsStreamCoalesce:  getEnvDuration("RESULTS_STREAM_COALESCE", 250*time.Millisecond),
+			ResultsStreamHeartbeat: getEnvDuration("RESULTS_STREAM_HEARTBEAT", 15*time.Second),
+
+			MesaLockTTL:      getEnvDuration("MESA_LOCK_TTL", time.Minute),
+			MesaLockRequired: getEnv("MESA_LOCK_REQUIRED", "true") == "true",
+
+			WebhookTimeout:      getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+			WebhookPollInterval: getEnvDuration("WEBHOOK_POLL_INTERVAL", 2*time.Second),
+			WebhookRetryBase:    getEnvDuration("WEBHOOK_RETRY_BASE", 30*time.Second),
+			WebhookRetryMax:     getEnvDuration("WEBHOOK_RETRY_MAX", time.Hour),
+			WebhookMaxAttempts:  getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+			WebhookAllowPrivate: getEnv("WEBHOOK_ALLOW_PRIVATE", "false") == "true",
 		}
 	})
 }
@@ -49,3 +171,39 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvDuration: acepta el formato de time.ParseDuration (p. ej. "15m", "720h")
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	val, ok := os.LookupEnv(key)
+	if !ok {
+		return defaultValue
+	}
+	d, err := time.ParseDuration(val)
+	if err != nil || d <= 0 {
+		return defaultValue
+	}
+	return d
+}
+
+// getEnvList: lista separada por comas, sin espacios ni elementos vacíos
+func getEnvList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, strings.ToUpper(item))
+		}
+	}
+	return list
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	val, ok := os.LookupEnv(key)
+	if !ok {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(val)
+	if err != nil || n <= 0 {
+		return defaultValue
+	}
+	return n
+}
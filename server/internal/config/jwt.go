@@ -0,0 +1,77 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"server/pkgs/security"
+)
+
+var (
+	jwtService *security.JWTService
+	jwtErr     error
+	jwtOnce    sync.Once
+)
+
+// GetJWTService: emisor y verificador de access tokens construido a partir de
+// la configuración; se inicializa una sola vez
+func GetJWTService() (*security.JWTService, error) {
+	jwtOnce.Do(func() {
+		jwtService, jwtErr = buildJWTService(GetConfig())
+	})
+	return jwtService, jwtErr
+}
+
+func buildJWTService(c *AppConfig) (*security.JWTService, error) {
+	var active security.JWTKey
+	switch strings.ToUpper(c.JWTAlgorithm) {
+	case "HS256":
+		if c.JWTSecret == "" {
+			return nil, fmt.Errorf("JWT_SECRET no configurado")
+		}
+		active = security.NewHMACKey(c.JWTKeyID, c.JWTSecret)
+	case "EDDSA":
+		priv, err := readEd25519PrivateKey(c.JWTPrivateKeyFile, "JWT")
+		if err != nil {
+			return nil, err
+		}
+		active = security.NewEd25519Key(c.JWTKeyID, priv)
+	default:
+		return nil, fmt.Errorf("JWT_ALGORITHM no soportado: %s", c.JWTAlgorithm)
+	}
+
+	previous, err := parsePreviousKeys(c.JWTPreviousKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	return security.NewJWTService(c.JWTIssuer, c.JWTAudience, c.AccessTokenTTL, active, previous...)
+}
+
+// parsePreviousKeys: "kid1=secreto,kid2=@/ruta/publica.pem"
+func parsePreviousKeys(spec string) ([]security.JWTKey, error) {
+	var keys []security.JWTKey
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		kid, value, ok := strings.Cut(entry, "=")
+		if !ok || kid == "" || value == "" {
+			return nil, fmt.Errorf("JWT_PREVIOUS_KEYS inválido: %q", entry)
+		}
+
+		if path, isFile := strings.CutPrefix(value, "@"); isFile {
+			pub, err := readEd25519PublicKey(path, "JWT")
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, security.NewEd25519VerifyKey(kid, pub))
+			continue
+		}
+		keys = append(keys, security.NewHMACKey(kid, value))
+	}
+	return keys, nil
+}
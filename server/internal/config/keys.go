@@ -0,0 +1,46 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"os"
+
+	"server/pkgs/security"
+)
+
+// readEd25519PrivateKey: clave privada PKCS#8 en PEM; label indica su uso en los errores
+func readEd25519PrivateKey(path, label string) (ed25519.PrivateKey, error) {
+	data, err := readKeyFile(path, label)
+	if err != nil {
+		return nil, err
+	}
+	priv, err := security.ParseEd25519PrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("clave privada %s %s: %w", label, path, err)
+	}
+	return priv, nil
+}
+
+// readEd25519PublicKey: clave pública PKIX en PEM
+func readEd25519PublicKey(path, label string) (ed25519.PublicKey, error) {
+	data, err := readKeyFile(path, label)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := security.ParseEd25519PublicKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("clave pública %s %s: %w", label, path, err)
+	}
+	return pub, nil
+}
+
+func readKeyFile(path, label string) ([]byte, error) {
+	if path == "" {
+		return nil, fmt.Errorf("ruta de clave %s no configurada", label)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("leyendo clave %s: %w", label, err)
+	}
+	return data, nil
+}
@@ -0,0 +1,23 @@
+package config
+
+import (
+	"sync"
+
+	"server/pkgs/security"
+)
+
+var (
+	passwordPolicy    *security.PasswordPolicy
+	passwordPolicyErr error
+	passwordOnce      sync.Once
+)
+
+// GetPasswordPolicy: política de contraseñas compartida por el login y la
+// gestión de usuarios; la lista de prohibidas se lee una sola vez
+func GetPasswordPolicy() (*security.PasswordPolicy, error) {
+	passwordOnce.Do(func() {
+		c := GetConfig()
+		passwordPolicy, passwordPolicyErr = security.NewPasswordPolicy(c.PasswordMinLength, c.PasswordBlocklistFile)
+	})
+	return passwordPolicy, passwordPolicyErr
+}
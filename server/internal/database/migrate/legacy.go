@@ -0,0 +1,147 @@
+package migrate
+
+import (
+	"fmt"
+
+	"server/internal/models"
+	"server/pkgs/logger"
+
+	"gorm.io/gorm"
+)
+
+// legacyElectionName: elección a la que se asignan los datos creados antes de
+// que existieran las elecciones
+const legacyElectionName = "Elección histórica"
+
+// legacySteps: migraciones de datos que AutoMigrate no sabe hacer. Cada paso
+// detecta por sí mismo si la base ya está al día.
+var legacySteps = []func(tx *gorm.DB) error{
+	legacyElectionScope,
+	legacyVoteMesa,
+}
+
+// MigrateLegacy: se ejecuta antes de AutoMigrate para que las columnas not
+// null nuevas encuentren las filas existentes ya completadas
+func MigrateLegacy(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, step := range legacySteps {
+			if err := step(tx); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// legacyElectionScope: positions y mesas anteriores a las elecciones pasan a
+// la elección histórica antes de exigir election_id. Los únicos globales de
+// nombre y número se eliminan; AutoMigrate crea luego los únicos por elección.
+func legacyElectionScope(tx *gorm.DB) error {
+	for _, table := range []string{"positions", "mesas"} {
+		if !tx.Migrator().HasTable(table) || tx.Migrator().HasColumn(table, "election_id") {
+			continue
+		}
+
+		if err := tx.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN election_id uuid`, table)).Error; err != nil {
+			return fmt.Errorf("no se pudo agregar election_id a %s: %w", table, err)
+		}
+
+		var rows int64
+		if err := tx.Table(table).Count(&rows).Error; err != nil {
+			return err
+		}
+		if rows > 0 {
+			electionID, err := legacyElection(tx)
+			if err != nil {
+				return err
+			}
+			if err := tx.Exec(fmt.Sprintf(`UPDATE %s SET election_id = ? WHERE election_id IS NULL`, table), electionID).Error; err != nil {
+				return fmt.Errorf("no se pudo asignar la elección histórica a %s: %w", table, err)
+			}
+			logger.Log.Infof("Migración: %d filas de %s asignadas a '%s'", rows, table, legacyElectionName)
+		}
+
+		if err := tx.Exec(fmt.Sprintf(`ALTER TABLE %s ALTER COLUMN election_id SET NOT NULL`, table)).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, stmt := range []string{
+		`ALTER TABLE IF EXISTS positions DROP CONSTRAINT IF EXISTS uni_positions_name`,
+		`ALTER TABLE IF EXISTS positions DROP CONSTRAINT IF EXISTS positions_name_key`,
+		`DROP INDEX IF EXISTS idx_mesas_number`,
+	} {
+		if err := tx.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("no se pudo eliminar un único global: %w", err)
+		}
+	}
+	return nil
+}
+
+// legacyVoteMesa: votes.mesa era el número de mesa en texto libre. Cada valor
+// distinto pasa a ser una Mesa contada de la elección histórica, votes.mesa_id
+// se completa con ella y la columna vieja se elimina.
+func legacyVoteMesa(tx *gorm.DB) error {
+	if !tx.Migrator().HasTable("votes") || !tx.Migrator().HasColumn("votes", "mesa") {
+		return nil
+	}
+
+	if err := tx.Exec(`ALTER TABLE votes ADD COLUMN IF NOT EXISTS mesa_id uuid`).Error; err != nil {
+		return fmt.Errorf("no se pudo agregar mesa_id a votes: %w", err)
+	}
+
+	var pending int64
+	if err := tx.Table("votes").Where("mesa_id IS NULL").Count(&pending).Error; err != nil {
+		return err
+	}
+	if pending > 0 {
+		electionID, err := legacyElection(tx)
+		if err != nil {
+			return err
+		}
+		if err := tx.AutoMigrate(&models.Mesa{}); err != nil {
+			return fmt.Errorf("AutoMigrate %T: %w", &models.Mesa{}, err)
+		}
+
+		if err := tx.Exec(`
+			INSERT INTO mesas (id, election_id, number, status, created_at, updated_at)
+			SELECT gen_random_uuid(), ?, legacy.mesa, ?, now(), now()
+			FROM (SELECT DISTINCT UPPER(TRIM(mesa)) AS mesa FROM votes WHERE mesa_id IS NULL) AS legacy
+			ON CONFLICT (election_id, number) DO NOTHING`,
+			electionID, models.MScounted,
+		).Error; err != nil {
+			return fmt.Errorf("no se pudieron crear las mesas históricas: %w", err)
+		}
+
+		if err := tx.Exec(`
+			UPDATE votes SET mesa_id = m.id
+			FROM mesas m
+			WHERE m.election_id = ? AND m.number = UPPER(TRIM(votes.mesa)) AND votes.mesa_id IS NULL`,
+			electionID,
+		).Error; err != nil {
+			return fmt.Errorf("no se pudo asignar mesa_id a votes: %w", err)
+		}
+		logger.Log.Infof("Migración: %d votos asignados a mesas de '%s'", pending, legacyElectionName)
+	}
+
+	if err := tx.Exec(`ALTER TABLE votes ALTER COLUMN mesa_id SET NOT NULL`).Error; err != nil {
+		return err
+	}
+	if err := tx.Exec(`ALTER TABLE votes DROP COLUMN mesa`).Error; err != nil {
+		return fmt.Errorf("no se pudo eliminar votes.mesa: %w", err)
+	}
+	return nil
+}
+
+// legacyElection: id de la elección histórica, creándola cerrada si no existe
+func legacyElection(tx *gorm.DB) (string, error) {
+	if err := tx.AutoMigrate(&models.Election{}); err != nil {
+		return "", fmt.Errorf("AutoMigrate %T: %w", &models.Election{}, err)
+	}
+
+	election := models.Election{Name: legacyElectionName, Status: models.ESclosed}
+	if err := tx.Where("name = ?", legacyElectionName).FirstOrCreate(&election).Error; err != nil {
+		return "", fmt.Errorf("no se pudo crear la elección histórica: %w", err)
+	}
+	return election.ID, nil
+}
@@ -17,21 +17,51 @@ func modelOrderUp() []any {
 	return []any{
 		&models.User{},
 		&models.Account{},
+		&models.RefreshToken{},
+		&models.RecoveryCode{},
+		&models.LoginAttempt{},
+		&models.Invitation{},
+		&models.Election{},
 		&models.Candidate{},
 		&models.Position{},
+		&models.Mesa{},
+		&models.Acta{},
+		&models.ActaPosition{},
 		&models.Vote{},
+		&models.VoteRevision{},
+		&models.Discrepancy{},
+		&models.VoteCapture{},
 		&models.Image{},
+		&models.ResultCertificate{},
+		&models.AuditEvent{},
+		&models.Webhook{},
+		&models.WebhookDelivery{},
 	}
 }
 
 // Orden inverso para eliminar tablas correctamente
 func modelOrderDown() []any {
 	return []any{
+		&models.WebhookDelivery{},
+		&models.Webhook{},
+		&models.AuditEvent{},
+		&models.RefreshToken{},
+		&models.RecoveryCode{},
+		&models.LoginAttempt{},
+		&models.Invitation{},
 		&models.User{},
 		&models.Account{},
 		&models.Candidate{},
 		&models.Position{},
+		&models.VoteCapture{},
+		&models.Discrepancy{},
+		&models.VoteRevision{},
 		&models.Vote{},
+		&models.ActaPosition{},
+		&models.Acta{},
+		&models.Mesa{},
+		&models.ResultCertificate{},
+		&models.Election{},
 		&models.Image{},
 	}
 }
@@ -42,6 +72,10 @@ func migrateUp(db *gorm.DB) error {
 		return fmt.Errorf("no se pudo habilitar pgcrypto: %w", err)
 	}
 
+	if err := MigrateLegacy(db); err != nil {
+		return fmt.Errorf("migración de datos existentes: %w", err)
+	}
+
 	for _, m := range modelOrderUp() {
 		if err := db.AutoMigrate(m); err != nil {
 			return fmt.Errorf("AutoMigrate %T: %w", m, err)
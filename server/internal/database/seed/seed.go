@@ -25,7 +25,9 @@ func seedAdmin(db *gorm.DB) error {
 		Name:     "Administrador",
 		Email:    "admin@votos.com",
 		Password: hashedPassword,
-		Rol:      "ADMIN",
+		Rol:      models.RolSuperAdmin,
+		// la contraseña por defecto es conocida: se obliga a cambiarla
+		MustChangePassword: true,
 	}
 	return db.Create(&admin).Error
 }
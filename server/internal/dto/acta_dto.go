@@ -0,0 +1,39 @@
+package dto
+
+type ActaCandidateCount struct {
+	CandidateID string `json:"candidateId" validate:"required"`
+	TotalVotes  int    `json:"totalVotes" validate:"min=0"`
+}
+
+type ActaPositionRequest struct {
+	PositionID string               `json:"positionId" validate:"required"`
+	BlankVotes int                  `json:"blankVotes" validate:"min=0"`
+	NullVotes  int                  `json:"nullVotes" validate:"min=0"`
+	Votes      []ActaCandidateCount `json:"votes" validate:"required,dive"`
+}
+
+type SubmitActaRequest struct {
+	TypeVote    string                `json:"typeVote" validate:"required,oneof=DOCENTES PUBLICO"`
+	BallotsCast int                   `json:"ballotsCast" validate:"min=0"`
+	Positions   []ActaPositionRequest `json:"positions" validate:"required,dive"`
+	// LockToken: bloqueo de edición de la mesa, llega en la cabecera X-Mesa-Lock
+	LockToken string `json:"-"`
+}
+
+type ActaPositionResponse struct {
+	PositionID   string         `json:"positionId"`
+	PositionName string         `json:"positionName"`
+	BlankVotes   int            `json:"blankVotes"`
+	NullVotes    int            `json:"nullVotes"`
+	Votes        []VoteResponse `json:"votes"`
+}
+
+type ActaResponse struct {
+	ID          string                 `json:"id"`
+	MesaID      string                 `json:"mesaId"`
+	Mesa        string                 `json:"mesa"`
+	TypeVote    string                 `json:"typeVote"`
+	BallotsCast int                    `json:"ballotsCast"`
+	CreatedAt   string                 `json:"createdAt"`
+	Positions   []ActaPositionResponse `json:"positions"`
+}
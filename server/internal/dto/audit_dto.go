@@ -0,0 +1,42 @@
+package dto
+
+import "encoding/json"
+
+// AuditFilter: filtros de GET /audit; From y To en RFC3339
+type AuditFilter struct {
+	EntityType string
+	EntityID   string
+	ActorID    string
+	Action     string
+	From       string
+	To         string
+	Limit      int
+}
+
+type AuditEventResponse struct {
+	ID         string          `json:"id"`
+	Seq        int64           `json:"seq"`
+	CreatedAt  string          `json:"createdAt"`
+	ActorID    string          `json:"actorId"`
+	ActorRole  string          `json:"actorRole"`
+	IP         string          `json:"ip"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entityType"`
+	EntityID   string          `json:"entityId"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	PrevHash   string          `json:"prevHash"`
+	Hash       string          `json:"hash"`
+}
+
+// AuditVerifyResponse: resultado de recorrer la cadena; si Valid es false,
+// BrokenSeq y Reason indican el primer eslabón roto
+type AuditVerifyResponse struct {
+	Valid     bool   `json:"valid"`
+	Checked   int64  `json:"checked"`
+	LastSeq   int64  `json:"lastSeq"`
+	LastHash  string `json:"lastHash"`
+	BrokenSeq *int64 `json:"brokenSeq,omitempty"`
+	BrokenID  string `json:"brokenId,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
@@ -1,15 +1,61 @@
 package dto
 
 type SigninRequest struct {
-	Email    string  `json:"email"`    
-	Password string  `json:"password"` 
-	Provider *string `json:"provider,omitempty"` 
+	Email    string  `json:"email"`
+	Password string  `json:"password"`
+	Provider *string `json:"provider,omitempty"`
+	IDToken  string  `json:"idToken,omitempty"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"currentPassword" validate:"required"`
+	NewPassword     string `json:"newPassword" validate:"required"`
+}
+
+type AcceptInviteRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" validate:"required"`
 }
 
 type AuthResponse struct {
-	ID    string  `json:"id"`  
-	Email string  `json:"email"`
-	Name  *string `json:"name"` 
-	Role  string  `json:"role"`
-	Token string  `json:"token"`   
+	ID           string  `json:"id"`
+	Email        string  `json:"email"`
+	Name         *string `json:"name"`
+	Role         string  `json:"role"`
+	Token        string  `json:"token,omitempty"`
+	ExpiresAt    int64   `json:"expiresAt,omitempty"`
+	RefreshToken string  `json:"refreshToken,omitempty"`
+
+	// El token solo permite POST /auth/password hasta que se cambie
+	MustChangePassword bool `json:"mustChangePassword,omitempty"`
+
+	// Si el usuario usa 2FA no se emiten tokens: MFAStatus es "mfa_required"
+	// o "mfa_enrollment_required" y MFAToken es el challenge a canjear
+	MFAStatus string `json:"mfaStatus,omitempty"`
+	MFAToken  string `json:"mfaToken,omitempty"`
+}
+
+type MFAEnrollResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+type MFACodeRequest struct {
+	Code         string `json:"code,omitempty"`
+	RecoveryCode string `json:"recoveryCode,omitempty"`
+}
+
+type MFAVerifyResponse struct {
+	RecoveryCodes []string      `json:"recoveryCodes"`
+	Auth          *AuthResponse `json:"auth,omitempty"`
+}
+
+type MFAChallengeRequest struct {
+	MFAToken     string `json:"mfaToken" validate:"required"`
+	Code         string `json:"code,omitempty"`
+	RecoveryCode string `json:"recoveryCode,omitempty"`
 }
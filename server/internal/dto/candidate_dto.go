@@ -9,6 +9,7 @@ type CreateCandidateRequest struct {
 	IsActive      *bool                 `json:"isActive,omitempty"`
 	PositionID    string                `json:"positionId,omitempty"`
 	TypeCandidate models.TypeCandidates `json:"typeCandidate" validate:"required"`
+	Party         *string               `json:"party,omitempty"`
 }
 
 type UpdateCandidateRequest struct {
@@ -17,6 +18,8 @@ type UpdateCandidateRequest struct {
 	ImageID     *string `json:"imageId,omitempty"`
 	IsActive    *bool   `json:"isActive,omitempty"`
 	PositionID  *string `json:"positionId,omitempty"`
+	// Party: "" borra el partido; nil lo deja igual
+	Party *string `json:"party,omitempty"`
 }
 
 type CandidateResponse struct {
@@ -27,12 +30,14 @@ type CandidateResponse struct {
 	ImageID       *string               `json:"imageId,omitempty"`
 	IsActive      bool                  `json:"isActive"`
 	TypeCandidate models.TypeCandidates `json:"typeCandidate"`
+	Party         *string               `json:"party,omitempty"`
 
 	Position PositionSimple `json:"position,omitempty"`
 }
 
 type PositionSimple struct {
 	ID           string `json:"id"`
+	ElectionID   string `json:"electionId,omitempty"`
 	Name         string `json:"name"`
 	TypePosition string `json:"typePosition"`
 }
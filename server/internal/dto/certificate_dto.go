@@ -0,0 +1,27 @@
+package dto
+
+import "encoding/json"
+
+// CertifiedResultsDocument: documento que se congela y firma al certificar.
+// Sus campos forman parte de lo firmado, así que no deben cambiar de nombre.
+type CertifiedResultsDocument struct {
+	Type         string          `json:"type"`
+	Version      int             `json:"version"`
+	ElectionID   string          `json:"electionId"`
+	ElectionName string          `json:"electionName"`
+	CertifiedAt  string          `json:"certifiedAt"`
+	CertifiedBy  string          `json:"certifiedBy"`
+	KeyID        string          `json:"keyId"`
+	Results      ResultsResponse `json:"results"`
+}
+
+type CertificateResponse struct {
+	ElectionID   string          `json:"electionId"`
+	Document     json.RawMessage `json:"document"`
+	DocumentHash string          `json:"documentHash"`
+	Signature    string          `json:"signature"`
+	KeyID        string          `json:"keyId"`
+	PublicKey    string          `json:"publicKey"`
+	CertifiedBy  string          `json:"certifiedBy"`
+	CertifiedAt  string          `json:"certifiedAt"`
+}
@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+type CreateElectionRequest struct {
+	Name        string     `json:"name" validate:"required"`
+	Description *string    `json:"description,omitempty"`
+	StartDate   *time.Time `json:"startDate,omitempty"`
+	EndDate     *time.Time `json:"endDate,omitempty"`
+}
+
+type UpdateElectionRequest struct {
+	Name        *string    `json:"name,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	StartDate   *time.Time `json:"startDate,omitempty"`
+	EndDate     *time.Time `json:"endDate,omitempty"`
+}
+
+type TransitionElectionRequest struct {
+	Status string `json:"status" validate:"required,oneof=DRAFT CONFIGURED OPEN CLOSED CERTIFIED"`
+}
+
+type ElectionResponse struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	Description *string `json:"description"`
+	StartDate   *string `json:"startDate"`
+	EndDate     *string `json:"endDate"`
+	Status      string  `json:"status"`
+	CreatedAt   string  `json:"createdAt"`
+	UpdatedAt   string  `json:"updatedAt"`
+}
+
+type ElectionSimple struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
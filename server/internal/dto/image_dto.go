@@ -7,4 +7,4 @@ type ImageResponse struct {
 	Name     string `json:"name"`
 	URL      string `json:"url"`
 	Size     int64  `json:"size,omitempty"`
-}
\ No newline at end of file
+}
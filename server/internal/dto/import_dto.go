@@ -0,0 +1,25 @@
+package dto
+
+// ImportRowError: Row es el número de fila de la hoja, contando el encabezado
+// como fila 1
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportItem: fila aplicada; ID solo existe si la importación se confirmó
+type ImportItem struct {
+	Row  int    `json:"row"`
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
+type ImportResult struct {
+	DryRun    bool             `json:"dryRun"`
+	Applied   bool             `json:"applied"`
+	TotalRows int              `json:"totalRows"`
+	Valid     int              `json:"valid"`
+	Items     []ImportItem     `json:"items"`
+	Errors    []ImportRowError `json:"errors"`
+}
@@ -0,0 +1,29 @@
+package dto
+
+type CreateMesaRequest struct {
+	ElectionID       string  `json:"electionId" validate:"required"`
+	Number           string  `json:"number" validate:"required"`
+	Location         *string `json:"location,omitempty"`
+	ElectorsDocentes int     `json:"electorsDocentes" validate:"min=0"`
+	ElectorsPublico  int     `json:"electorsPublico" validate:"min=0"`
+}
+
+type UpdateMesaRequest struct {
+	Number           *string `json:"number,omitempty"`
+	Location         *string `json:"location,omitempty"`
+	ElectorsDocentes *int    `json:"electorsDocentes,omitempty" validate:"omitempty,min=0"`
+	ElectorsPublico  *int    `json:"electorsPublico,omitempty" validate:"omitempty,min=0"`
+	Status           *string `json:"status,omitempty" validate:"omitempty,oneof=PENDING OPEN CLOSED COUNTED ANNULLED"`
+}
+
+type MesaResponse struct {
+	ID               string  `json:"id"`
+	ElectionID       string  `json:"electionId"`
+	Number           string  `json:"number"`
+	Location         *string `json:"location"`
+	ElectorsDocentes int     `json:"electorsDocentes"`
+	ElectorsPublico  int     `json:"electorsPublico"`
+	Status           string  `json:"status"`
+	CreatedAt        string  `json:"createdAt"`
+	UpdatedAt        string  `json:"updatedAt"`
+}
@@ -0,0 +1,50 @@
+package dto
+
+// Tipos de mensaje del WebSocket de edición de mesas
+const (
+	MesaWSLock    = "lock"
+	MesaWSRelease = "release"
+	MesaWSPing    = "ping"
+	MesaWSAuth    = "auth"
+
+	MesaWSWelcome     = "welcome"
+	MesaWSLockGranted = "lock_granted"
+	MesaWSLockDenied  = "lock_denied"
+	MesaWSLockExpired = "lock_expired"
+	MesaWSReleased    = "released"
+	MesaWSPresence    = "presence"
+	MesaWSPong        = "pong"
+	MesaWSError       = "error"
+)
+
+// MesaWSRequest: mensaje del cliente; lock anuncia la mesa que se edita (y
+// renueva el bloqueo si ya lo tiene), release la abandona y auth reemplaza el
+// access token de la conexión por uno renovado antes de que el actual expire
+type MesaWSRequest struct {
+	Type   string `json:"type"`
+	MesaID string `json:"mesaId,omitempty"`
+	Token  string `json:"token,omitempty"`
+}
+
+type MesaWSMessage struct {
+	Type      string         `json:"type"`
+	ClientID  string         `json:"clientId,omitempty"`
+	MesaID    string         `json:"mesaId,omitempty"`
+	Token     string         `json:"token,omitempty"`
+	ExpiresAt string         `json:"expiresAt,omitempty"`
+	Holder    *MesaPresence  `json:"holder,omitempty"`
+	Clients   []MesaPresence `json:"clients,omitempty"`
+	Message   string         `json:"message,omitempty"`
+}
+
+// MesaPresence: un cliente conectado y la mesa que tiene abierta; Locked
+// indica si es quien tiene el bloqueo de edición
+type MesaPresence struct {
+	ClientID  string `json:"clientId"`
+	UserID    string `json:"userId"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	MesaID    string `json:"mesaId,omitempty"`
+	Locked    bool   `json:"locked"`
+	ExpiresAt string `json:"expiresAt,omitempty"`
+}
@@ -1,6 +1,7 @@
 package dto
 
 type CreatePositionRequest struct {
+	ElectionID      string  `json:"electionId" validate:"required"`
 	Name            string  `json:"name" validate:"required"`
 	Description     *string `json:"description,omitempty"`
 	TypePosition    string  `json:"typePosition" validate:"required"`
@@ -18,6 +19,7 @@ type UpdatePositionRequest struct {
 
 type PositionResponse struct {
 	ID              string  `json:"id"`
+	ElectionID      string  `json:"electionId"`
 	Name            string  `json:"name"`
 	Description     *string `json:"description"`
 	TypePosition    string  `json:"typePosition"`
@@ -0,0 +1,25 @@
+package dto
+
+// ReportFile: informe generado en el servidor, listo para descargar
+type ReportFile struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+	// Hash: SHA-256 del ReportDocument canónico que se imprime en el pie
+	Hash        string
+	GeneratedAt string
+}
+
+// ReportDocument: datos de un informe. Su forma canónica es la que se hashea,
+// así que el hash del pie identifica los resultados y el momento de emisión
+// con independencia de cómo se maquetó el PDF.
+type ReportDocument struct {
+	Type           string                   `json:"type"`
+	ElectionID     string                   `json:"electionId"`
+	ElectionName   string                   `json:"electionName"`
+	ElectionStatus string                   `json:"electionStatus"`
+	GeneratedAt    string                   `json:"generatedAt"`
+	CountedMesas   int                      `json:"countedMesas"`
+	TotalMesas     int                      `json:"totalMesas"`
+	Positions      []PositionResultResponse `json:"positions"`
+}
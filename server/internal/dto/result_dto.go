@@ -0,0 +1,63 @@
+package dto
+
+type CandidateResult struct {
+	ID            string  `json:"id"`
+	Name          string  `json:"name"`
+	ImageID       *string `json:"imageId,omitempty"`
+	VotesDocentes int     `json:"votesDocentes"`
+	VotesPublico  int     `json:"votesPublico"`
+	Votes         float64 `json:"votes"`
+	Percentage    float64 `json:"percentage"`
+	IsWinner      bool    `json:"isWinner"`
+}
+
+type PositionResultResponse struct {
+	ElectionID      string            `json:"electionId"`
+	PositionID      string            `json:"positionId"`
+	PositionName    string            `json:"positionName"`
+	TypePosition    string            `json:"typePosition"`
+	TotalVotes      int               `json:"totalVotes"`
+	ValidPercentage float64           `json:"validPercentage"`
+	VotesDocentes   int               `json:"votesDocentes"`
+	VotesPublico    int               `json:"votesPublico"`
+	WeightFactor    float64           `json:"weightFactor"`
+	ValidVotes      int               `json:"validVotes"`
+	NullVotes       int               `json:"nullVotes"`
+	EmittedVotes    int               `json:"emittedVotes"`
+	IsTie           bool              `json:"isTie"`
+	Validity        ValidityResult    `json:"validity"`
+	Candidates      []CandidateResult `json:"candidates"`
+}
+
+type ValidityResult struct {
+	Status             string  `json:"status"`
+	Electorate         int     `json:"electorate"`
+	ValidVotes         int     `json:"validVotes"`
+	NullVotes          int     `json:"nullVotes"`
+	Turnout            float64 `json:"turnout"`
+	RequiredPercentage float64 `json:"requiredPercentage"`
+}
+
+type ResultsResponse struct {
+	TotalVotes      int                      `json:"totalVotes"`
+	TotalPositions  int                      `json:"totalPositions"`
+	TotalCandidates int                      `json:"totalCandidates"`
+	Positions       []PositionResultResponse `json:"positions"`
+}
+
+// ResultDelta: estado actualizado de una posición que cambió, enviado por
+// GET /results/stream. Los totales son absolutos, no incrementos.
+type ResultDelta struct {
+	ElectionID        string            `json:"electionId"`
+	PositionID        string            `json:"positionId"`
+	PositionName      string            `json:"positionName"`
+	Candidates        []CandidateResult `json:"candidates"`
+	ValidVotes        int               `json:"validVotes"`
+	NullVotes         int               `json:"nullVotes"`
+	EmittedVotes      int               `json:"emittedVotes"`
+	IsTie             bool              `json:"isTie"`
+	CountedMesas      int               `json:"countedMesas"`
+	TotalMesas        int               `json:"totalMesas"`
+	CountedPercentage float64           `json:"countedPercentage"`
+	UpdatedAt         string            `json:"updatedAt"`
+}
@@ -0,0 +1,43 @@
+package dto
+
+type CreateUserRequest struct {
+	Name     string  `json:"name" validate:"required"`
+	Email    string  `json:"email" validate:"required,email"`
+	Password string  `json:"password,omitempty"`
+	Rol      string  `json:"rol" validate:"required,oneof=SUPERADMIN ADMIN DIGITADOR SUPERVISOR PERSONERO OBSERVER"`
+	Party    *string `json:"party,omitempty"`
+}
+
+type UpdateUserRequest struct {
+	Name     *string `json:"name,omitempty"`
+	Email    *string `json:"email,omitempty" validate:"omitempty,email"`
+	Rol      *string `json:"rol,omitempty" validate:"omitempty,oneof=SUPERADMIN ADMIN DIGITADOR SUPERVISOR PERSONERO OBSERVER"`
+	Party    *string `json:"party,omitempty"`
+	IsActive *bool   `json:"isActive,omitempty"`
+}
+
+type ResetPasswordRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
+type UserResponse struct {
+	ID       string  `json:"id"`
+	Name     string  `json:"name"`
+	Email    string  `json:"email"`
+	Rol      string  `json:"rol"`
+	Party    *string `json:"party"`
+	IsActive bool    `json:"isActive"`
+	Locked   bool    `json:"locked"`
+
+	MustChangePassword bool `json:"mustChangePassword"`
+
+	// Solo presente al crear el usuario o reenviar la invitación
+	Invitation *InvitationResponse `json:"invitation,omitempty"`
+	CreatedAt  string              `json:"createdAt"`
+	UpdatedAt  string              `json:"updatedAt"`
+}
+
+type InvitationResponse struct {
+	Token     string `json:"token"`
+	ExpiresAt string `json:"expiresAt"`
+}
@@ -1,18 +1,75 @@
 package dto
 
 type CreateVoteRequest struct {
-	Mesa        string `json:"mesa" validate:"required,min=0"`
+	MesaID      string `json:"mesaId" validate:"required"`
 	CandidateID string `json:"candidateId" validate:"required"`
 	TotalVotes  int    `json:"totalVotes" validate:"required,min=0"`
 	TypeVote    string `json:"typeVote" validate:"required"`
+	// LockToken: bloqueo de edición de la mesa, llega en la cabecera X-Mesa-Lock
+	LockToken string `json:"-"`
 }
 
 type VoteResponse struct {
 	ID            string         `json:"id"`
+	MesaID        string         `json:"mesaId"`
 	Mesa          string         `json:"mesa"`
 	CandidateID   string         `json:"candidateId"`
 	CandidateName string         `json:"candidateName,omitempty"`
 	TotalVotes    int            `json:"totalVotes"`
 	TypeVote      string         `json:"typeVote" validate:"required"`
+	Revision      int            `json:"revision"`
+	IsAnnulled    bool           `json:"isAnnulled"`
+	Status        string         `json:"status"`
+	CaptureID     string         `json:"captureId,omitempty"`
 	Position      PositionSimple `json:"position,omitempty"`
 }
+
+type UpdateVoteRequest struct {
+	TotalVotes *int   `json:"totalVotes" validate:"required,min=0"`
+	Reason     string `json:"reason" validate:"required"`
+}
+
+type AnnulVoteRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+type VoteRevisionResponse struct {
+	ID           string `json:"id"`
+	VoteID       string `json:"voteId"`
+	Revision     int    `json:"revision"`
+	PreviousVote int    `json:"previousVote"`
+	NewVote      int    `json:"newVote"`
+	Action       string `json:"action"`
+	Reason       string `json:"reason"`
+	UserID       string `json:"userId"`
+	CreatedAt    string `json:"createdAt"`
+}
+
+type CaptureResponse struct {
+	ID         string `json:"id"`
+	UserID     string `json:"userId"`
+	TotalVotes int    `json:"totalVotes"`
+	Status     string `json:"status"`
+	CreatedAt  string `json:"createdAt"`
+}
+
+type DiscrepancyResponse struct {
+	ID            string            `json:"id"`
+	MesaID        string            `json:"mesaId"`
+	Mesa          string            `json:"mesa"`
+	CandidateID   string            `json:"candidateId"`
+	CandidateName string            `json:"candidateName"`
+	TypeVote      string            `json:"typeVote"`
+	Status        string            `json:"status"`
+	ResolvedBy    *string           `json:"resolvedBy,omitempty"`
+	ResolvedVote  *int              `json:"resolvedVote,omitempty"`
+	Reason        *string           `json:"reason,omitempty"`
+	VoteID        *string           `json:"voteId,omitempty"`
+	CreatedAt     string            `json:"createdAt"`
+	Captures      []CaptureResponse `json:"captures"`
+}
+
+type ResolveDiscrepancyRequest struct {
+	TotalVotes *int   `json:"totalVotes" validate:"required,min=0"`
+	Reason     string `json:"reason" validate:"required"`
+}
@@ -0,0 +1,61 @@
+package dto
+
+import "encoding/json"
+
+type CreateWebhookRequest struct {
+	Name     string   `json:"name" validate:"required"`
+	URL      string   `json:"url" validate:"required,url"`
+	Events   []string `json:"events,omitempty"`
+	IsActive *bool    `json:"isActive,omitempty"`
+}
+
+type UpdateWebhookRequest struct {
+	Name     *string  `json:"name,omitempty"`
+	URL      *string  `json:"url,omitempty" validate:"omitempty,url"`
+	Events   []string `json:"events,omitempty"`
+	IsActive *bool    `json:"isActive,omitempty"`
+}
+
+// WebhookResponse: Secret solo se devuelve al crear el webhook
+type WebhookResponse struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	IsActive  bool     `json:"isActive"`
+	Secret    string   `json:"secret,omitempty"`
+	CreatedAt string   `json:"createdAt"`
+	UpdatedAt string   `json:"updatedAt"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             string          `json:"id"`
+	WebhookID      string          `json:"webhookId"`
+	EventID        string          `json:"eventId"`
+	EventType      string          `json:"eventType"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  string          `json:"nextAttemptAt"`
+	LastStatusCode int             `json:"lastStatusCode,omitempty"`
+	LastError      *string         `json:"lastError,omitempty"`
+	DeliveredAt    *string         `json:"deliveredAt,omitempty"`
+	CreatedAt      string          `json:"createdAt"`
+}
+
+// WebhookTestResponse: resultado del ping, que se envía en el momento y no
+// pasa por la cola
+type WebhookTestResponse struct {
+	Delivered  bool   `json:"delivered"`
+	StatusCode int    `json:"statusCode,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+	Error      string `json:"error,omitempty"`
+}
+
+// WebhookEvent: cuerpo JSON de cada entrega
+type WebhookEvent struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt string      `json:"createdAt"`
+	Data      interface{} `json:"data"`
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"server/internal/dto"
+	"server/internal/services"
+	"server/pkgs/logger"
+)
+
+type ActaHandler struct {
+	service services.ActaService
+}
+
+func NewActaHandler(service services.ActaService) *ActaHandler {
+	return &ActaHandler{service: service}
+}
+
+func (h *ActaHandler) GetByMesa(c fiber.Ctx) (interface{}, string, error) {
+	userID, userRole, err := getAuthLocals(c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	actas, err := h.service.GetByMesa(c.Params("id"), userID, userRole)
+	if err != nil {
+		logger.Log.Errorf("❌ GetByMesa actas failed: %v", err)
+		return nil, err.Error(), actaError(err)
+	}
+
+	return actas, "Actas obtenidas correctamente", nil
+}
+
+func (h *ActaHandler) Submit(c fiber.Ctx) (interface{}, string, error) {
+	userID, userRole, err := getAuthLocals(c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var req dto.SubmitActaRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return nil, "Solicitud inválida", fiber.NewError(fiber.StatusBadRequest, "Body inválido: "+err.Error())
+	}
+	req.LockToken = c.Get("X-Mesa-Lock")
+
+	acta, err := h.service.Submit(c.Params("id"), req, userID, userRole, c.IP())
+	if err != nil {
+		logger.Log.Errorf("❌ Submit acta failed: %v", err)
+		return nil, err.Error(), actaError(err)
+	}
+
+	return acta, "Acta registrada correctamente", nil
+}
+
+func actaError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrMesaNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrUnauthorized), errors.Is(err, services.ErrPartyRequired):
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrActaAlreadySubmitted), errors.Is(err, services.ErrActaMismatch),
+		errors.Is(err, services.ErrCaptureAlreadySubmitted), errors.Is(err, services.ErrDiscrepancyPending):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrActaIncomplete),
+		errors.Is(err, services.ErrActaInvalid),
+		errors.Is(err, services.ErrActaExceedsElectors):
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	case errors.Is(err, services.ErrElectionLocked):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrMesaLockRequired):
+		return fiber.NewError(fiber.StatusLocked, err.Error())
+	default:
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+}
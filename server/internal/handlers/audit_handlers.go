@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"server/internal/dto"
+	"server/internal/services"
+	"server/pkgs/logger"
+)
+
+type AuditHandler struct {
+	service services.AuditService
+}
+
+func NewAuditHandler(service services.AuditService) *AuditHandler {
+	return &AuditHandler{service: service}
+}
+
+func (h *AuditHandler) GetAll(c fiber.Ctx) (interface{}, string, error) {
+	filter := dto.AuditFilter{
+		EntityType: c.Query("entityType"),
+		EntityID:   c.Query("entityId"),
+		ActorID:    c.Query("actorId"),
+		Action:     c.Query("action"),
+		From:       c.Query("from"),
+		To:         c.Query("to"),
+		Limit:      fiber.Query[int](c, "limit"),
+	}
+
+	events, err := h.service.GetAll(filter)
+	if err != nil {
+		logger.Log.Errorf("❌ GetAll audit events failed: %v", err)
+		if errors.Is(err, services.ErrInvalidAuditFilter) {
+			return nil, err.Error(), fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		return nil, err.Error(), fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return events, "Eventos de auditoría obtenidos correctamente", nil
+}
+
+func (h *AuditHandler) Verify(c fiber.Ctx) (interface{}, string, error) {
+	result, err := h.service.Verify()
+	if err != nil {
+		logger.Log.Errorf("❌ Verify audit chain failed: %v", err)
+		return nil, err.Error(), fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	if !result.Valid {
+		logger.Log.Warnf("⚠️ Cadena de auditoría rota en el evento %d: %s", *result.BrokenSeq, result.Reason)
+		return result, "La cadena de auditoría está rota", nil
+	}
+
+	return result, "Cadena de auditoría íntegra", nil
+}
@@ -6,6 +6,8 @@ import (
 	"server/internal/dto"
 	"server/internal/services"
 	"server/pkgs/logger"
+	"server/pkgs/security"
+	"strconv"
 
 	"github.com/gofiber/fiber/v3"
 )
@@ -18,6 +20,18 @@ func NewAuthHandler(authService services.AuthService) *AuthHandler {
 	return &AuthHandler{authService: authService}
 }
 
+// loginClientIP: IP por la que se limitan los intentos de login. Si la petición
+// viene de un proxy de confianza que no reenvía X-Forwarded-For (el frontend no
+// siempre conoce la IP de su cliente) devuelve "": la IP remota es la del
+// proxy, compartida por todos los usuarios, y limitar por ella los bloquearía
+// a todos a la vez. Quedan el límite por email y el bloqueo de cuenta.
+func loginClientIP(c fiber.Ctx) string {
+	if c.App().Config().TrustProxy && c.IsProxyTrusted() && c.Get(fiber.HeaderXForwardedFor) == "" {
+		return ""
+	}
+	return c.IP()
+}
+
 func (h *AuthHandler) Signin(c fiber.Ctx) (interface{}, string, error) {
 	logger.Log.Info("📥 Signin request received")
 
@@ -29,17 +43,220 @@ func (h *AuthHandler) Signin(c fiber.Ctx) (interface{}, string, error) {
 
 	logger.Log.Infof("📧 Email: %s, Provider: %v", req.Email, req.Provider)
 
-	response, err := h.authService.Signin(req)
+	response, err := h.authService.Signin(req, loginClientIP(c))
 	if err != nil {
 		logger.Log.Errorf("❌ Signin failed: %v", err)
 
-		if errors.Is(err, services.ErrGoogleUserNotRegistered) {
+		if limited := rateLimited(c, err); limited != nil {
+			return nil, err.Error(), limited
+		}
+
+		if errors.Is(err, services.ErrGoogleUserNotRegistered) ||
+			errors.Is(err, security.ErrGoogleEmailNotVerified) {
 			return nil, err.Error(), fiber.NewError(fiber.StatusForbidden, err.Error())
 		}
+		if errors.Is(err, services.ErrGoogleTokenRequired) {
+			return nil, err.Error(), fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, security.ErrGoogleNotConfigured) {
+			return nil, err.Error(), fiber.NewError(fiber.StatusServiceUnavailable, err.Error())
+		}
+
+		return nil, err.Error(), fiber.NewError(fiber.StatusUnauthorized, err.Error())
+	}
+
+	logger.Log.Infof("✅ Signin successful for %s", response.Email)
+	return response, "Login successful", nil
+}
+
+func (h *AuthHandler) Refresh(c fiber.Ctx) (interface{}, string, error) {
+	var req dto.RefreshRequest
+	if err := c.Bind().JSON(&req); err != nil {
+		return nil, "Invalid request body", fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	response, err := h.authService.Refresh(req)
+	if err != nil {
+		logger.Log.Errorf("❌ Refresh failed: %v", err)
+		if errors.Is(err, services.ErrRefreshTokenReused) {
+			logger.Log.Warn("⚠️ Refresh token reuse detected, session family revoked")
+		}
+		return nil, err.Error(), fiber.NewError(fiber.StatusUnauthorized, err.Error())
+	}
+
+	return response, "Token refreshed", nil
+}
+
+func (h *AuthHandler) Logout(c fiber.Ctx) (interface{}, string, error) {
+	var req dto.RefreshRequest
+	if err := c.Bind().JSON(&req); err != nil {
+		return nil, "Invalid request body", fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
 
+	if err := h.authService.Logout(req); err != nil {
+		logger.Log.Errorf("❌ Logout failed: %v", err)
 		return nil, err.Error(), fiber.NewError(fiber.StatusUnauthorized, err.Error())
 	}
 
+	return nil, "Logout successful", nil
+}
+
+func (h *AuthHandler) LogoutAll(c fiber.Ctx) (interface{}, string, error) {
+	userID, _, err := getAuthLocals(c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := h.authService.LogoutAll(userID); err != nil {
+		logger.Log.Errorf("❌ LogoutAll failed: %v", err)
+		return nil, err.Error(), fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	return nil, "All sessions closed", nil
+}
+
+func (h *AuthHandler) ChangePassword(c fiber.Ctx) (interface{}, string, error) {
+	userID, _, err := getAuthLocals(c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var req dto.ChangePasswordRequest
+	if err := c.Bind().JSON(&req); err != nil {
+		return nil, "Invalid request body", fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	response, err := h.authService.ChangePassword(userID, req)
+	if err != nil {
+		logger.Log.Errorf("❌ ChangePassword failed: %v", err)
+		if errors.Is(err, services.ErrInvalidCredentials) ||
+			errors.Is(err, services.ErrUserNotFound) ||
+			errors.Is(err, services.ErrUserInactive) {
+			return nil, err.Error(), fiber.NewError(fiber.StatusUnauthorized, err.Error())
+		}
+		return nil, err.Error(), fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return response, "Password changed, other sessions were closed", nil
+}
+
+func (h *AuthHandler) AcceptInvite(c fiber.Ctx) (interface{}, string, error) {
+	var req dto.AcceptInviteRequest
+	if err := c.Bind().JSON(&req); err != nil {
+		return nil, "Invalid request body", fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	response, err := h.authService.AcceptInvite(req)
+	if err != nil {
+		logger.Log.Errorf("❌ AcceptInvite failed: %v", err)
+		if errors.Is(err, services.ErrInvitationInvalid) || errors.Is(err, services.ErrUserInactive) {
+			return nil, err.Error(), fiber.NewError(fiber.StatusUnauthorized, err.Error())
+		}
+		return nil, err.Error(), fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return response, "Invitation accepted", nil
+}
+
+func (h *AuthHandler) EnrollMFA(c fiber.Ctx) (interface{}, string, error) {
+	userID, ok := c.Locals("userID").(string)
+	if !ok {
+		return nil, "", fiber.NewError(fiber.StatusUnauthorized, "Token inválido o userID no encontrado")
+	}
+
+	response, err := h.authService.EnrollMFA(userID)
+	if err != nil {
+		logger.Log.Errorf("❌ EnrollMFA failed: %v", err)
+		return nil, err.Error(), mfaError(err)
+	}
+
+	return response, "Scan the QR code and verify a code to enable 2FA", nil
+}
+
+func (h *AuthHandler) VerifyMFA(c fiber.Ctx) (interface{}, string, error) {
+	userID, ok := c.Locals("userID").(string)
+	if !ok {
+		return nil, "", fiber.NewError(fiber.StatusUnauthorized, "Token inválido o userID no encontrado")
+	}
+	enrollment, _ := c.Locals("mfaEnrollment").(bool)
+
+	var req dto.MFACodeRequest
+	if err := c.Bind().JSON(&req); err != nil {
+		return nil, "Invalid request body", fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	response, err := h.authService.VerifyMFA(userID, req, enrollment)
+	if err != nil {
+		logger.Log.Errorf("❌ VerifyMFA failed: %v", err)
+		return nil, err.Error(), mfaError(err)
+	}
+
+	return response, "2FA enabled, store the recovery codes safely", nil
+}
+
+func (h *AuthHandler) DisableMFA(c fiber.Ctx) (interface{}, string, error) {
+	userID, _, err := getAuthLocals(c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var req dto.MFACodeRequest
+	if err := c.Bind().JSON(&req); err != nil {
+		return nil, "Invalid request body", fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	if err := h.authService.DisableMFA(userID, req); err != nil {
+		logger.Log.Errorf("❌ DisableMFA failed: %v", err)
+		return nil, err.Error(), mfaError(err)
+	}
+
+	return nil, "2FA disabled", nil
+}
+
+func (h *AuthHandler) ChallengeMFA(c fiber.Ctx) (interface{}, string, error) {
+	var req dto.MFAChallengeRequest
+	if err := c.Bind().JSON(&req); err != nil {
+		return nil, "Invalid request body", fiber.NewError(fiber.StatusBadRequest, "Invalid request body")
+	}
+
+	response, err := h.authService.ChallengeMFA(req, loginClientIP(c))
+	if err != nil {
+		logger.Log.Errorf("❌ ChallengeMFA failed: %v", err)
+		if limited := rateLimited(c, err); limited != nil {
+			return nil, err.Error(), limited
+		}
+		return nil, err.Error(), mfaError(err)
+	}
+
 	logger.Log.Infof("✅ Signin successful for %s", response.Email)
 	return response, "Login successful", nil
 }
+
+func mfaError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrMFAInvalidCode),
+		errors.Is(err, services.ErrMFATokenInvalid),
+		errors.Is(err, services.ErrUserInactive):
+		return fiber.NewError(fiber.StatusUnauthorized, err.Error())
+	case errors.Is(err, services.ErrMFARequiredByPolicy):
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrUserNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrMFAAlreadyEnabled),
+		errors.Is(err, services.ErrMFANotEnabled),
+		errors.Is(err, services.ErrMFANotEnrolled):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	default:
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+}
+
+// rateLimited: convierte un RateLimitError en 429 con la cabecera Retry-After
+func rateLimited(c fiber.Ctx, err error) error {
+	var rl *services.RateLimitError
+	if !errors.As(err, &rl) {
+		return nil
+	}
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(rl.RetrySeconds()))
+	return fiber.NewError(fiber.StatusTooManyRequests, err.Error())
+}
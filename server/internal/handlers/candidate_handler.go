@@ -35,8 +35,31 @@ func getAuthLocals(c fiber.Ctx) (string, string, error) {
 	return userID, userRole, nil
 }
 
+// formField: valor de un campo del formulario y si vino en la petición, para
+// distinguir un campo vacío de uno ausente
+func formField(c fiber.Ctx, key string) (string, bool) {
+	if form, err := c.MultipartForm(); err == nil {
+		values, ok := form.Value[key]
+		if !ok || len(values) == 0 {
+			return "", false
+		}
+		return values[0], true
+	}
+
+	args := c.Request().PostArgs()
+	if !args.Has(key) {
+		return "", false
+	}
+	return string(args.Peek(key)), true
+}
+
 func (h *CandidateHandler) GetAll(c fiber.Ctx) (interface{}, string, error) {
-	candidates, err := h.service.GetAll()
+	userID, userRole, err := getAuthLocals(c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	candidates, err := h.service.GetAll(c.Query("electionId"), userID, userRole)
 	if err != nil {
 		logger.Log.Errorf("❌ GetAll candidates failed: %v", err)
 		return nil, err.Error(), fiber.NewError(fiber.StatusBadRequest, err.Error())
@@ -67,10 +90,6 @@ func (h *CandidateHandler) Create(c fiber.Ctx) (interface{}, string, error) {
 		return nil, "", err
 	}
 
-	if userRole != "ADMIN" {
-		return nil, "No autorizado", fiber.NewError(fiber.StatusForbidden, "Solo ADMIN puede crear candidatos")
-	}
-
 	name := c.FormValue("name")
 	if name == "" {
 		return nil, "Campo requerido", fiber.NewError(fiber.StatusBadRequest, "El campo 'name' es requerido")
@@ -94,7 +113,7 @@ func (h *CandidateHandler) Create(c fiber.Ctx) (interface{}, string, error) {
 	var imageID string
 	file, err := c.FormFile("image")
 	if err == nil && file != nil {
-		image, err := h.imageService.SaveImage(file)
+		image, err := h.imageService.SaveImage(file, userID, userRole, c.IP())
 		if err != nil {
 			logger.Log.Errorf("❌ Save image failed: %v", err)
 			return nil, err.Error(), fiber.NewError(fiber.StatusBadRequest, err.Error())
@@ -103,7 +122,7 @@ func (h *CandidateHandler) Create(c fiber.Ctx) (interface{}, string, error) {
 		filepath := filepath.Join("./uploads", image.Filename)
 		if err := c.SaveFile(file, filepath); err != nil {
 			logger.Log.Errorf("❌ Save file failed: %v", err)
-			h.imageService.Delete(image.ID)
+			h.imageService.Delete(image.ID, userID, userRole, c.IP())
 			return nil, "Error al guardar archivo", fiber.NewError(fiber.StatusInternalServerError, err.Error())
 		}
 
@@ -115,6 +134,11 @@ func (h *CandidateHandler) Create(c fiber.Ctx) (interface{}, string, error) {
 		descPtr = &description
 	}
 
+	var partyPtr *string
+	if party := c.FormValue("party"); party != "" {
+		partyPtr = &party
+	}
+
 	req := dto.CreateCandidateRequest{
 		Name:          name,
 		Description:   descPtr,
@@ -122,13 +146,14 @@ func (h *CandidateHandler) Create(c fiber.Ctx) (interface{}, string, error) {
 		IsActive:      isActive,
 		PositionID:    positionID,
 		TypeCandidate: typeCandidate,
+		Party:         partyPtr,
 	}
 
-	candidate, err := h.service.Create(req, userID, userRole)
+	candidate, err := h.service.Create(req, userID, userRole, c.IP())
 	if err != nil {
 		logger.Log.Errorf("❌ Create candidate failed: %v", err)
 		if imageID != "" {
-			h.imageService.Delete(imageID)
+			h.imageService.Delete(imageID, userID, userRole, c.IP())
 		}
 		return nil, err.Error(), fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
@@ -143,10 +168,6 @@ func (h *CandidateHandler) Update(c fiber.Ctx) (interface{}, string, error) {
 		return nil, "", err
 	}
 
-	if userRole != "ADMIN" {
-		return nil, "No autorizado", fiber.NewError(fiber.StatusForbidden, "Solo ADMIN puede actualizar candidatos")
-	}
-
 	currentCandidate, err := h.service.GetOne(id, userID, userRole)
 	if err != nil {
 		logger.Log.Errorf("❌ Candidate not found: %v", err)
@@ -172,9 +193,14 @@ func (h *CandidateHandler) Update(c fiber.Ctx) (interface{}, string, error) {
 		req.IsActive = &val
 	}
 
+	// party vacío pero presente borra el partido
+	if party, ok := formField(c, "party"); ok {
+		req.Party = &party
+	}
+
 	file, err := c.FormFile("image")
 	if err == nil && file != nil {
-		newImage, err := h.imageService.SaveImage(file)
+		newImage, err := h.imageService.SaveImage(file, userID, userRole, c.IP())
 		if err != nil {
 			logger.Log.Errorf("❌ Save image failed: %v", err)
 			return nil, err.Error(), fiber.NewError(fiber.StatusBadRequest, err.Error())
@@ -183,29 +209,28 @@ func (h *CandidateHandler) Update(c fiber.Ctx) (interface{}, string, error) {
 		filepath := filepath.Join("./uploads", newImage.Filename)
 		if err := c.SaveFile(file, filepath); err != nil {
 			logger.Log.Errorf("❌ Save file failed: %v", err)
-			h.imageService.Delete(newImage.ID)
+			h.imageService.Delete(newImage.ID, userID, userRole, c.IP())
 			return nil, "Error al guardar archivo", fiber.NewError(fiber.StatusInternalServerError, err.Error())
 		}
 
 		req.ImageID = &newImage.ID
-
-		if currentCandidate.ImageID != nil && *currentCandidate.ImageID != "" {
-			oldImageID := *currentCandidate.ImageID
-			if err := h.imageService.Delete(oldImageID); err != nil {
-				logger.Log.Warnf("⚠️ No se pudo eliminar imagen anterior: %v", err)
-			}
-		}
 	}
 
-	candidate, err := h.service.Update(id, req, userID, userRole)
+	candidate, err := h.service.Update(id, req, userID, userRole, c.IP())
 	if err != nil {
 		logger.Log.Errorf("❌ Update candidate failed: %v", err)
 		if req.ImageID != nil && *req.ImageID != "" {
-			h.imageService.Delete(*req.ImageID)
+			h.imageService.Delete(*req.ImageID, userID, userRole, c.IP())
 		}
 		return nil, err.Error(), fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
 
+	if req.ImageID != nil && currentCandidate.ImageID != nil && *currentCandidate.ImageID != "" {
+		if err := h.imageService.Delete(*currentCandidate.ImageID, userID, userRole, c.IP()); err != nil {
+			logger.Log.Warnf("⚠️ No se pudo eliminar imagen anterior: %v", err)
+		}
+	}
+
 	return candidate, "Candidate actualizado correctamente", nil
 }
 
@@ -216,27 +241,23 @@ func (h *CandidateHandler) Delete(c fiber.Ctx) (interface{}, string, error) {
 		return nil, "", err
 	}
 
-	if userRole != "ADMIN" {
-		return nil, "No autorizado", fiber.NewError(fiber.StatusForbidden, "Solo ADMIN puede eliminar candidatos")
-	}
-
 	currentCandidate, err := h.service.GetOne(id, userID, userRole)
 	if err != nil {
 		logger.Log.Errorf("❌ Candidate not found: %v", err)
 		return nil, err.Error(), fiber.NewError(fiber.StatusNotFound, err.Error())
 	}
 
+	if err := h.service.Delete(id, userID, userRole, c.IP()); err != nil {
+		logger.Log.Errorf("❌ Delete candidate failed: %v", err)
+		return nil, err.Error(), fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
 	if currentCandidate.ImageID != nil && *currentCandidate.ImageID != "" {
-		if err := h.imageService.Delete(*currentCandidate.ImageID); err != nil {
+		if err := h.imageService.Delete(*currentCandidate.ImageID, userID, userRole, c.IP()); err != nil {
 			logger.Log.Warnf("⚠️ No se pudo eliminar la imagen asociada: %v", err)
 		}
 	}
 
-	if err := h.service.Delete(id, userID, userRole); err != nil {
-		logger.Log.Errorf("❌ Delete candidate failed: %v", err)
-		return nil, err.Error(), fiber.NewError(fiber.StatusBadRequest, err.Error())
-	}
-
 	return nil, "Candidate eliminado correctamente", nil
 }
 
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"server/internal/dto"
+	"server/internal/services"
+	"server/pkgs/logger"
+)
+
+type DiscrepancyHandler struct {
+	service services.DiscrepancyService
+}
+
+func NewDiscrepancyHandler(service services.DiscrepancyService) *DiscrepancyHandler {
+	return &DiscrepancyHandler{service: service}
+}
+
+func (h *DiscrepancyHandler) GetAll(c fiber.Ctx) (interface{}, string, error) {
+	userID, userRole, err := getAuthLocals(c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	discrepancies, err := h.service.GetAll(c.Query("status"), c.Query("electionId"), userID, userRole)
+	if err != nil {
+		logger.Log.Errorf("❌ GetAll discrepancies failed: %v", err)
+		return nil, err.Error(), fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return discrepancies, "Discrepancias obtenidas correctamente", nil
+}
+
+func (h *DiscrepancyHandler) Resolve(c fiber.Ctx) (interface{}, string, error) {
+	userID, userRole, err := getAuthLocals(c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var req dto.ResolveDiscrepancyRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return nil, "Solicitud inválida", fiber.NewError(fiber.StatusBadRequest, "Body inválido: "+err.Error())
+	}
+
+	discrepancy, err := h.service.Resolve(c.Params("id"), req, userID, userRole, c.IP())
+	if err != nil {
+		logger.Log.Errorf("❌ Resolve discrepancy failed: %v", err)
+		switch {
+		case errors.Is(err, services.ErrDiscrepancyNotFound):
+			return nil, err.Error(), fiber.NewError(fiber.StatusNotFound, err.Error())
+		case errors.Is(err, services.ErrUnauthorized):
+			return nil, err.Error(), fiber.NewError(fiber.StatusForbidden, err.Error())
+		case errors.Is(err, services.ErrDiscrepancyResolved), errors.Is(err, services.ErrDuplicateVote),
+			errors.Is(err, services.ErrMesaNotOpen), errors.Is(err, services.ErrElectionLocked):
+			return nil, err.Error(), fiber.NewError(fiber.StatusConflict, err.Error())
+		}
+		return nil, err.Error(), fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return discrepancy, "Discrepancia resuelta correctamente", nil
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"server/internal/dto"
+	"server/internal/services"
+	"server/pkgs/logger"
+)
+
+type ElectionHandler struct {
+	service services.ElectionService
+}
+
+func NewElectionHandler(service services.ElectionService) *ElectionHandler {
+	return &ElectionHandler{service: service}
+}
+
+func (h *ElectionHandler) GetAll(c fiber.Ctx) (interface{}, string, error) {
+	elections, err := h.service.GetAll()
+	if err != nil {
+		logger.Log.Errorf("❌ GetAll elections failed: %v", err)
+		return nil, err.Error(), fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return elections, "Elecciones obtenidas correctamente", nil
+}
+
+func (h *ElectionHandler) GetOne(c fiber.Ctx) (interface{}, string, error) {
+	election, err := h.service.GetOne(c.Params("id"))
+	if err != nil {
+		logger.Log.Errorf("❌ GetOne election failed: %v", err)
+		return nil, err.Error(), electionError(err)
+	}
+
+	return election, "Elección obtenida correctamente", nil
+}
+
+func (h *ElectionHandler) Create(c fiber.Ctx) (interface{}, string, error) {
+	userID, userRole, err := getAuthLocals(c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var req dto.CreateElectionRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return nil, "Solicitud inválida", fiber.NewError(fiber.StatusBadRequest, "Body inválido: "+err.Error())
+	}
+
+	election, err := h.service.Create(req, userID, userRole)
+	if err != nil {
+		logger.Log.Errorf("❌ Create election failed: %v", err)
+		return nil, err.Error(), electionError(err)
+	}
+
+	return election, "Elección creada correctamente", nil
+}
+
+func (h *ElectionHandler) Update(c fiber.Ctx) (interface{}, string, error) {
+	userID, userRole, err := getAuthLocals(c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var req dto.UpdateElectionRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return nil, "Solicitud inválida", fiber.NewError(fiber.StatusBadRequest, "Body inválido: "+err.Error())
+	}
+
+	election, err := h.service.Update(c.Params("id"), req, userID, userRole, c.IP())
+	if err != nil {
+		logger.Log.Errorf("❌ Update election failed: %v", err)
+		return nil, err.Error(), electionError(err)
+	}
+
+	return election, "Elección actualizada correctamente", nil
+}
+
+func (h *ElectionHandler) Delete(c fiber.Ctx) (interface{}, string, error) {
+	userID, userRole, err := getAuthLocals(c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := h.service.Delete(c.Params("id"), userID, userRole); err != nil {
+		logger.Log.Errorf("❌ Delete election failed: %v", err)
+		return nil, err.Error(), electionError(err)
+	}
+
+	return nil, "Elección eliminada correctamente", nil
+}
+
+func (h *ElectionHandler) Transition(c fiber.Ctx) (interface{}, string, error) {
+	userID, userRole, err := getAuthLocals(c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var req dto.TransitionElectionRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return nil, "Solicitud inválida", fiber.NewError(fiber.StatusBadRequest, "Body inválido: "+err.Error())
+	}
+
+	election, err := h.service.Transition(c.Params("id"), req, userID, userRole)
+	if err != nil {
+		logger.Log.Errorf("❌ Transition election failed: %v", err)
+		return nil, err.Error(), electionError(err)
+	}
+
+	return election, "Estado de la elección actualizado correctamente", nil
+}
+
+// Certify: congela y firma los resultados de una elección cerrada
+func (h *ElectionHandler) Certify(c fiber.Ctx) (interface{}, string, error) {
+	userID, userRole, err := getAuthLocals(c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	certificate, err := h.service.Certify(c.Params("id"), userID, userRole, c.IP())
+	if err != nil {
+		logger.Log.Errorf("❌ Certify election failed: %v", err)
+		return nil, err.Error(), electionError(err)
+	}
+
+	return certificate, "Resultados certificados y firmados correctamente", nil
+}
+
+func (h *ElectionHandler) GetCertificate(c fiber.Ctx) (interface{}, string, error) {
+	certificate, err := h.service.GetCertificate(c.Params("id"))
+	if err != nil {
+		logger.Log.Errorf("❌ GetCertificate election failed: %v", err)
+		return nil, err.Error(), electionError(err)
+	}
+
+	return certificate, "Certificado de resultados obtenido correctamente", nil
+}
+
+func electionError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrElectionNotFound),
+		errors.Is(err, services.ErrCertificateNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrUnauthorizedAction):
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrElectionNameTaken),
+		errors.Is(err, services.ErrElectionInUse),
+		errors.Is(err, services.ErrElectionLocked),
+		errors.Is(err, services.ErrElectionTransition),
+		errors.Is(err, services.ErrCertificationRequired):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrCertificationUnavailable):
+		return fiber.NewError(fiber.StatusServiceUnavailable, err.Error())
+	case errors.Is(err, services.ErrElectionPrecondition):
+		return fiber.NewError(fiber.StatusUnprocessableEntity, err.Error())
+	default:
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+}
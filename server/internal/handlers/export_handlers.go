@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v3"
+	"server/internal/services"
+	"server/pkgs/logger"
+)
+
+type ExportHandler struct {
+	service services.ExportService
+}
+
+func NewExportHandler(service services.ExportService) *ExportHandler {
+	return &ExportHandler{service: service}
+}
+
+// exportFunc: handler que responde el archivo en el formato negociado
+type exportFunc func(c fiber.Ctx, format services.ExportFormat) error
+
+// Negotiate: ?format=csv|xlsx o, sin él, la cabecera Accept deciden si la
+// ruta responde el archivo exportado; en cualquier otro caso responde el JSON
+// de siempre con jsonHandler.
+func Negotiate(jsonHandler fiber.Handler, export exportFunc) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		format, err := exportFormat(c)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		if format == "" {
+			return jsonHandler(c)
+		}
+		return export(c, format)
+	}
+}
+
+// exportFormat: "" si hay que responder JSON
+func exportFormat(c fiber.Ctx) (services.ExportFormat, error) {
+	if f := c.Query("format"); f != "" {
+		if strings.EqualFold(f, "json") {
+			return "", nil
+		}
+		return services.ParseExportFormat(f)
+	}
+
+	csvType := "text/csv"
+	xlsxType := services.ExportXLSX.ContentType()
+	switch c.Accepts(fiber.MIMEApplicationJSON, csvType, xlsxType) {
+	case csvType:
+		return services.ExportCSV, nil
+	case xlsxType:
+		return services.ExportXLSX, nil
+	}
+	return "", nil
+}
+
+func (h *ExportHandler) Votes(c fiber.Ctx, format services.ExportFormat) error {
+	userID, userRole, err := getAuthLocals(c)
+	if err != nil {
+		return err
+	}
+
+	export, err := h.service.Votes(c.Query("electionId"), userID, userRole, format)
+	if err != nil {
+		logger.Log.Errorf("❌ Export votes failed: %v", err)
+		return exportError(err)
+	}
+	return sendExport(c, export)
+}
+
+func (h *ExportHandler) Results(c fiber.Ctx, format services.ExportFormat) error {
+	export, err := h.service.Results(c.Query("electionId"), format)
+	if err != nil {
+		logger.Log.Errorf("❌ Export results failed: %v", err)
+		return exportError(err)
+	}
+	return sendExport(c, export)
+}
+
+func (h *ExportHandler) PositionResults(c fiber.Ctx, format services.ExportFormat) error {
+	export, err := h.service.PositionResults(c.Params("id"), format)
+	if err != nil {
+		logger.Log.Errorf("❌ Export position results failed: %v", err)
+		return exportError(err)
+	}
+	return sendExport(c, export)
+}
+
+// sendExport: el archivo se escribe mientras se envía. Si falla a mitad, el
+// estado 200 ya salió: se registra y la descarga queda truncada.
+func sendExport(c fiber.Ctx, export *services.Export) error {
+	c.Set(fiber.HeaderContentType, export.ContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, export.Filename))
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		if err := export.Write(w); err != nil {
+			logger.Log.Errorf("❌ Export %s failed while streaming: %v", export.Filename, err)
+			return
+		}
+		if err := w.Flush(); err != nil {
+			logger.Log.Warnf("⚠️ Export %s interrupted: %v", export.Filename, err)
+		}
+	})
+}
+
+func exportError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrPositionNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrPartyRequired):
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrInvalidExportFormat):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	default:
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"errors"
+	"mime/multipart"
+
+	"github.com/gofiber/fiber/v3"
+	"server/internal/dto"
+	"server/internal/services"
+	"server/pkgs/logger"
+)
+
+type ImportHandler struct {
+	service services.ImportService
+}
+
+func NewImportHandler(service services.ImportService) *ImportHandler {
+	return &ImportHandler{service: service}
+}
+
+// Positions: multipart con file (.csv o .xlsx) y electionId. No pasa por
+// httpwrap porque una importación rechazada responde 422 con los errores por
+// fila en data.
+func (h *ImportHandler) Positions(c fiber.Ctx) error {
+	userID, userRole, err := getAuthLocals(c)
+	if err != nil {
+		return err
+	}
+
+	electionID := importElectionID(c)
+	if electionID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "El campo 'electionId' es requerido")
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "El archivo 'file' es requerido")
+	}
+
+	res, err := h.service.Positions(electionID, file, importDryRun(c), userID, userRole, c.IP())
+	if err != nil {
+		logger.Log.Errorf("❌ Import positions failed: %v", err)
+		return importError(err)
+	}
+	return sendImportResult(c, res)
+}
+
+// Candidates: como Positions, más photos (.zip) opcional con las fotos que
+// nombra la columna photo
+func (h *ImportHandler) Candidates(c fiber.Ctx) error {
+	userID, userRole, err := getAuthLocals(c)
+	if err != nil {
+		return err
+	}
+
+	electionID := importElectionID(c)
+	if electionID == "" {
+		return fiber.NewError(fiber.StatusBadRequest, "El campo 'electionId' es requerido")
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, "El archivo 'file' es requerido")
+	}
+
+	var photos *multipart.FileHeader
+	if f, err := c.FormFile("photos"); err == nil {
+		photos = f
+	}
+
+	res, err := h.service.Candidates(electionID, file, photos, importDryRun(c), userID, userRole, c.IP())
+	if err != nil {
+		logger.Log.Errorf("❌ Import candidates failed: %v", err)
+		return importError(err)
+	}
+	return sendImportResult(c, res)
+}
+
+func importElectionID(c fiber.Ctx) string {
+	if id := c.FormValue("electionId"); id != "" {
+		return id
+	}
+	return c.Query("electionId")
+}
+
+func importDryRun(c fiber.Ctx) bool {
+	return c.Query("dryRun") == "true" || c.FormValue("dryRun") == "true"
+}
+
+func sendImportResult(c fiber.Ctx, res *dto.ImportResult) error {
+	status, msg := fiber.StatusOK, "Importación aplicada correctamente"
+	switch {
+	case res.DryRun && len(res.Errors) > 0:
+		msg = "Validación completada con errores"
+	case res.DryRun:
+		msg = "Validación completada sin errores, no se aplicó ningún cambio"
+	case !res.Applied:
+		status, msg = fiber.StatusUnprocessableEntity, "La importación tiene errores, no se aplicó ningún cambio"
+	}
+
+	return c.Status(status).JSON(fiber.Map{
+		"status":  status,
+		"message": msg,
+		"data":    res,
+	})
+}
+
+func importError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrElectionNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrElectionLocked):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrImportFileType), errors.Is(err, services.ErrImportPhotosType),
+		errors.Is(err, services.ErrImportEmpty), errors.Is(err, services.ErrImportTooManyRows),
+		errors.Is(err, services.ErrImportHeader):
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	default:
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"server/internal/dto"
+	"server/internal/services"
+	"server/pkgs/logger"
+)
+
+type MesaHandler struct {
+	service services.MesaService
+}
+
+func NewMesaHandler(service services.MesaService) *MesaHandler {
+	return &MesaHandler{service: service}
+}
+
+func (h *MesaHandler) GetAll(c fiber.Ctx) (interface{}, string, error) {
+	mesas, err := h.service.GetAll(c.Query("electionId"))
+	if err != nil {
+		logger.Log.Errorf("❌ GetAll mesas failed: %v", err)
+		return nil, err.Error(), fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return mesas, "Mesas obtenidas correctamente", nil
+}
+
+func (h *MesaHandler) GetOne(c fiber.Ctx) (interface{}, string, error) {
+	mesa, err := h.service.GetOne(c.Params("id"))
+	if err != nil {
+		logger.Log.Errorf("❌ GetOne mesa failed: %v", err)
+		return nil, err.Error(), mesaError(err)
+	}
+
+	return mesa, "Mesa obtenida correctamente", nil
+}
+
+func (h *MesaHandler) Create(c fiber.Ctx) (interface{}, string, error) {
+	userID, userRole, err := getAuthLocals(c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var req dto.CreateMesaRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return nil, "Solicitud inválida", fiber.NewError(fiber.StatusBadRequest, "Body inválido: "+err.Error())
+	}
+
+	mesa, err := h.service.Create(req, userID, userRole)
+	if err != nil {
+		logger.Log.Errorf("❌ Create mesa failed: %v", err)
+		return nil, err.Error(), mesaError(err)
+	}
+
+	return mesa, "Mesa creada correctamente", nil
+}
+
+func (h *MesaHandler) Update(c fiber.Ctx) (interface{}, string, error) {
+	userID, userRole, err := getAuthLocals(c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var req dto.UpdateMesaRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return nil, "Solicitud inválida", fiber.NewError(fiber.StatusBadRequest, "Body inválido: "+err.Error())
+	}
+
+	mesa, err := h.service.Update(c.Params("id"), req, userID, userRole)
+	if err != nil {
+		logger.Log.Errorf("❌ Update mesa failed: %v", err)
+		return nil, err.Error(), mesaError(err)
+	}
+
+	return mesa, "Mesa actualizada correctamente", nil
+}
+
+func (h *MesaHandler) Delete(c fiber.Ctx) (interface{}, string, error) {
+	userID, userRole, err := getAuthLocals(c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := h.service.Delete(c.Params("id"), userID, userRole); err != nil {
+		logger.Log.Errorf("❌ Delete mesa failed: %v", err)
+		return nil, err.Error(), mesaError(err)
+	}
+
+	return nil, "Mesa eliminada correctamente", nil
+}
+
+func mesaError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrMesaNotFound), errors.Is(err, services.ErrElectionNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrUnauthorizedAction):
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrMesaNumberTaken), errors.Is(err, services.ErrMesaHasVotes):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrElectionLocked):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	default:
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v3"
+	"github.com/valyala/fasthttp"
+	"server/internal/dto"
+	"server/internal/services"
+	"server/pkgs/logger"
+	"server/pkgs/middleware"
+)
+
+const (
+	mesaWSPingPeriod   = 30 * time.Second
+	mesaWSWriteTimeout = 10 * time.Second
+	mesaWSMaxMessage   = 4096
+)
+
+type MesaLockHandler struct {
+	locks    *services.MesaLockManager
+	sessions services.SessionService
+	upgrader websocket.FastHTTPUpgrader
+}
+
+func NewMesaLockHandler(locks *services.MesaLockManager, sessions services.SessionService) *MesaLockHandler {
+	return &MesaLockHandler{
+		locks:    locks,
+		sessions: sessions,
+		upgrader: websocket.FastHTTPUpgrader{
+			Subprotocols: []string{middleware.WebSocketAuthProtocol},
+			// la autenticación va en el token y no en cookies, así que no hay
+			// riesgo de CSRF; se admite cualquier origen como en CORS
+			CheckOrigin: func(ctx *fasthttp.RequestCtx) bool { return true },
+		},
+	}
+}
+
+// Connect: WebSocket en el que el digitador anuncia la mesa que edita. El
+// servidor le concede el bloqueo de edición (o le dice quién lo tiene),
+// difunde la presencia de todos y libera el bloqueo al desconectarse. El token
+// de lock_granted debe enviarse en la cabecera X-Mesa-Lock de POST /votes y
+// renovarse repitiendo lock antes de expiresAt. La sesión se vuelve a validar
+// en cada mensaje y en cada ping: si el token expira sin renovarse con auth,
+// o el usuario se desactiva o cambia de rol, se cierra la conexión y se
+// liberan sus bloqueos.
+func (h *MesaLockHandler) Connect(c fiber.Ctx) error {
+	if !websocket.FastHTTPIsWebSocketUpgrade(c.RequestCtx()) {
+		return fiber.NewError(fiber.StatusUpgradeRequired, "se esperaba una conexión WebSocket")
+	}
+
+	// los locals no sobreviven al handler, se copian antes del upgrade
+	userID, _ := c.Locals("userID").(string)
+	email, _ := c.Locals("userEmail").(string)
+	role, _ := c.Locals("userRole").(string)
+	session := &mesaWSSession{token: middleware.WebSocketAccessToken(c), userID: userID, role: role}
+
+	err := h.upgrader.Upgrade(c.RequestCtx(), func(conn *websocket.Conn) {
+		h.serve(conn, session, email)
+	})
+	if err != nil {
+		// el upgrader ya respondió con el error del handshake
+		logger.Log.Errorf("❌ Mesa lock websocket upgrade failed: %v", err)
+	}
+	return nil
+}
+
+func (h *MesaLockHandler) serve(conn *websocket.Conn, session *mesaWSSession, email string) {
+	defer conn.Close()
+
+	clientID, outbox := h.locks.Connect(session.userID, email, session.role)
+	defer h.locks.Disconnect(clientID)
+
+	var writeMu sync.Mutex
+	write := func(msg dto.MesaWSMessage) error {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		conn.SetWriteDeadline(time.Now().Add(mesaWSWriteTimeout))
+		return conn.WriteJSON(msg)
+	}
+
+	// closeRevoked: avisa al cliente y cierra; el lector termina y el defer
+	// libera los bloqueos
+	closeRevoked := func() {
+		writeMu.Lock()
+		conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.ClosePolicyViolation, services.ErrSessionRevoked.Error()),
+			time.Now().Add(mesaWSWriteTimeout))
+		writeMu.Unlock()
+		conn.Close()
+	}
+
+	go func() {
+		ticker := time.NewTicker(mesaWSPingPeriod)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case msg, ok := <-outbox:
+				if !ok {
+					return
+				}
+				if err := write(msg); err != nil {
+					conn.Close()
+					return
+				}
+			case <-ticker.C:
+				if err := h.authorize(session.current(), session); err != nil {
+					logger.Log.Warnf("⚠️ Mesa lock websocket closed for %s: %v", session.userID, err)
+					closeRevoked()
+					return
+				}
+				writeMu.Lock()
+				err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(mesaWSWriteTimeout))
+				writeMu.Unlock()
+				if err != nil {
+					conn.Close()
+					return
+				}
+			}
+		}
+	}()
+
+	conn.SetReadLimit(mesaWSMaxMessage)
+	conn.SetReadDeadline(time.Now().Add(2 * mesaWSPingPeriod))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * mesaWSPingPeriod))
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		conn.SetReadDeadline(time.Now().Add(2 * mesaWSPingPeriod))
+
+		var req dto.MesaWSRequest
+		var reply dto.MesaWSMessage
+		if err := json.Unmarshal(data, &req); err != nil {
+			req.Type = ""
+		}
+
+		if req.Type == dto.MesaWSAuth {
+			if err := h.authorize(req.Token, session); err != nil {
+				write(dto.MesaWSMessage{Type: dto.MesaWSError, Message: services.ErrSessionRevoked.Error()})
+				closeRevoked()
+				return
+			}
+			session.renew(req.Token)
+			if err := write(dto.MesaWSMessage{Type: dto.MesaWSWelcome, ClientID: clientID}); err != nil {
+				return
+			}
+			continue
+		}
+
+		if err := h.authorize(session.current(), session); err != nil {
+			logger.Log.Warnf("⚠️ Mesa lock websocket closed for %s: %v", session.userID, err)
+			write(dto.MesaWSMessage{Type: dto.MesaWSError, Message: services.ErrSessionRevoked.Error()})
+			closeRevoked()
+			return
+		}
+
+		switch req.Type {
+		case dto.MesaWSLock:
+			if req.MesaID == "" {
+				reply = dto.MesaWSMessage{Type: dto.MesaWSError, Message: "mesaId obligatorio"}
+				break
+			}
+			reply, _ = h.locks.Acquire(clientID, req.MesaID)
+		case dto.MesaWSRelease:
+			h.locks.Release(clientID)
+			reply = dto.MesaWSMessage{Type: dto.MesaWSReleased}
+		case dto.MesaWSPing:
+			reply = dto.MesaWSMessage{Type: dto.MesaWSPong}
+		default:
+			reply = dto.MesaWSMessage{Type: dto.MesaWSError, Message: "tipo de mensaje desconocido"}
+		}
+
+		if err := write(reply); err != nil {
+			return
+		}
+	}
+}
+
+// mesaWSSession: usuario de la conexión y el access token vigente, que el
+// cliente reemplaza con un mensaje auth
+type mesaWSSession struct {
+	userID string
+	role   string
+
+	mu    sync.Mutex
+	token string
+}
+
+func (s *mesaWSSession) current() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.token
+}
+
+func (s *mesaWSSession) renew(token string) {
+	s.mu.Lock()
+	s.token = token
+	s.mu.Unlock()
+}
+
+// authorize: el token sigue vigente, es del mismo usuario y rol de la
+// conexión, y ese rol todavía puede digitar
+func (h *MesaLockHandler) authorize(token string, session *mesaWSSession) error {
+	claims, err := h.sessions.Validate(token)
+	if err != nil {
+		return err
+	}
+	if claims.Subject != session.userID || claims.Role != session.role ||
+		!middleware.Can(claims.Role, middleware.PermVotesCreate) {
+		return services.ErrSessionRevoked
+	}
+	return nil
+}
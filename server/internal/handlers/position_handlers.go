@@ -16,7 +16,7 @@ func NewPositionHandler(service services.PositionService) *PositionHandler {
 }
 
 func (h *PositionHandler) GetAll(c fiber.Ctx) (interface{}, string, error) {
-	positions, err := h.service.GetAll()
+	positions, err := h.service.GetAll(c.Query("electionId"))
 	if err != nil {
 		logger.Log.Errorf("❌ GetAll positions failed: %v", err)
 		return nil, err.Error(), fiber.NewError(fiber.StatusBadRequest, err.Error())
@@ -40,7 +40,7 @@ func (h *PositionHandler) Create(c fiber.Ctx) (interface{}, string, error) {
 		return nil, "Solicitud inválida", fiber.NewError(fiber.StatusBadRequest, "Body inválido o formato incorrecto: "+err.Error())
 	}
 
-	position, err := h.service.Create(req, userID.(string), userRole.(string))
+	position, err := h.service.Create(req, userID.(string), userRole.(string), c.IP())
 	if err != nil {
 		logger.Log.Errorf("❌ Create position failed: %v", err)
 		return nil, err.Error(), fiber.NewError(fiber.StatusBadRequest, err.Error())
@@ -65,7 +65,7 @@ func (h *PositionHandler) Update(c fiber.Ctx) (interface{}, string, error) {
 		return nil, "Solicitud inválida", fiber.NewError(fiber.StatusBadRequest, "Body inválido o formato incorrecto: "+err.Error())
 	}
 
-	position, err := h.service.Update(id, req, userID.(string), userRole.(string))
+	position, err := h.service.Update(id, req, userID.(string), userRole.(string), c.IP())
 	if err != nil {
 		logger.Log.Errorf("❌ Update position failed: %v", err)
 		return nil, err.Error(), fiber.NewError(fiber.StatusBadRequest, err.Error())
@@ -85,7 +85,7 @@ func (h *PositionHandler) Delete(c fiber.Ctx) (interface{}, string, error) {
 		return nil, "Rol no encontrado", fiber.NewError(fiber.StatusUnauthorized, "Rol no disponible")
 	}
 
-	if err := h.service.Delete(id, userID.(string), userRole.(string)); err != nil {
+	if err := h.service.Delete(id, userID.(string), userRole.(string), c.IP()); err != nil {
 		logger.Log.Errorf("❌ Delete position failed: %v", err)
 		return nil, err.Error(), fiber.NewError(fiber.StatusBadRequest, err.Error())
 	}
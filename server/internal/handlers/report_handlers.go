@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v3"
+	"server/internal/dto"
+	"server/internal/services"
+	"server/pkgs/logger"
+)
+
+type ReportHandler struct {
+	service services.ReportService
+}
+
+func NewReportHandler(service services.ReportService) *ReportHandler {
+	return &ReportHandler{service: service}
+}
+
+// PositionPDF: acta de resultados de una posición. No pasa por httpwrap
+// porque la respuesta es el PDF y no JSON.
+func (h *ReportHandler) PositionPDF(c fiber.Ctx) error {
+	report, err := h.service.PositionPDF(c.Params("id"))
+	if err != nil {
+		logger.Log.Errorf("❌ Position report failed: %v", err)
+		return reportError(err)
+	}
+	return sendReport(c, report)
+}
+
+// ElectionPDF: resumen de la elección con el acta de cada posición
+func (h *ReportHandler) ElectionPDF(c fiber.Ctx) error {
+	report, err := h.service.ElectionPDF(c.Params("id"))
+	if err != nil {
+		logger.Log.Errorf("❌ Election report failed: %v", err)
+		return reportError(err)
+	}
+	return sendReport(c, report)
+}
+
+// sendReport: el hash y la fecha del pie también van en cabeceras para
+// poder archivarlos sin abrir el documento
+func sendReport(c fiber.Ctx, report *dto.ReportFile) error {
+	c.Set(fiber.HeaderContentType, report.ContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`inline; filename="%s"`, report.Filename))
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	c.Set("X-Report-Hash", report.Hash)
+	c.Set("X-Report-Generated-At", report.GeneratedAt)
+	return c.Send(report.Content)
+}
+
+func reportError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrPositionNotFound), errors.Is(err, services.ErrElectionNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	default:
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+}
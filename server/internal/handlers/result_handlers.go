@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v3"
+	"server/internal/services"
+	"server/pkgs/logger"
+)
+
+type ResultHandler struct {
+	service   services.ResultService
+	hub       *services.ResultHub
+	heartbeat time.Duration
+}
+
+func NewResultHandler(service services.ResultService, hub *services.ResultHub, heartbeat time.Duration) *ResultHandler {
+	return &ResultHandler{service: service, hub: hub, heartbeat: heartbeat}
+}
+
+func (h *ResultHandler) GetAll(c fiber.Ctx) (interface{}, string, error) {
+	results, err := h.service.GetAll(c.Query("electionId"))
+	if err != nil {
+		logger.Log.Errorf("❌ GetAll results failed: %v", err)
+		return nil, err.Error(), fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return results, "Resultados obtenidos correctamente", nil
+}
+
+func (h *ResultHandler) GetByPosition(c fiber.Ctx) (interface{}, string, error) {
+	positionID := c.Params("id")
+
+	result, err := h.service.GetByPosition(positionID)
+	if err != nil {
+		logger.Log.Errorf("❌ GetByPosition results failed: %v", err)
+		if errors.Is(err, services.ErrPositionNotFound) {
+			return nil, err.Error(), fiber.NewError(fiber.StatusNotFound, err.Error())
+		}
+		return nil, err.Error(), fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return result, "Resultados de la posición obtenidos correctamente", nil
+}
+
+// Stream: Server-Sent Events con los resultados de las posiciones que cambian.
+// Al conectar se envía un snapshot; al reconectar con Last-Event-ID (o
+// ?lastEventId=) solo los eventos perdidos, o un snapshot si ya no están en
+// memoria. No pasa por httpwrap porque la respuesta no es JSON.
+func (h *ResultHandler) Stream(c fiber.Ctx) error {
+	electionID := c.Query("electionId")
+	lastEventID, _ := strconv.ParseInt(c.Get("Last-Event-ID", c.Query("lastEventId")), 10, 64)
+
+	events, missed, resumed, cancel := h.hub.Subscribe(electionID, lastEventID)
+
+	var snapshot *services.ResultEvent
+	if !resumed {
+		snap, err := h.hub.Snapshot(electionID)
+		if err != nil {
+			cancel()
+			logger.Log.Errorf("❌ Results stream snapshot failed: %v", err)
+			return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+		}
+		snapshot = &snap
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	heartbeat := h.heartbeat
+	return c.SendStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+
+		ticker := time.NewTicker(heartbeat)
+		defer ticker.Stop()
+
+		fmt.Fprint(w, "retry: 2000\n\n")
+		if snapshot != nil {
+			writeResultEvent(w, *snapshot)
+		}
+		for _, ev := range missed {
+			writeResultEvent(w, ev)
+		}
+		if err := w.Flush(); err != nil {
+			return
+		}
+
+		for {
+			select {
+			case ev, ok := <-events:
+				if !ok {
+					// el hub descartó al cliente por lento; reconectará con Last-Event-ID
+					return
+				}
+				writeResultEvent(w, ev)
+			case now := <-ticker.C:
+				fmt.Fprintf(w, "event: heartbeat\ndata: {\"at\":%q}\n\n", now.Format(time.RFC3339))
+			}
+
+			// un error al vaciar el buffer significa que el cliente se desconectó
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+}
+
+func writeResultEvent(w *bufio.Writer, ev services.ResultEvent) {
+	data, err := json.Marshal(ev.Deltas)
+	if err != nil {
+		logger.Log.Errorf("❌ Results stream encode failed: %v", err)
+		return
+	}
+
+	name := "delta"
+	if ev.Snapshot {
+		name = "snapshot"
+	}
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.ID, name, data)
+}
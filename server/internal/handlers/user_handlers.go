@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"server/internal/dto"
+	"server/internal/services"
+	"server/pkgs/logger"
+)
+
+type UserHandler struct {
+	service services.UserService
+}
+
+func NewUserHandler(service services.UserService) *UserHandler {
+	return &UserHandler{service: service}
+}
+
+func (h *UserHandler) GetAll(c fiber.Ctx) (interface{}, string, error) {
+	users, err := h.service.GetAll(c.Query("rol"))
+	if err != nil {
+		logger.Log.Errorf("❌ GetAll users failed: %v", err)
+		return nil, err.Error(), fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	return users, "Usuarios obtenidos correctamente", nil
+}
+
+func (h *UserHandler) GetOne(c fiber.Ctx) (interface{}, string, error) {
+	user, err := h.service.GetOne(c.Params("id"))
+	if err != nil {
+		logger.Log.Errorf("❌ GetOne user failed: %v", err)
+		return nil, err.Error(), userError(err)
+	}
+
+	return user, "Usuario obtenido correctamente", nil
+}
+
+func (h *UserHandler) Create(c fiber.Ctx) (interface{}, string, error) {
+	userID, userRole, err := getAuthLocals(c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var req dto.CreateUserRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return nil, "Solicitud inválida", fiber.NewError(fiber.StatusBadRequest, "Body inválido: "+err.Error())
+	}
+
+	user, err := h.service.Create(req, userID, userRole)
+	if err != nil {
+		logger.Log.Errorf("❌ Create user failed: %v", err)
+		return nil, err.Error(), userError(err)
+	}
+
+	return user, "Usuario creado correctamente", nil
+}
+
+func (h *UserHandler) Update(c fiber.Ctx) (interface{}, string, error) {
+	userID, userRole, err := getAuthLocals(c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var req dto.UpdateUserRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return nil, "Solicitud inválida", fiber.NewError(fiber.StatusBadRequest, "Body inválido: "+err.Error())
+	}
+
+	user, err := h.service.Update(c.Params("id"), req, userID, userRole)
+	if err != nil {
+		logger.Log.Errorf("❌ Update user failed: %v", err)
+		return nil, err.Error(), userError(err)
+	}
+
+	return user, "Usuario actualizado correctamente", nil
+}
+
+func (h *UserHandler) ResetPassword(c fiber.Ctx) (interface{}, string, error) {
+	userID, userRole, err := getAuthLocals(c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var req dto.ResetPasswordRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return nil, "Solicitud inválida", fiber.NewError(fiber.StatusBadRequest, "Body inválido: "+err.Error())
+	}
+
+	if err := h.service.ResetPassword(c.Params("id"), req, userID, userRole); err != nil {
+		logger.Log.Errorf("❌ ResetPassword failed: %v", err)
+		return nil, err.Error(), userError(err)
+	}
+
+	return nil, "Contraseña restablecida correctamente", nil
+}
+
+func (h *UserHandler) Unlock(c fiber.Ctx) (interface{}, string, error) {
+	userID, userRole, err := getAuthLocals(c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	user, err := h.service.Unlock(c.Params("id"), userID, userRole)
+	if err != nil {
+		logger.Log.Errorf("❌ Unlock user failed: %v", err)
+		return nil, err.Error(), userError(err)
+	}
+
+	return user, "Usuario desbloqueado correctamente", nil
+}
+
+func (h *UserHandler) Invite(c fiber.Ctx) (interface{}, string, error) {
+	userID, userRole, err := getAuthLocals(c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	user, err := h.service.Invite(c.Params("id"), userID, userRole)
+	if err != nil {
+		logger.Log.Errorf("❌ Invite user failed: %v", err)
+		return nil, err.Error(), userError(err)
+	}
+
+	return user, "Invitación generada correctamente", nil
+}
+
+func userError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrUserNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrUnauthorizedAction):
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrSignupEmailTaken),
+		errors.Is(err, services.ErrUserSelfDeactivate),
+		errors.Is(err, services.ErrLastSuperAdmin):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	default:
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+}
@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v3"
 	"server/internal/dto"
+	"server/internal/models"
 	"server/internal/services"
 	"server/pkgs/logger"
 )
@@ -28,22 +31,30 @@ func getVoteAuthLocals(c fiber.Ctx) (string, string, error) {
 }
 
 func (h *VoteHandler) GetAll(c fiber.Ctx) (interface{}, string, error) {
-	votes, err := h.service.GetAll()
+	userID, userRole, err := getVoteAuthLocals(c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	votes, err := h.service.GetAll(c.Query("electionId"), userID, userRole)
 	if err != nil {
 		logger.Log.Errorf("❌ GetAll votes failed: %v", err)
-		return nil, err.Error(), fiber.NewError(fiber.StatusBadRequest, err.Error())
+		return nil, err.Error(), voteError(err)
 	}
 
 	return votes, "Votos obtenidos correctamente", nil
 }
 
 func (h *VoteHandler) GetByCandidate(c fiber.Ctx) (interface{}, string, error) {
-	candidateID := c.Params("candidateId")
+	userID, userRole, err := getVoteAuthLocals(c)
+	if err != nil {
+		return nil, "", err
+	}
 
-	votes, err := h.service.GetByCandidate(candidateID)
+	votes, err := h.service.GetByCandidate(c.Params("candidateId"), userID, userRole)
 	if err != nil {
 		logger.Log.Errorf("❌ GetByCandidate failed: %v", err)
-		return nil, err.Error(), fiber.NewError(fiber.StatusBadRequest, err.Error())
+		return nil, err.Error(), voteError(err)
 	}
 
 	return votes, "Votos del candidato obtenidos correctamente", nil
@@ -55,20 +66,100 @@ func (h *VoteHandler) Create(c fiber.Ctx) (interface{}, string, error) {
 		return nil, "", err
 	}
 
-	if userRole != "ADMIN" {
-		return nil, "No autorizado", fiber.NewError(fiber.StatusForbidden, "Solo ADMIN puede crear votos")
-	}
-
 	var req dto.CreateVoteRequest
 	if err := c.Bind().Body(&req); err != nil {
 		return nil, "Solicitud inválida", fiber.NewError(fiber.StatusBadRequest, "Body inválido: "+err.Error())
 	}
+	req.LockToken = c.Get("X-Mesa-Lock")
 
-	vote, err := h.service.Create(req, userID, userRole)
+	vote, err := h.service.Create(req, userID, userRole, c.IP())
 	if err != nil {
 		logger.Log.Errorf("❌ Create vote failed: %v", err)
-		return nil, err.Error(), fiber.NewError(fiber.StatusBadRequest, err.Error())
+		return nil, err.Error(), voteError(err)
+	}
+
+	switch vote.Status {
+	case string(models.CSpending):
+		return vote, "Captura registrada, pendiente de segunda digitación", nil
+	case string(models.CSdiscrepant):
+		return vote, "Las capturas no coinciden, la mesa pasa a revisión", nil
 	}
 
 	return vote, "Voto creado correctamente", nil
 }
+
+func (h *VoteHandler) Update(c fiber.Ctx) (interface{}, string, error) {
+	userID, userRole, err := getVoteAuthLocals(c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var req dto.UpdateVoteRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return nil, "Solicitud inválida", fiber.NewError(fiber.StatusBadRequest, "Body inválido: "+err.Error())
+	}
+
+	vote, err := h.service.Update(c.Params("id"), req, userID, userRole, c.IP())
+	if err != nil {
+		logger.Log.Errorf("❌ Update vote failed: %v", err)
+		return nil, err.Error(), voteError(err)
+	}
+
+	return vote, "Voto corregido correctamente", nil
+}
+
+func (h *VoteHandler) Annul(c fiber.Ctx) (interface{}, string, error) {
+	userID, userRole, err := getVoteAuthLocals(c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var req dto.AnnulVoteRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return nil, "Solicitud inválida", fiber.NewError(fiber.StatusBadRequest, "Body inválido: "+err.Error())
+	}
+
+	vote, err := h.service.Annul(c.Params("id"), req, userID, userRole, c.IP())
+	if err != nil {
+		logger.Log.Errorf("❌ Annul vote failed: %v", err)
+		return nil, err.Error(), voteError(err)
+	}
+
+	return vote, "Voto anulado correctamente", nil
+}
+
+func (h *VoteHandler) GetRevisions(c fiber.Ctx) (interface{}, string, error) {
+	userID, userRole, err := getVoteAuthLocals(c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	revisions, err := h.service.GetRevisions(c.Params("id"), userID, userRole)
+	if err != nil {
+		logger.Log.Errorf("❌ GetRevisions failed: %v", err)
+		return nil, err.Error(), voteError(err)
+	}
+
+	return revisions, "Historial del voto obtenido correctamente", nil
+}
+
+func voteError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrVoteNotFound), errors.Is(err, services.ErrCandidateNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrUnauthorized), errors.Is(err, services.ErrUnauthorizedAction),
+		errors.Is(err, services.ErrPartyRequired):
+		return fiber.NewError(fiber.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrVoteAnnulled),
+		errors.Is(err, services.ErrDuplicateVote),
+		errors.Is(err, services.ErrCaptureAlreadySubmitted),
+		errors.Is(err, services.ErrDiscrepancyPending):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrElectionLocked):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	case errors.Is(err, services.ErrMesaLockRequired):
+		return fiber.NewError(fiber.StatusLocked, err.Error())
+	default:
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+}
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v3"
+	"server/internal/dto"
+	"server/internal/services"
+	"server/pkgs/logger"
+)
+
+type WebhookHandler struct {
+	service services.WebhookService
+}
+
+func NewWebhookHandler(service services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+func (h *WebhookHandler) GetAll(c fiber.Ctx) (interface{}, string, error) {
+	hooks, err := h.service.GetAll()
+	if err != nil {
+		logger.Log.Errorf("❌ GetAll webhooks failed: %v", err)
+		return nil, err.Error(), webhookError(err)
+	}
+
+	return hooks, "Webhooks obtenidos correctamente", nil
+}
+
+func (h *WebhookHandler) GetOne(c fiber.Ctx) (interface{}, string, error) {
+	hook, err := h.service.GetOne(c.Params("id"))
+	if err != nil {
+		logger.Log.Errorf("❌ GetOne webhook failed: %v", err)
+		return nil, err.Error(), webhookError(err)
+	}
+
+	return hook, "Webhook obtenido correctamente", nil
+}
+
+func (h *WebhookHandler) Create(c fiber.Ctx) (interface{}, string, error) {
+	userID, _, err := getAuthLocals(c)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var req dto.CreateWebhookRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return nil, "Solicitud inválida", fiber.NewError(fiber.StatusBadRequest, "Body inválido o formato incorrecto: "+err.Error())
+	}
+
+	hook, err := h.service.Create(req, userID)
+	if err != nil {
+		logger.Log.Errorf("❌ Create webhook failed: %v", err)
+		return nil, err.Error(), webhookError(err)
+	}
+
+	return hook, "Webhook creado correctamente; guarda el secreto, no se volverá a mostrar", nil
+}
+
+func (h *WebhookHandler) Update(c fiber.Ctx) (interface{}, string, error) {
+	var req dto.UpdateWebhookRequest
+	if err := c.Bind().Body(&req); err != nil {
+		return nil, "Solicitud inválida", fiber.NewError(fiber.StatusBadRequest, "Body inválido o formato incorrecto: "+err.Error())
+	}
+
+	hook, err := h.service.Update(c.Params("id"), req)
+	if err != nil {
+		logger.Log.Errorf("❌ Update webhook failed: %v", err)
+		return nil, err.Error(), webhookError(err)
+	}
+
+	return hook, "Webhook actualizado correctamente", nil
+}
+
+func (h *WebhookHandler) Delete(c fiber.Ctx) (interface{}, string, error) {
+	if err := h.service.Delete(c.Params("id")); err != nil {
+		logger.Log.Errorf("❌ Delete webhook failed: %v", err)
+		return nil, err.Error(), webhookError(err)
+	}
+
+	return nil, "Webhook eliminado correctamente", nil
+}
+
+func (h *WebhookHandler) Test(c fiber.Ctx) (interface{}, string, error) {
+	result, err := h.service.Test(c.Params("id"))
+	if err != nil {
+		logger.Log.Errorf("❌ Test webhook failed: %v", err)
+		return nil, err.Error(), webhookError(err)
+	}
+
+	if !result.Delivered {
+		return result, "El receptor no aceptó el ping", nil
+	}
+	return result, "Ping entregado correctamente", nil
+}
+
+func (h *WebhookHandler) GetDeliveries(c fiber.Ctx) (interface{}, string, error) {
+	deliveries, err := h.service.GetDeliveries(c.Params("id"), c.Query("status"))
+	if err != nil {
+		logger.Log.Errorf("❌ GetDeliveries webhook failed: %v", err)
+		return nil, err.Error(), webhookError(err)
+	}
+
+	return deliveries, "Entregas obtenidas correctamente", nil
+}
+
+func (h *WebhookHandler) GetDeadLetters(c fiber.Ctx) (interface{}, string, error) {
+	deliveries, err := h.service.GetDeadLetters()
+	if err != nil {
+		logger.Log.Errorf("❌ GetDeadLetters webhooks failed: %v", err)
+		return nil, err.Error(), webhookError(err)
+	}
+
+	return deliveries, "Entregas descartadas obtenidas correctamente", nil
+}
+
+func (h *WebhookHandler) RetryDelivery(c fiber.Ctx) (interface{}, string, error) {
+	delivery, err := h.service.RetryDelivery(c.Params("id"))
+	if err != nil {
+		logger.Log.Errorf("❌ RetryDelivery webhook failed: %v", err)
+		return nil, err.Error(), webhookError(err)
+	}
+
+	return delivery, "Entrega devuelta a la cola", nil
+}
+
+func webhookError(err error) error {
+	switch {
+	case errors.Is(err, services.ErrWebhookNotFound), errors.Is(err, services.ErrWebhookDeliveryNotFound):
+		return fiber.NewError(fiber.StatusNotFound, err.Error())
+	case errors.Is(err, services.ErrWebhookDeliveryNotDead):
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	default:
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+}
@@ -8,15 +8,49 @@ type Rol string
 type TypePositions string
 type TypeCandidates string
 type TypeVote string
+type MesaStatus string
+type RevisionAction string
+type CaptureStatus string
+type DiscrepancyStatus string
+type ElectionStatus string
 
 const (
-	RolAdmin    Rol            = "ADMIN"
+	RolSuperAdmin Rol = "SUPERADMIN"
+	RolAdmin      Rol = "ADMIN"
+	RolDigitador  Rol = "DIGITADOR"
+	RolSupervisor Rol = "SUPERVISOR"
+	RolPersonero  Rol = "PERSONERO"
+	RolObserver   Rol = "OBSERVER"
+
 	TAposition  TypePositions  = "AUTORIDAD"
 	TIposition  TypePositions  = "ORGANO"
 	TCcandidate TypeCandidates = "CANDIDATO"
 	TCnull      TypeCandidates = "NULL"
 	TVpersonnel TypeVote       = "DOCENTES"
 	TVpublic    TypeVote       = "PUBLICO"
+
+	MSpending  MesaStatus = "PENDING"
+	MSopen     MesaStatus = "OPEN"
+	MSclosed   MesaStatus = "CLOSED"
+	MScounted  MesaStatus = "COUNTED"
+	MSannulled MesaStatus = "ANNULLED"
+
+	RAcorrection RevisionAction = "CORRECTION"
+	RAannulment  RevisionAction = "ANNULMENT"
+
+	CSpending    CaptureStatus = "PENDING"
+	CSconfirmed  CaptureStatus = "CONFIRMED"
+	CSdiscrepant CaptureStatus = "DISCREPANT"
+	CSrejected   CaptureStatus = "REJECTED"
+
+	DSopen     DiscrepancyStatus = "OPEN"
+	DSresolved DiscrepancyStatus = "RESOLVED"
+
+	ESdraft      ElectionStatus = "DRAFT"
+	ESconfigured ElectionStatus = "CONFIGURED"
+	ESopen       ElectionStatus = "OPEN"
+	ESclosed     ElectionStatus = "CLOSED"
+	EScertified  ElectionStatus = "CERTIFIED"
 )
 
 type Base struct {
@@ -27,19 +61,57 @@ type Base struct {
 
 type User struct {
 	Base
-	Name     string `gorm:"type:varchar(120);not null"`
-	Email    string `gorm:"uniqueIndex;not null"`
-	Password string `gorm:"type:varchar(255)"`
-	Rol      Rol    `gorm:"type:varchar(30);default:'ADMIN'"`
-	IsActive bool   `gorm:"default:true"`
+	Name     string  `gorm:"type:varchar(120);not null"`
+	Email    string  `gorm:"uniqueIndex;not null"`
+	Password string  `gorm:"type:varchar(255)"`
+	Rol      Rol     `gorm:"type:varchar(30);default:'ADMIN'"`
+	Party    *string `gorm:"type:varchar(120)"`
+	IsActive bool    `gorm:"default:true"`
+
+	// TOTP: el secreto se guarda al iniciar el enrolamiento y TOTPEnabled se
+	// activa solo tras verificar el primer código. TOTPLastStep evita reutilizar
+	// un código dentro de su ventana de validez.
+	TOTPSecret   *string `gorm:"type:varchar(64)"`
+	TOTPEnabled  bool    `gorm:"default:false"`
+	TOTPLastStep int64   `gorm:"default:0"`
+
+	// Bloqueo temporal tras FailedAttempts fallos consecutivos de contraseña
+	FailedAttempts int `gorm:"default:0"`
+	LockedUntil    *time.Time
+
+	// MustChangePassword: la contraseña la eligió un administrador; el access
+	// token solo sirve para cambiarla hasta que el usuario lo haga
+	MustChangePassword bool `gorm:"default:false"`
+}
+
+// Invitation: enlace de un solo uso para que un usuario nuevo fije su
+// contraseña inicial. Solo se guarda el hash del token.
+type Invitation struct {
+	Base
+	UserID    string    `gorm:"type:uuid;not null;index"`
+	User      User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	TokenHash string    `gorm:"type:varchar(64);uniqueIndex;not null"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedBy string `gorm:"type:uuid"`
+}
+
+// LoginAttempt: registro de cada intento de login; alimenta los límites por IP
+// y por email con ventana deslizante
+type LoginAttempt struct {
+	Base
+	Email   string `gorm:"type:varchar(255);not null;index:idx_login_attempt_email"`
+	IP      string `gorm:"type:varchar(64);not null;index:idx_login_attempt_ip"`
+	Success bool   `gorm:"not null"`
+	Reason  string `gorm:"type:varchar(60)"`
 }
 
 type Account struct {
 	ID                string `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
 	UserID            string `gorm:"type:uuid;not null"`
 	Type              string
-	Provider          string
-	ProviderAccountID string
+	Provider          string  `gorm:"uniqueIndex:idx_account_provider"`
+	ProviderAccountID string  `gorm:"uniqueIndex:idx_account_provider"`
 	RefreshToken      *string `gorm:"type:text"`
 	AccessToken       *string `gorm:"type:text"`
 	ExpiresAt         *int
@@ -50,6 +122,29 @@ type Account struct {
 	User              User `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
 }
 
+// RefreshToken: token opaco de renovación; solo se guarda su hash SHA-256.
+// Todos los tokens obtenidos a partir de un mismo login comparten FamilyID,
+// de modo que reutilizar uno ya rotado revoca la familia completa.
+type RefreshToken struct {
+	Base
+	UserID       string    `gorm:"type:uuid;not null;index"`
+	User         User      `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	TokenHash    string    `gorm:"type:varchar(64);uniqueIndex;not null"`
+	FamilyID     string    `gorm:"type:uuid;not null;index"`
+	ExpiresAt    time.Time `gorm:"not null"`
+	RevokedAt    *time.Time
+	ReplacedByID *string `gorm:"type:uuid"`
+}
+
+// RecoveryCode: código de recuperación 2FA de un solo uso (solo se guarda el hash)
+type RecoveryCode struct {
+	Base
+	UserID   string `gorm:"type:uuid;not null;index"`
+	User     User   `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	CodeHash string `gorm:"type:varchar(64);not null;index"`
+	UsedAt   *time.Time
+}
+
 type Candidate struct {
 	Base
 	Name          string         `gorm:"type:varchar(120);not null"`
@@ -58,6 +153,7 @@ type Candidate struct {
 	Image         *Image         `gorm:"foreignKey:ImageID;constraint:OnDelete:SET NULL"`
 	IsActive      bool           `gorm:"default:true"`
 	TypeCandidate TypeCandidates `gorm:"not null,default:CANDIDATO"`
+	Party         *string        `gorm:"type:varchar(120)"`
 
 	PositionID *string   `gorm:"type:uuid"`
 	Position   *Position `gorm:"foreignKey:PositionID;constraint:OnDelete:SET NULL"`
@@ -70,9 +166,22 @@ type Image struct {
 	URL      string `gorm:"type:varchar(500)"`
 }
 
+type Election struct {
+	Base
+	Name        string         `gorm:"type:varchar(255);not null;uniqueIndex"`
+	Description *string        `gorm:"type:text"`
+	StartDate   *time.Time     `gorm:"type:timestamptz"`
+	EndDate     *time.Time     `gorm:"type:timestamptz"`
+	Status      ElectionStatus `gorm:"type:varchar(20);not null;default:'DRAFT'"`
+
+	Positions []Position `gorm:"foreignKey:ElectionID"`
+}
+
 type Position struct {
 	Base
-	Name            string        `gorm:"type:varchar(255);not null;unique"`
+	ElectionID      string        `gorm:"type:uuid;not null;uniqueIndex:idx_position_election_name"`
+	Election        *Election     `gorm:"foreignKey:ElectionID;constraint:OnDelete:RESTRICT"`
+	Name            string        `gorm:"type:varchar(255);not null;uniqueIndex:idx_position_election_name"`
 	Description     *string       `gorm:"type:text"`
 	TypePosition    TypePositions `gorm:"not null,default:ORGANO"`
 	TotalVotes      int           `gorm:"not null;default:0"`
@@ -81,11 +190,165 @@ type Position struct {
 	Candidates []Candidate `gorm:"foreignKey:PositionID"`
 }
 
+type Mesa struct {
+	Base
+	ElectionID       string     `gorm:"type:uuid;not null;uniqueIndex:idx_mesa_election_number"`
+	Election         *Election  `gorm:"foreignKey:ElectionID;constraint:OnDelete:RESTRICT"`
+	Number           string     `gorm:"type:varchar(120);not null;uniqueIndex:idx_mesa_election_number"`
+	Location         *string    `gorm:"type:varchar(255)"`
+	ElectorsDocentes int        `gorm:"not null;default:0"`
+	ElectorsPublico  int        `gorm:"not null;default:0"`
+	Status           MesaStatus `gorm:"type:varchar(20);not null;default:'PENDING'"`
+}
+
+type Acta struct {
+	Base
+	MesaID      string   `gorm:"type:uuid;not null;uniqueIndex:idx_acta_mesa_type"`
+	Mesa        Mesa     `gorm:"foreignKey:MesaID;constraint:OnDelete:RESTRICT"`
+	TypeVote    TypeVote `gorm:"type:varchar(20);not null;uniqueIndex:idx_acta_mesa_type"`
+	BallotsCast int      `gorm:"not null;default:0"`
+	UserID      string   `gorm:"type:uuid;not null"`
+
+	// SecondUserID: con doble digitación, quien digitó el acta por segunda vez
+	SecondUserID *string `gorm:"type:uuid"`
+
+	Positions []ActaPosition `gorm:"foreignKey:ActaID"`
+}
+
+type ActaPosition struct {
+	Base
+	ActaID     string   `gorm:"type:uuid;not null;uniqueIndex:idx_acta_position"`
+	PositionID string   `gorm:"type:uuid;not null;uniqueIndex:idx_acta_position"`
+	Position   Position `gorm:"foreignKey:PositionID;constraint:OnDelete:CASCADE"`
+	BlankVotes int      `gorm:"not null;default:0"`
+	NullVotes  int      `gorm:"not null;default:0"`
+}
+
 type Vote struct {
 	Base
-	Mesa        string    `gorm:"type:varchar(120);not null"`
+	MesaID      string    `gorm:"type:uuid;not null"`
+	Mesa        Mesa      `gorm:"foreignKey:MesaID;constraint:OnDelete:RESTRICT"`
+	ActaID      *string   `gorm:"type:uuid"`
+	Acta        *Acta     `gorm:"foreignKey:ActaID;constraint:OnDelete:SET NULL"`
 	CandidateID string    `gorm:"type:uuid;not null"`
 	Candidate   Candidate `gorm:"foreignKey:CandidateID;constraint:OnDelete:CASCADE"`
 	TypeVote    TypeVote  `gorm:"not null,default:PUBLICO"`
 	Vote        int       `gorm:"not null;default:0"`
+	Revision    int       `gorm:"not null;default:1"`
+	IsAnnulled  bool      `gorm:"default:false"`
+}
+
+type VoteRevision struct {
+	Base
+	VoteID       string         `gorm:"type:uuid;not null;index"`
+	Vote         Vote           `gorm:"foreignKey:VoteID;constraint:OnDelete:CASCADE"`
+	Revision     int            `gorm:"not null"`
+	PreviousVote int            `gorm:"not null"`
+	NewVote      int            `gorm:"not null"`
+	Action       RevisionAction `gorm:"type:varchar(20);not null"`
+	Reason       string         `gorm:"type:text;not null"`
+	UserID       string         `gorm:"type:uuid;not null"`
+}
+
+type VoteCapture struct {
+	Base
+	MesaID        string        `gorm:"type:uuid;not null;index"`
+	Mesa          Mesa          `gorm:"foreignKey:MesaID;constraint:OnDelete:CASCADE"`
+	CandidateID   string        `gorm:"type:uuid;not null"`
+	Candidate     Candidate     `gorm:"foreignKey:CandidateID;constraint:OnDelete:CASCADE"`
+	TypeVote      TypeVote      `gorm:"type:varchar(20);not null"`
+	Vote          int           `gorm:"not null;default:0"`
+	UserID        string        `gorm:"type:uuid;not null"`
+	Status        CaptureStatus `gorm:"type:varchar(20);not null;default:'PENDING'"`
+	VoteID        *string       `gorm:"type:uuid"`
+	DiscrepancyID *string       `gorm:"type:uuid"`
+}
+
+type Discrepancy struct {
+	Base
+	MesaID       string            `gorm:"type:uuid;not null;index"`
+	Mesa         Mesa              `gorm:"foreignKey:MesaID;constraint:OnDelete:CASCADE"`
+	CandidateID  string            `gorm:"type:uuid;not null"`
+	Candidate    Candidate         `gorm:"foreignKey:CandidateID;constraint:OnDelete:CASCADE"`
+	TypeVote     TypeVote          `gorm:"type:varchar(20);not null"`
+	Status       DiscrepancyStatus `gorm:"type:varchar(20);not null;default:'OPEN'"`
+	ResolvedBy   *string           `gorm:"type:uuid"`
+	ResolvedVote *int
+	Reason       *string `gorm:"type:text"`
+	VoteID       *string `gorm:"type:uuid"`
+
+	Captures []VoteCapture `gorm:"foreignKey:DiscrepancyID"`
+}
+
+// ResultCertificate: resultados congelados al certificar la elección. Document
+// es el JSON canónico firmado; PublicKey permite verificarlo sin la API.
+type ResultCertificate struct {
+	Base
+	ElectionID   string    `gorm:"type:uuid;not null;uniqueIndex"`
+	Election     *Election `gorm:"foreignKey:ElectionID;constraint:OnDelete:RESTRICT"`
+	Document     string    `gorm:"type:text;not null"`
+	DocumentHash string    `gorm:"type:varchar(64);not null"`
+	Signature    string    `gorm:"type:text;not null"`
+	KeyID        string    `gorm:"type:varchar(64);not null"`
+	PublicKey    string    `gorm:"type:text;not null"`
+	CertifiedBy  string    `gorm:"type:uuid;not null"`
+}
+
+// AuditEvent: registro inmutable de una mutación. Cada evento guarda el hash
+// del anterior, de modo que alterar o borrar uno rompe la cadena.
+type AuditEvent struct {
+	ID         string    `gorm:"type:uuid;default:gen_random_uuid();primaryKey"`
+	Seq        int64     `gorm:"not null;uniqueIndex"`
+	CreatedAt  time.Time `gorm:"type:timestamptz;not null;index"`
+	ActorID    string    `gorm:"type:varchar(64);index"`
+	ActorRole  string    `gorm:"type:varchar(20)"`
+	IP         string    `gorm:"type:varchar(64)"`
+	Action     string    `gorm:"type:varchar(60);not null;index"`
+	EntityType string    `gorm:"type:varchar(40);not null;index:idx_audit_entity"`
+	EntityID   string    `gorm:"type:varchar(64);not null;index:idx_audit_entity"`
+	// Before/After se guardan como texto y no jsonb para que los bytes
+	// hasheados sean exactamente los que se leen al verificar
+	Before   *string `gorm:"type:text"`
+	After    *string `gorm:"type:text"`
+	PrevHash string  `gorm:"type:varchar(64);not null"`
+	Hash     string  `gorm:"type:varchar(64);not null;uniqueIndex"`
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WDpending   WebhookDeliveryStatus = "PENDING"
+	WDdelivered WebhookDeliveryStatus = "DELIVERED"
+	WDdead      WebhookDeliveryStatus = "DEAD"
+)
+
+// Webhook: destino externo de eventos. Secret se guarda en claro porque hace
+// falta para firmar cada entrega; Events es una lista separada por comas y
+// vacía significa todos.
+type Webhook struct {
+	Base
+	Name      string `gorm:"type:varchar(120);not null"`
+	URL       string `gorm:"type:varchar(500);not null"`
+	Secret    string `gorm:"type:varchar(128);not null"`
+	Events    string `gorm:"type:text"`
+	IsActive  bool   `gorm:"default:true"`
+	CreatedBy string `gorm:"type:uuid"`
+}
+
+// WebhookDelivery: cola persistente de entregas. Se inserta en la misma
+// transacción que el cambio que origina el evento; las que agotan los
+// reintentos quedan DEAD como lista de mensajes fallidos.
+type WebhookDelivery struct {
+	Base
+	WebhookID      string                `gorm:"type:uuid;not null;index"`
+	Webhook        Webhook               `gorm:"foreignKey:WebhookID;constraint:OnDelete:CASCADE"`
+	EventID        string                `gorm:"type:uuid;not null;index"`
+	EventType      string                `gorm:"type:varchar(60);not null"`
+	Payload        string                `gorm:"type:text;not null"`
+	Status         WebhookDeliveryStatus `gorm:"type:varchar(20);not null;default:'PENDING';index:idx_webhook_delivery_due"`
+	NextAttemptAt  time.Time             `gorm:"type:timestamptz;not null;index:idx_webhook_delivery_due"`
+	Attempts       int                   `gorm:"not null;default:0"`
+	LastStatusCode int
+	LastError      *string `gorm:"type:text"`
+	DeliveredAt    *time.Time
 }
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"gorm.io/gorm"
+	"server/internal/handlers"
+	"server/internal/services"
+	"server/pkgs/httpwrap"
+	"server/pkgs/middleware"
+)
+
+func RegisterAuditRoutes(app *fiber.App, db *gorm.DB) {
+	auditService := services.NewAuditService(db)
+	auditHandler := handlers.NewAuditHandler(auditService)
+
+	auditGroup := app.Group("/audit", middleware.AuthRequired(), middleware.RequirePermission(middleware.PermAuditRead))
+	{
+		auditGroup.Get("/", httpwrap.Wrap(auditHandler.GetAll))
+		auditGroup.Get("/verify", httpwrap.Wrap(auditHandler.Verify))
+	}
+
+	println("✅ Audit routes registered")
+}
@@ -6,17 +6,53 @@ import (
 	"server/internal/handlers"
 	"server/internal/services"
 	"server/pkgs/httpwrap"
+	"server/pkgs/logger"
+	"server/pkgs/middleware"
 	"server/pkgs/security"
 
 	"github.com/gofiber/fiber/v3"
 )
 
 func RegisterAuthRoutes(app *fiber.App) {
+	jwtSvc, err := config.GetJWTService()
+	if err != nil {
+		logger.Log.Fatalf("❌ Error configurando JWT: %v", err)
+	}
+
 	argon := security.NewArgon2Service()
-	authSvc := services.NewAuthService(config.DB, argon)
+	policy, err := config.GetPasswordPolicy()
+	if err != nil {
+		logger.Log.Fatalf("❌ Error cargando la política de contraseñas: %v", err)
+	}
+
+	cfg := config.GetConfig()
+	google := security.NewGoogleVerifier(cfg.GoogleClientID, cfg.GoogleJWKSURL, cfg.GoogleJWKSFile)
+	authSvc := services.NewAuthService(config.DB, argon, google, jwtSvc, services.AuthSettings{
+		RefreshTTL:       cfg.RefreshTokenTTL,
+		MFAIssuer:        cfg.JWTIssuer,
+		MFARequiredRoles: cfg.MFARequiredRoles,
+
+		LoginWindow:       cfg.LoginWindow,
+		LoginIPLimit:      cfg.LoginIPLimit,
+		LoginEmailLimit:   cfg.LoginEmailLimit,
+		LoginMaxFailures:  cfg.LoginMaxFailures,
+		LoginLockDuration: cfg.LoginLockDuration,
+
+		PasswordPolicy: policy,
+	})
 	authH := handlers.NewAuthHandler(authSvc)
 
 	app.Post("/auth/signin", httpwrap.Wrap(authH.Signin))
+	app.Post("/auth/refresh", httpwrap.Wrap(authH.Refresh))
+	app.Post("/auth/logout", httpwrap.Wrap(authH.Logout))
+	app.Post("/auth/logout-all", middleware.AuthAllowPasswordChange(), httpwrap.Wrap(authH.LogoutAll))
+	app.Post("/auth/password", middleware.AuthAllowPasswordChange(), httpwrap.Wrap(authH.ChangePassword))
+	app.Post("/auth/accept-invite", httpwrap.Wrap(authH.AcceptInvite))
+
+	app.Post("/auth/2fa/enroll", middleware.AuthOrMFAEnrollment(), httpwrap.Wrap(authH.EnrollMFA))
+	app.Post("/auth/2fa/verify", middleware.AuthOrMFAEnrollment(), httpwrap.Wrap(authH.VerifyMFA))
+	app.Post("/auth/2fa/disable", middleware.AuthRequired(), httpwrap.Wrap(authH.DisableMFA))
+	app.Post("/auth/2fa/challenge", httpwrap.Wrap(authH.ChallengeMFA))
 
-	println("✅ Auth routes registered: POST /auth/signin")
+	println("✅ Auth routes registered: POST /auth/signin, /auth/refresh, /auth/logout, /auth/logout-all, /auth/password, /auth/accept-invite, /auth/2fa/*")
 }
@@ -14,16 +14,15 @@ func RegisterCandidateRoutes(app *fiber.App, db *gorm.DB) {
 	imageService := services.NewImageService(db)
 	candidateHandler := handlers.NewCandidateHandler(candidateService, imageService)
 
-	app.Get("/candidates", httpwrap.Wrap(candidateHandler.GetAll))
-
 	candidateGroup := app.Group("/candidates", middleware.AuthRequired())
 	{
-		candidateGroup.Get("/:id", httpwrap.Wrap(candidateHandler.GetOne))
-		candidateGroup.Post("/", httpwrap.Wrap(candidateHandler.Create))
-		candidateGroup.Patch("/:id", httpwrap.Wrap(candidateHandler.Update))
-		candidateGroup.Delete("/:id", httpwrap.Wrap(candidateHandler.Delete))
+		candidateGroup.Get("/", middleware.RequirePermission(middleware.PermRead), httpwrap.Wrap(candidateHandler.GetAll))
+		candidateGroup.Get("/:id", middleware.RequirePermission(middleware.PermRead), httpwrap.Wrap(candidateHandler.GetOne))
+		candidateGroup.Post("/", middleware.RequirePermission(middleware.PermCandidatesManage), httpwrap.Wrap(candidateHandler.Create))
+		candidateGroup.Patch("/:id", middleware.RequirePermission(middleware.PermCandidatesManage), httpwrap.Wrap(candidateHandler.Update))
+		candidateGroup.Delete("/:id", middleware.RequirePermission(middleware.PermCandidatesManage), httpwrap.Wrap(candidateHandler.Delete))
 
-		candidateGroup.Get("/:id/position", httpwrap.Wrap(candidateHandler.GetPosition))
+		candidateGroup.Get("/:id/position", middleware.RequirePermission(middleware.PermRead), httpwrap.Wrap(candidateHandler.GetPosition))
 	}
 	println("✅ Candidate routes registered")
 }
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"gorm.io/gorm"
+	"server/internal/handlers"
+	"server/internal/services"
+	"server/pkgs/httpwrap"
+	"server/pkgs/middleware"
+)
+
+func RegisterDiscrepancyRoutes(app *fiber.App, db *gorm.DB, resultHub *services.ResultHub) {
+	discrepancyService := services.NewDiscrepancyService(db, resultHub)
+	discrepancyHandler := handlers.NewDiscrepancyHandler(discrepancyService)
+
+	discrepancyGroup := app.Group("/discrepancies", middleware.AuthRequired())
+	{
+		discrepancyGroup.Get("/", middleware.RequirePermission(middleware.PermDiscrepanciesRead), httpwrap.Wrap(discrepancyHandler.GetAll))
+		discrepancyGroup.Post("/:id/resolve", middleware.RequirePermission(middleware.PermDiscrepanciesResolve), httpwrap.Wrap(discrepancyHandler.Resolve))
+	}
+
+	println("✅ Discrepancy routes registered")
+}
@@ -0,0 +1,40 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"gorm.io/gorm"
+	"server/internal/config"
+	"server/internal/handlers"
+	"server/internal/services"
+	"server/pkgs/httpwrap"
+	"server/pkgs/logger"
+	"server/pkgs/middleware"
+)
+
+func RegisterElectionRoutes(app *fiber.App, db *gorm.DB) {
+	signer, err := config.GetCertificationSigner()
+	if err != nil {
+		logger.Log.Fatalf("❌ Error cargando la clave de certificación: %v", err)
+	}
+	if signer == nil {
+		logger.Log.Warn("⚠️ CERT_SIGNING_KEY_FILE no configurado: no se podrán certificar elecciones")
+	}
+
+	electionService := services.NewElectionService(db, signer)
+	electionHandler := handlers.NewElectionHandler(electionService)
+
+	app.Get("/elections", httpwrap.Wrap(electionHandler.GetAll))
+	app.Get("/elections/:id", httpwrap.Wrap(electionHandler.GetOne))
+	app.Get("/elections/:id/certificate", httpwrap.Wrap(electionHandler.GetCertificate))
+
+	electionGroup := app.Group("/elections", middleware.AuthRequired(), middleware.RequirePermission(middleware.PermElectionsManage))
+	{
+		electionGroup.Post("/", httpwrap.Wrap(electionHandler.Create))
+		electionGroup.Patch("/:id", httpwrap.Wrap(electionHandler.Update))
+		electionGroup.Delete("/:id", httpwrap.Wrap(electionHandler.Delete))
+		electionGroup.Post("/:id/transition", httpwrap.Wrap(electionHandler.Transition))
+		electionGroup.Post("/:id/certify", httpwrap.Wrap(electionHandler.Certify))
+	}
+
+	println("✅ Election routes registered")
+}
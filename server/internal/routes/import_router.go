@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"gorm.io/gorm"
+	"server/internal/handlers"
+	"server/internal/services"
+	"server/pkgs/middleware"
+)
+
+func RegisterImportRoutes(app *fiber.App, db *gorm.DB) {
+	importService := services.NewImportService(db)
+	importHandler := handlers.NewImportHandler(importService)
+
+	importGroup := app.Group("/import", middleware.AuthRequired())
+	{
+		importGroup.Post("/positions", middleware.RequirePermission(middleware.PermPositionsManage), importHandler.Positions)
+		importGroup.Post("/candidates", middleware.RequirePermission(middleware.PermCandidatesManage), importHandler.Candidates)
+	}
+
+	println("✅ Import routes registered")
+}
@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"gorm.io/gorm"
+	"server/internal/config"
+	"server/internal/handlers"
+	"server/internal/services"
+	"server/pkgs/logger"
+	"server/pkgs/middleware"
+)
+
+func RegisterMesaLockRoutes(app *fiber.App, db *gorm.DB, mesaLocks *services.MesaLockManager) {
+	jwtSvc, err := config.GetJWTService()
+	if err != nil {
+		logger.Log.Fatalf("❌ Error configurando JWT: %v", err)
+	}
+
+	sessionService := services.NewSessionService(db, jwtSvc)
+	mesaLockHandler := handlers.NewMesaLockHandler(mesaLocks, sessionService)
+
+	app.Get("/ws/mesas",
+		middleware.AuthWebSocket(),
+		middleware.RequirePermission(middleware.PermVotesCreate),
+		mesaLockHandler.Connect,
+	)
+
+	println("✅ Mesa lock routes registered")
+}
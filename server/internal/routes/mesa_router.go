@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"gorm.io/gorm"
+	"server/internal/config"
+	"server/internal/handlers"
+	"server/internal/services"
+	"server/pkgs/httpwrap"
+	"server/pkgs/middleware"
+)
+
+func RegisterMesaRoutes(app *fiber.App, db *gorm.DB, resultHub *services.ResultHub, mesaLocks services.MesaLockChecker) {
+	mesaService := services.NewMesaService(db, resultHub)
+	actaService := services.NewActaService(db, config.GetConfig().DoubleEntry, resultHub, mesaLocks)
+	mesaHandler := handlers.NewMesaHandler(mesaService)
+	actaHandler := handlers.NewActaHandler(actaService)
+
+	app.Get("/mesas", httpwrap.Wrap(mesaHandler.GetAll))
+	app.Get("/mesas/:id", httpwrap.Wrap(mesaHandler.GetOne))
+	mesaGroup := app.Group("/mesas", middleware.AuthRequired())
+	{
+		mesaGroup.Get("/:id/acta", middleware.RequirePermission(middleware.PermRead), httpwrap.Wrap(actaHandler.GetByMesa))
+		mesaGroup.Post("/", middleware.RequirePermission(middleware.PermMesasManage), httpwrap.Wrap(mesaHandler.Create))
+		mesaGroup.Patch("/:id", middleware.RequirePermission(middleware.PermMesasManage), httpwrap.Wrap(mesaHandler.Update))
+		mesaGroup.Delete("/:id", middleware.RequirePermission(middleware.PermMesasManage), httpwrap.Wrap(mesaHandler.Delete))
+
+		mesaGroup.Post("/:id/acta", middleware.RequirePermission(middleware.PermVotesCreate), httpwrap.Wrap(actaHandler.Submit))
+	}
+
+	println("✅ Mesa routes registered")
+}
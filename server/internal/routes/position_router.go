@@ -15,7 +15,7 @@ func RegisterPositionRoutes(app *fiber.App, db *gorm.DB) {
 
 	app.Get("/positions", httpwrap.Wrap(positionHandler.GetAll))
 
-	positionGroup := app.Group("/positions", middleware.AuthRequired())
+	positionGroup := app.Group("/positions", middleware.AuthRequired(), middleware.RequirePermission(middleware.PermPositionsManage))
 	{
 		positionGroup.Post("/", httpwrap.Wrap(positionHandler.Create))
 		positionGroup.Patch("/:id", httpwrap.Wrap(positionHandler.Update))
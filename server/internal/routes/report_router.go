@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"gorm.io/gorm"
+	"server/internal/handlers"
+	"server/internal/services"
+	"server/pkgs/middleware"
+)
+
+func RegisterReportRoutes(app *fiber.App, db *gorm.DB) {
+	reportService := services.NewReportService(db)
+	reportHandler := handlers.NewReportHandler(reportService)
+
+	// mismos permisos que /results: el acta muestra los mismos datos
+	reportGroup := app.Group("/reports", middleware.AuthRequired(), middleware.RequirePermission(middleware.PermRead))
+	{
+		reportGroup.Get("/positions/:id.pdf", reportHandler.PositionPDF)
+		reportGroup.Get("/elections/:id.pdf", reportHandler.ElectionPDF)
+	}
+
+	println("✅ Report routes registered")
+}
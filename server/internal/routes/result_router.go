@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"gorm.io/gorm"
+	"server/internal/config"
+	"server/internal/handlers"
+	"server/internal/services"
+	"server/pkgs/httpwrap"
+	"server/pkgs/middleware"
+)
+
+func RegisterResultRoutes(app *fiber.App, db *gorm.DB, resultHub *services.ResultHub) {
+	resultService := services.NewResultService(db)
+	resultHandler := handlers.NewResultHandler(resultService, resultHub, config.GetConfig().ResultsStreamHeartbeat)
+	exportHandler := handlers.NewExportHandler(services.NewExportService(db))
+
+	// los resultados son agregados por candidato: todo rol con PermRead los
+	// ve completos, sin limitar al PERSONERO a su partido
+	resultGroup := app.Group("/results", middleware.AuthRequired(), middleware.RequirePermission(middleware.PermRead))
+	{
+		resultGroup.Get("/", handlers.Negotiate(httpwrap.Wrap(resultHandler.GetAll), exportHandler.Results))
+		resultGroup.Get("/stream", resultHandler.Stream)
+		resultGroup.Get("/positions/:id", handlers.Negotiate(httpwrap.Wrap(resultHandler.GetByPosition), exportHandler.PositionResults))
+	}
+
+	println("✅ Result routes registered")
+}
@@ -3,6 +3,8 @@ package routes
 import (
 	"github.com/gofiber/fiber/v3"
 	"gorm.io/gorm"
+	"server/internal/config"
+	"server/internal/services"
 )
 
 func RegisterRoutes(app *fiber.App, db *gorm.DB) {
@@ -11,9 +13,41 @@ func RegisterRoutes(app *fiber.App, db *gorm.DB) {
 		return c.JSON(fiber.Map{"status": "ok"})
 	})
 
+	// resultHub: un único hub por proceso para que todos los servicios que
+	// escriben votos alimenten el mismo stream de resultados
+	resultHub := services.NewResultHub(db, config.GetConfig().ResultsStreamCoalesce)
+	// mesaLocks: bloqueos de edición compartidos entre el WebSocket, POST /votes
+	// y POST /mesas/:id/acta
+	mesaLocks := services.NewMesaLockManager(config.GetConfig().MesaLockTTL)
+	// lockChecker: con MESA_LOCK_REQUIRED=false no se exige el bloqueo
+	// (interfaz nil, no puntero nil)
+	var lockChecker services.MesaLockChecker
+	if config.GetConfig().MesaLockRequired {
+		lockChecker = mesaLocks
+	}
+	// webhookDispatcher: entrega en segundo plano la cola persistente de webhooks
+	webhookDispatcher := services.NewWebhookDispatcher(db, services.WebhookDispatcherConfig{
+		Timeout:      config.GetConfig().WebhookTimeout,
+		PollInterval: config.GetConfig().WebhookPollInterval,
+		RetryBase:    config.GetConfig().WebhookRetryBase,
+		RetryMax:     config.GetConfig().WebhookRetryMax,
+		MaxAttempts:  config.GetConfig().WebhookMaxAttempts,
+		AllowPrivate: config.GetConfig().WebhookAllowPrivate,
+	})
+
 	RegisterAuthRoutes(app)
+	RegisterUserRoutes(app, db)
+	RegisterElectionRoutes(app, db)
 	RegisterPositionRoutes(app, db)
 	RegisterCandidateRoutes(app, db)
-	RegisterVoteRoutes(app, db)
+	RegisterImportRoutes(app, db)
+	RegisterMesaRoutes(app, db, resultHub, lockChecker)
+	RegisterVoteRoutes(app, db, resultHub, lockChecker)
+	RegisterMesaLockRoutes(app, db, mesaLocks)
+	RegisterDiscrepancyRoutes(app, db, resultHub)
 	RegisterImageRoutes(app, db)
+	RegisterResultRoutes(app, db, resultHub)
+	RegisterReportRoutes(app, db)
+	RegisterAuditRoutes(app, db)
+	RegisterWebhookRoutes(app, db, webhookDispatcher)
 }
@@ -0,0 +1,36 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"gorm.io/gorm"
+	"server/internal/config"
+	"server/internal/handlers"
+	"server/internal/services"
+	"server/pkgs/httpwrap"
+	"server/pkgs/logger"
+	"server/pkgs/middleware"
+	"server/pkgs/security"
+)
+
+func RegisterUserRoutes(app *fiber.App, db *gorm.DB) {
+	policy, err := config.GetPasswordPolicy()
+	if err != nil {
+		logger.Log.Fatalf("❌ Error cargando la política de contraseñas: %v", err)
+	}
+
+	userService := services.NewUserService(db, security.NewArgon2Service(), policy, config.GetConfig().InviteTTL)
+	userHandler := handlers.NewUserHandler(userService)
+
+	userGroup := app.Group("/users", middleware.AuthRequired(), middleware.RequirePermission(middleware.PermUsersManage))
+	{
+		userGroup.Get("/", httpwrap.Wrap(userHandler.GetAll))
+		userGroup.Get("/:id", httpwrap.Wrap(userHandler.GetOne))
+		userGroup.Post("/", httpwrap.Wrap(userHandler.Create))
+		userGroup.Patch("/:id", httpwrap.Wrap(userHandler.Update))
+		userGroup.Post("/:id/reset-password", httpwrap.Wrap(userHandler.ResetPassword))
+		userGroup.Post("/:id/unlock", httpwrap.Wrap(userHandler.Unlock))
+		userGroup.Post("/:id/invite", httpwrap.Wrap(userHandler.Invite))
+	}
+
+	println("✅ User routes registered")
+}
@@ -3,23 +3,27 @@ package routes
 import (
 	"github.com/gofiber/fiber/v3"
 	"gorm.io/gorm"
+	"server/internal/config"
 	"server/internal/handlers"
 	"server/internal/services"
 	"server/pkgs/httpwrap"
 	"server/pkgs/middleware"
 )
 
-func RegisterVoteRoutes(app *fiber.App, db *gorm.DB) {
+func RegisterVoteRoutes(app *fiber.App, db *gorm.DB, resultHub *services.ResultHub, mesaLocks services.MesaLockChecker) {
 
-	voteService := services.NewVoteService(db)
+	voteService := services.NewVoteService(db, config.GetConfig().DoubleEntry, resultHub, mesaLocks)
 	voteHandler := handlers.NewVoteHandler(voteService)
-
-	app.Get("/votes", httpwrap.Wrap(voteHandler.GetAll))
-	app.Get("/votes/candidate/:candidateId", httpwrap.Wrap(voteHandler.GetByCandidate))
+	exportHandler := handlers.NewExportHandler(services.NewExportService(db))
 
 	voteGroup := app.Group("/votes", middleware.AuthRequired())
 	{
-		voteGroup.Post("/", httpwrap.Wrap(voteHandler.Create))
+		voteGroup.Get("/", middleware.RequirePermission(middleware.PermRead), handlers.Negotiate(httpwrap.Wrap(voteHandler.GetAll), exportHandler.Votes))
+		voteGroup.Get("/candidate/:candidateId", middleware.RequirePermission(middleware.PermRead), httpwrap.Wrap(voteHandler.GetByCandidate))
+		voteGroup.Post("/", middleware.RequirePermission(middleware.PermVotesCreate), httpwrap.Wrap(voteHandler.Create))
+		voteGroup.Patch("/:id", middleware.RequirePermission(middleware.PermVotesCorrect), httpwrap.Wrap(voteHandler.Update))
+		voteGroup.Post("/:id/annul", middleware.RequirePermission(middleware.PermVotesCorrect), httpwrap.Wrap(voteHandler.Annul))
+		voteGroup.Get("/:id/revisions", middleware.RequirePermission(middleware.PermRead), httpwrap.Wrap(voteHandler.GetRevisions))
 	}
 
 	println("✅ Vote routes registered")
@@ -0,0 +1,32 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v3"
+	"gorm.io/gorm"
+	"server/internal/handlers"
+	"server/internal/services"
+	"server/pkgs/httpwrap"
+	"server/pkgs/middleware"
+)
+
+func RegisterWebhookRoutes(app *fiber.App, db *gorm.DB, dispatcher *services.WebhookDispatcher) {
+	webhookService := services.NewWebhookService(db, dispatcher)
+	webhookHandler := handlers.NewWebhookHandler(webhookService)
+
+	webhookGroup := app.Group("/webhooks", middleware.AuthRequired(), middleware.RequirePermission(middleware.PermWebhooksManage))
+	{
+		// rutas fijas antes de /:id
+		webhookGroup.Get("/dead-letters", httpwrap.Wrap(webhookHandler.GetDeadLetters))
+		webhookGroup.Post("/deliveries/:id/retry", httpwrap.Wrap(webhookHandler.RetryDelivery))
+
+		webhookGroup.Get("/", httpwrap.Wrap(webhookHandler.GetAll))
+		webhookGroup.Post("/", httpwrap.Wrap(webhookHandler.Create))
+		webhookGroup.Get("/:id", httpwrap.Wrap(webhookHandler.GetOne))
+		webhookGroup.Patch("/:id", httpwrap.Wrap(webhookHandler.Update))
+		webhookGroup.Delete("/:id", httpwrap.Wrap(webhookHandler.Delete))
+		webhookGroup.Post("/:id/test", httpwrap.Wrap(webhookHandler.Test))
+		webhookGroup.Get("/:id/deliveries", httpwrap.Wrap(webhookHandler.GetDeliveries))
+	}
+
+	println("✅ Webhook routes registered")
+}
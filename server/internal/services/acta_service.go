@@ -0,0 +1,393 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"server/internal/dto"
+	"server/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ActaService interface {
+	Submit(mesaID string, req dto.SubmitActaRequest, userID, userRole, ip string) (*dto.ActaResponse, error)
+	GetByMesa(mesaID, userID, userRole string) ([]dto.ActaResponse, error)
+}
+
+type actaServiceImpl struct {
+	db          *gorm.DB
+	doubleEntry bool
+	notifier    ResultNotifier
+	locks       MesaLockChecker
+}
+
+// NewActaService: si locks no es nil, Submit exige el bloqueo de edición
+// vigente de la mesa, igual que VoteService.Create
+func NewActaService(db *gorm.DB, doubleEntry bool, notifier ResultNotifier, locks MesaLockChecker) ActaService {
+	return &actaServiceImpl{db: db, doubleEntry: doubleEntry, notifier: notifier, locks: locks}
+}
+
+// Submit: valida el acta completa de una mesa y la registra en una sola
+// transacción; si cualquier fila falla no se guarda nada. Con doble
+// digitación cada fila de votos pasa por captureVote: la primera acta deja
+// capturas pendientes y la segunda, de otro usuario, confirma los votos que
+// coinciden y abre discrepancias para los demás. Cédulas y votos en blanco no
+// tienen voto propio, así que la segunda acta debe coincidir en ellos.
+func (s *actaServiceImpl) Submit(mesaID string, req dto.SubmitActaRequest, userID, userRole, ip string) (*dto.ActaResponse, error) {
+	tv := models.TypeVote(req.TypeVote)
+	if tv != models.TVpersonnel && tv != models.TVpublic {
+		return nil, fmt.Errorf("typeVote inválido: debe ser DOCENTES o PUBLICO")
+	}
+
+	if req.BallotsCast < 0 {
+		return nil, ErrInvalidVoteCount
+	}
+
+	if s.locks != nil {
+		if err := s.locks.Check(mesaID, userID, req.LockToken); err != nil {
+			return nil, err
+		}
+	}
+
+	var actaID string
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var mesa models.Mesa
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&mesa, "id = ?", mesaID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrMesaNotFound
+			}
+			return err
+		}
+
+		if err := ensureElectionPhase(tx, mesa.ElectionID, electionVotingPhases); err != nil {
+			return err
+		}
+
+		if mesa.Status != models.MSopen {
+			return ErrMesaNotOpen
+		}
+
+		electors := mesa.ElectorsPublico
+		if tv == models.TVpersonnel {
+			electors = mesa.ElectorsDocentes
+		}
+		if req.BallotsCast > electors {
+			return fmt.Errorf("%w: %d cédulas para %d electores", ErrActaExceedsElectors, req.BallotsCast, electors)
+		}
+
+		var first *models.Acta
+		var previous models.Acta
+		err := tx.Preload("Positions").
+			Where("mesa_id = ? AND type_vote = ?", mesa.ID, tv).
+			First(&previous).Error
+		switch {
+		case err == nil:
+			if !s.doubleEntry || previous.SecondUserID != nil {
+				return ErrActaAlreadySubmitted
+			}
+			if previous.UserID == userID {
+				return ErrCaptureAlreadySubmitted
+			}
+			first = &previous
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			var votes int64
+			if err := tx.Model(&models.Vote{}).
+				Where("mesa_id = ? AND type_vote = ? AND is_annulled = false", mesa.ID, tv).
+				Count(&votes).Error; err != nil {
+				return err
+			}
+			if votes > 0 {
+				return ErrActaAlreadySubmitted
+			}
+		default:
+			return err
+		}
+
+		var positions []models.Position
+		if err := tx.Preload("Candidates").Where("election_id = ?", mesa.ElectionID).Find(&positions).Error; err != nil {
+			return err
+		}
+
+		sheet := make(map[string]dto.ActaPositionRequest, len(req.Positions))
+		for _, p := range req.Positions {
+			if _, dup := sheet[p.PositionID]; dup {
+				return fmt.Errorf("%w: posición %s repetida", ErrActaInvalid, p.PositionID)
+			}
+			sheet[p.PositionID] = p
+		}
+
+		acta := models.Acta{
+			MesaID:      mesa.ID,
+			TypeVote:    tv,
+			BallotsCast: req.BallotsCast,
+			UserID:      userID,
+		}
+		var votes []models.Vote
+
+		for _, position := range positions {
+			p, ok := sheet[position.ID]
+			if !ok {
+				return fmt.Errorf("%w: falta la posición %s", ErrActaIncomplete, position.Name)
+			}
+			delete(sheet, position.ID)
+
+			if p.BlankVotes < 0 || p.NullVotes < 0 {
+				return ErrInvalidVoteCount
+			}
+
+			counts := make(map[string]int, len(p.Votes))
+			for _, v := range p.Votes {
+				if v.TotalVotes < 0 {
+					return ErrInvalidVoteCount
+				}
+				if _, dup := counts[v.CandidateID]; dup {
+					return fmt.Errorf("%w: candidato %s repetido en %s", ErrActaInvalid, v.CandidateID, position.Name)
+				}
+				counts[v.CandidateID] = v.TotalVotes
+			}
+
+			var nullCandidate *models.Candidate
+			sum := p.BlankVotes + p.NullVotes
+			for i, c := range position.Candidates {
+				if c.TypeCandidate == models.TCnull {
+					if nullCandidate == nil {
+						nullCandidate = &position.Candidates[i]
+					}
+					continue
+				}
+
+				count, ok := counts[c.ID]
+				if !ok {
+					return fmt.Errorf("%w: falta el candidato %s en %s", ErrActaIncomplete, c.Name, position.Name)
+				}
+				delete(counts, c.ID)
+				sum += count
+
+				votes = append(votes, models.Vote{
+					MesaID:      mesa.ID,
+					CandidateID: c.ID,
+					TypeVote:    tv,
+					Vote:        count,
+				})
+			}
+
+			if len(counts) > 0 {
+				return fmt.Errorf("%w: hay candidatos que no pertenecen a %s", ErrActaInvalid, position.Name)
+			}
+
+			if p.NullVotes > 0 && nullCandidate == nil {
+				return fmt.Errorf("%w: %s no tiene candidato NULL para registrar votos nulos", ErrActaInvalid, position.Name)
+			}
+			// con doble digitación los nulos se capturan aunque sean 0, para
+			// que ambas actas comparen la misma fila
+			if nullCandidate != nil && (p.NullVotes > 0 || s.doubleEntry) {
+				votes = append(votes, models.Vote{
+					MesaID:      mesa.ID,
+					CandidateID: nullCandidate.ID,
+					TypeVote:    tv,
+					Vote:        p.NullVotes,
+				})
+			}
+
+			if sum > req.BallotsCast {
+				return fmt.Errorf("%w: %s suma %d votos para %d cédulas", ErrActaInvalid, position.Name, sum, req.BallotsCast)
+			}
+			if sum > electors {
+				return fmt.Errorf("%w: %s suma %d votos para %d electores", ErrActaExceedsElectors, position.Name, sum, electors)
+			}
+
+			acta.Positions = append(acta.Positions, models.ActaPosition{
+				PositionID: position.ID,
+				BlankVotes: p.BlankVotes,
+				NullVotes:  p.NullVotes,
+			})
+		}
+
+		if len(sheet) > 0 {
+			return fmt.Errorf("%w: el acta incluye posiciones desconocidas", ErrActaInvalid)
+		}
+
+		actor := AuditActor{userID, userRole, ip}
+		if s.doubleEntry {
+			id, err := s.captureActa(tx, first, &acta, votes, actor)
+			actaID = id
+			return err
+		}
+
+		if err := tx.Create(&acta).Error; err != nil {
+			return err
+		}
+		if err := auditCreated(tx, actor, "acta.submit", &models.Acta{}, AuditActa, acta.ID); err != nil {
+			return err
+		}
+
+		for i := range votes {
+			votes[i].ActaID = &acta.ID
+		}
+		if len(votes) > 0 {
+			if err := tx.Create(&votes).Error; err != nil {
+				return err
+			}
+		}
+		for _, v := range votes {
+			if err := auditCreated(tx, actor, "vote.create", &models.Vote{}, AuditVote, v.ID); err != nil {
+				return err
+			}
+		}
+
+		actaID = acta.ID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	positionIDs := make([]string, len(req.Positions))
+	for i, p := range req.Positions {
+		positionIDs[i] = p.PositionID
+	}
+	s.notifier.VotesChanged(positionIDs...)
+
+	return s.getOne(actaID, "")
+}
+
+// captureActa: con doble digitación, registra la primera acta o confirma la
+// existente (first) y pasa cada fila de votos por captureVote
+func (s *actaServiceImpl) captureActa(tx *gorm.DB, first, acta *models.Acta, votes []models.Vote, actor AuditActor) (string, error) {
+	if first == nil {
+		if err := tx.Create(acta).Error; err != nil {
+			return "", err
+		}
+		if err := auditCreated(tx, actor, "acta.submit", &models.Acta{}, AuditActa, acta.ID); err != nil {
+			return "", err
+		}
+		first = acta
+	} else {
+		if !actaHeaderMatches(first, acta) {
+			return "", ErrActaMismatch
+		}
+
+		before, err := auditSnapshot(tx, &models.Acta{}, first.ID)
+		if err != nil {
+			return "", err
+		}
+		if err := tx.Model(first).Update("second_user_id", actor.UserID).Error; err != nil {
+			return "", err
+		}
+		after, err := auditSnapshot(tx, &models.Acta{}, first.ID)
+		if err != nil {
+			return "", err
+		}
+		if err := recordAudit(tx, actor, "acta.confirm", AuditActa, first.ID, before, after); err != nil {
+			return "", err
+		}
+	}
+
+	for _, v := range votes {
+		if _, _, err := captureVote(tx, v.MesaID, v.CandidateID, v.TypeVote, v.Vote, &first.ID, actor); err != nil {
+			return "", err
+		}
+	}
+	return first.ID, nil
+}
+
+// actaHeaderMatches: mismas cédulas y mismos votos en blanco por posición
+func actaHeaderMatches(first, second *models.Acta) bool {
+	if first.BallotsCast != second.BallotsCast || len(first.Positions) != len(second.Positions) {
+		return false
+	}
+
+	blank := make(map[string]int, len(first.Positions))
+	for _, p := range first.Positions {
+		blank[p.PositionID] = p.BlankVotes
+	}
+	for _, p := range second.Positions {
+		if v, ok := blank[p.PositionID]; !ok || v != p.BlankVotes {
+			return false
+		}
+	}
+	return true
+}
+
+// GetByMesa: a un PERSONERO solo se le muestran los votos de candidatos de su
+// partido; las cédulas y los votos en blanco son del acta y se muestran igual
+func (s *actaServiceImpl) GetByMesa(mesaID, userID, userRole string) ([]dto.ActaResponse, error) {
+	party, _, err := personeroParty(s.db, userID, userRole)
+	if err != nil {
+		return nil, err
+	}
+
+	var mesa models.Mesa
+	if err := s.db.First(&mesa, "id = ?", mesaID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMesaNotFound
+		}
+		return nil, err
+	}
+
+	var actas []models.Acta
+	if err := s.db.Where("mesa_id = ?", mesaID).Order("type_vote").Find(&actas).Error; err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.ActaResponse, 0, len(actas))
+	for _, a := range actas {
+		item, err := s.getOne(a.ID, party)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, *item)
+	}
+	return res, nil
+}
+
+// getOne: con party no vacío solo incluye los votos de candidatos de ese partido
+func (s *actaServiceImpl) getOne(id, party string) (*dto.ActaResponse, error) {
+	var acta models.Acta
+	if err := s.db.Preload("Mesa").Preload("Positions.Position").First(&acta, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+
+	q := s.db.Preload("Mesa").Preload("Candidate.Position").Where("acta_id = ?", acta.ID)
+	if party != "" {
+		q = q.Where("candidate_id IN (?)", s.db.Model(&models.Candidate{}).Select("id").Where("party = ?", party))
+	}
+
+	var votes []models.Vote
+	if err := q.Find(&votes).Error; err != nil {
+		return nil, err
+	}
+
+	byPosition := make(map[string][]dto.VoteResponse)
+	for _, v := range votes {
+		if v.Candidate.PositionID == nil {
+			continue
+		}
+		byPosition[*v.Candidate.PositionID] = append(byPosition[*v.Candidate.PositionID], mapVoteToResponse(v))
+	}
+
+	res := dto.ActaResponse{
+		ID:          acta.ID,
+		MesaID:      acta.MesaID,
+		Mesa:        acta.Mesa.Number,
+		TypeVote:    string(acta.TypeVote),
+		BallotsCast: acta.BallotsCast,
+		CreatedAt:   acta.CreatedAt.Format(time.RFC3339),
+		Positions:   make([]dto.ActaPositionResponse, len(acta.Positions)),
+	}
+
+	for i, p := range acta.Positions {
+		res.Positions[i] = dto.ActaPositionResponse{
+			PositionID:   p.PositionID,
+			PositionName: p.Position.Name,
+			BlankVotes:   p.BlankVotes,
+			NullVotes:    p.NullVotes,
+			Votes:        byPosition[p.PositionID],
+		}
+	}
+
+	return &res, nil
+}
@@ -0,0 +1,279 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"server/internal/dto"
+	"server/internal/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Tipos de entidad auditados
+const (
+	AuditElection    = "election"
+	AuditPosition    = "position"
+	AuditCandidate   = "candidate"
+	AuditVote        = "vote"
+	AuditCapture     = "vote_capture"
+	AuditDiscrepancy = "discrepancy"
+	AuditActa        = "acta"
+	AuditImage       = "image"
+)
+
+const (
+	// auditChainLock: clave del advisory lock que serializa la escritura de la cadena
+	auditChainLock   = 0x61756474
+	auditDefaultPage = 100
+	auditMaxPage     = 1000
+	auditVerifyBatch = 500
+)
+
+var auditGenesisHash = strings.Repeat("0", 64)
+
+var ErrInvalidAuditFilter = errors.New("filtro de auditoría inválido")
+
+// AuditActor: quién origina la mutación y desde qué IP
+type AuditActor struct {
+	UserID string
+	Role   string
+	IP     string
+}
+
+type AuditService interface {
+	GetAll(filter dto.AuditFilter) ([]dto.AuditEventResponse, error)
+	Verify() (*dto.AuditVerifyResponse, error)
+}
+
+type auditServiceImpl struct {
+	db *gorm.DB
+}
+
+func NewAuditService(db *gorm.DB) AuditService {
+	return &auditServiceImpl{db: db}
+}
+
+// auditSnapshot: serializa la fila actual de la entidad para before/after
+func auditSnapshot(tx *gorm.DB, model interface{}, id string) (*string, error) {
+	row := map[string]interface{}{}
+	if err := tx.Model(model).Where("id = ?", id).Take(&row).Error; err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(row)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := string(data)
+	return &snapshot, nil
+}
+
+// auditCreated: registra la creación de una fila ya insertada en la transacción
+func auditCreated(tx *gorm.DB, actor AuditActor, action string, model interface{}, entityType, id string) error {
+	after, err := auditSnapshot(tx, model, id)
+	if err != nil {
+		return err
+	}
+	return recordAudit(tx, actor, action, entityType, id, nil, after)
+}
+
+// recordAudit: añade un evento al final de la cadena dentro de la transacción
+// de la mutación. El advisory lock se libera con el commit o rollback, así que
+// dos transacciones nunca enlazan con el mismo evento anterior.
+func recordAudit(tx *gorm.DB, actor AuditActor, action, entityType, entityID string, before, after *string) error {
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditChainLock).Error; err != nil {
+		return err
+	}
+
+	prevHash, seq := auditGenesisHash, int64(1)
+
+	var last models.AuditEvent
+	err := tx.Select("seq", "hash").Order("seq DESC").Limit(1).Find(&last).Error
+	if err != nil {
+		return err
+	}
+	if last.Seq > 0 {
+		prevHash, seq = last.Hash, last.Seq+1
+	}
+
+	event := models.AuditEvent{
+		Seq: seq,
+		// precisión de microsegundos, la misma que guarda PostgreSQL
+		CreatedAt:  time.Now().UTC().Truncate(time.Microsecond),
+		ActorID:    actor.UserID,
+		ActorRole:  actor.Role,
+		IP:         actor.IP,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     before,
+		After:      after,
+		PrevHash:   prevHash,
+	}
+	event.Hash = auditHash(&event)
+
+	return tx.Create(&event).Error
+}
+
+// auditHash: SHA-256 del hash anterior y de todos los campos del evento, cada
+// uno precedido por su longitud para que la concatenación no sea ambigua
+func auditHash(e *models.AuditEvent) string {
+	h := sha256.New()
+	for _, field := range []string{
+		e.PrevHash,
+		strconv.FormatInt(e.Seq, 10),
+		e.CreatedAt.UTC().Format(time.RFC3339Nano),
+		e.ActorID,
+		e.ActorRole,
+		e.IP,
+		e.Action,
+		e.EntityType,
+		e.EntityID,
+	} {
+		writeHashField(h, &field)
+	}
+	writeHashField(h, e.Before)
+	writeHashField(h, e.After)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// writeHashField: nil se codifica con longitud -1 para distinguirlo de ""
+func writeHashField(h hash.Hash, field *string) {
+	var size [8]byte
+	if field == nil {
+		binary.BigEndian.PutUint64(size[:], ^uint64(0))
+		h.Write(size[:])
+		return
+	}
+	binary.BigEndian.PutUint64(size[:], uint64(len(*field)))
+	h.Write(size[:])
+	h.Write([]byte(*field))
+}
+
+func mapAuditToResponse(e models.AuditEvent) dto.AuditEventResponse {
+	res := dto.AuditEventResponse{
+		ID:         e.ID,
+		Seq:        e.Seq,
+		CreatedAt:  e.CreatedAt.Format(time.RFC3339Nano),
+		ActorID:    e.ActorID,
+		ActorRole:  e.ActorRole,
+		IP:         e.IP,
+		Action:     e.Action,
+		EntityType: e.EntityType,
+		EntityID:   e.EntityID,
+		PrevHash:   e.PrevHash,
+		Hash:       e.Hash,
+	}
+	if e.Before != nil {
+		res.Before = json.RawMessage(*e.Before)
+	}
+	if e.After != nil {
+		res.After = json.RawMessage(*e.After)
+	}
+	return res
+}
+
+// GetAll: eventos más recientes primero, con filtros opcionales
+func (s *auditServiceImpl) GetAll(filter dto.AuditFilter) ([]dto.AuditEventResponse, error) {
+	q := s.db.Order("seq DESC")
+
+	if filter.EntityType != "" {
+		q = q.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		q = q.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.ActorID != "" {
+		q = q.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		q = q.Where("action = ?", filter.Action)
+	}
+	if filter.From != "" {
+		from, err := time.Parse(time.RFC3339, filter.From)
+		if err != nil {
+			return nil, fmt.Errorf("%w: from debe ser RFC3339", ErrInvalidAuditFilter)
+		}
+		q = q.Where("created_at >= ?", from)
+	}
+	if filter.To != "" {
+		to, err := time.Parse(time.RFC3339, filter.To)
+		if err != nil {
+			return nil, fmt.Errorf("%w: to debe ser RFC3339", ErrInvalidAuditFilter)
+		}
+		q = q.Where("created_at <= ?", to)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = auditDefaultPage
+	}
+	if limit > auditMaxPage {
+		limit = auditMaxPage
+	}
+
+	var events []models.AuditEvent
+	if err := q.Limit(limit).Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.AuditEventResponse, len(events))
+	for i, e := range events {
+		res[i] = mapAuditToResponse(e)
+	}
+	return res, nil
+}
+
+// Verify: recorre la cadena en orden y se detiene en el primer eslabón roto:
+// un salto en la secuencia, un prevHash que no coincide o un hash recalculado
+// distinto del guardado
+func (s *auditServiceImpl) Verify() (*dto.AuditVerifyResponse, error) {
+	res := &dto.AuditVerifyResponse{Valid: true, LastHash: auditGenesisHash}
+
+	for {
+		var batch []models.AuditEvent
+		if err := s.db.Where("seq > ?", res.LastSeq).
+			Order("seq").
+			Limit(auditVerifyBatch).
+			Find(&batch).Error; err != nil {
+			return nil, err
+		}
+
+		for _, e := range batch {
+			reason := ""
+			switch {
+			case e.Seq != res.LastSeq+1:
+				reason = fmt.Sprintf("falta el evento %d", res.LastSeq+1)
+			case e.PrevHash != res.LastHash:
+				reason = "prevHash no coincide con el hash del evento anterior"
+			case auditHash(&e) != e.Hash:
+				reason = "el contenido del evento no coincide con su hash"
+			}
+
+			if reason != "" {
+				seq := e.Seq
+				res.Valid = false
+				res.BrokenSeq = &seq
+				res.BrokenID = e.ID
+				res.Reason = reason
+				return res, nil
+			}
+
+			res.Checked++
+			res.LastSeq = e.Seq
+			res.LastHash = e.Hash
+		}
+
+		if len(batch) < auditVerifyBatch {
+			return res, nil
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+	"server/internal/models"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestAuditHash(t *testing.T) {
+	base := func() models.AuditEvent {
+		return models.AuditEvent{
+			Seq:        7,
+			CreatedAt:  time.Date(2026, 10, 18, 12, 0, 0, 123456000, time.UTC),
+			ActorID:    "user-1",
+			ActorRole:  "ADMIN",
+			IP:         "10.0.0.1",
+			Action:     "vote.update",
+			EntityType: AuditVote,
+			EntityID:   "vote-1",
+			Before:     strPtr(`{"quantity":10}`),
+			After:      strPtr(`{"quantity":12}`),
+			PrevHash:   auditGenesisHash,
+		}
+	}
+	original := base()
+	want := auditHash(&original)
+
+	tests := []struct {
+		name   string
+		mutate func(e *models.AuditEvent)
+		same   bool
+	}{
+		{"mismo evento", func(e *models.AuditEvent) {}, true},
+		{"misma hora en otra zona", func(e *models.AuditEvent) {
+			e.CreatedAt = e.CreatedAt.In(time.FixedZone("PET", -5*3600))
+		}, true},
+		{"prevHash", func(e *models.AuditEvent) { e.PrevHash = strings.Repeat("1", 64) }, false},
+		{"seq", func(e *models.AuditEvent) { e.Seq++ }, false},
+		{"createdAt", func(e *models.AuditEvent) { e.CreatedAt = e.CreatedAt.Add(time.Microsecond) }, false},
+		{"actor", func(e *models.AuditEvent) { e.ActorID = "user-2" }, false},
+		{"rol", func(e *models.AuditEvent) { e.ActorRole = "PERSONERO" }, false},
+		{"ip", func(e *models.AuditEvent) { e.IP = "10.0.0.2" }, false},
+		{"acción", func(e *models.AuditEvent) { e.Action = "vote.annul" }, false},
+		{"entidad", func(e *models.AuditEvent) { e.EntityID = "vote-2" }, false},
+		{"after", func(e *models.AuditEvent) { e.After = strPtr(`{"quantity":13}`) }, false},
+		{"before nil", func(e *models.AuditEvent) { e.Before = nil }, false},
+		{"before vacío", func(e *models.AuditEvent) { e.Before = strPtr("") }, false},
+		// sin el prefijo de longitud, mover un byte de un campo al siguiente
+		// daría la misma concatenación
+		{"frontera entre campos", func(e *models.AuditEvent) {
+			e.ActorID, e.ActorRole = "user-1A", "DMIN"
+		}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := base()
+			tt.mutate(&e)
+			if got := auditHash(&e); (got == want) != tt.same {
+				t.Errorf("hash igual = %v, want %v", got == want, tt.same)
+			}
+		})
+	}
+
+	nilBefore, emptyBefore := base(), base()
+	nilBefore.Before, emptyBefore.Before = nil, strPtr("")
+	if auditHash(&nilBefore) == auditHash(&emptyBefore) {
+		t.Error("nil y \"\" producen el mismo hash")
+	}
+}
+
+// seedAuditChain: n eventos encadenados, cada uno en su propia transacción
+func seedAuditChain(t *testing.T, db *gorm.DB, n int) {
+	t.Helper()
+
+	actor := AuditActor{UserID: "user-1", Role: "ADMIN", IP: "10.0.0.1"}
+	for i := 1; i <= n; i++ {
+		after := strPtr(fmt.Sprintf(`{"quantity":%d}`, i))
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			return recordAudit(tx, actor, "vote.update", AuditVote, fmt.Sprintf("vote-%d", i), nil, after)
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestAuditChainVerify(t *testing.T) {
+	const events = 5
+
+	tests := []struct {
+		name       string
+		tamper     func(t *testing.T, db *gorm.DB)
+		wantValid  bool
+		wantBroken int64
+		wantReason string
+	}{
+		{
+			name:      "cadena intacta",
+			tamper:    func(t *testing.T, db *gorm.DB) {},
+			wantValid: true,
+		},
+		{
+			name: "contenido modificado",
+			tamper: func(t *testing.T, db *gorm.DB) {
+				mustExec(t, db, `UPDATE audit_events SET after = '{"quantity":99}' WHERE seq = 3`)
+			},
+			wantBroken: 3,
+			wantReason: "contenido",
+		},
+		{
+			name: "actor modificado",
+			tamper: func(t *testing.T, db *gorm.DB) {
+				mustExec(t, db, `UPDATE audit_events SET actor_id = 'user-2' WHERE seq = 2`)
+			},
+			wantBroken: 2,
+			wantReason: "contenido",
+		},
+		{
+			name: "evento borrado",
+			tamper: func(t *testing.T, db *gorm.DB) {
+				mustExec(t, db, `DELETE FROM audit_events WHERE seq = 3`)
+			},
+			wantBroken: 4,
+			wantReason: "falta el evento 3",
+		},
+		{
+			name: "evento reescrito con su hash recalculado",
+			tamper: func(t *testing.T, db *gorm.DB) {
+				var e models.AuditEvent
+				if err := db.First(&e, "seq = ?", 2).Error; err != nil {
+					t.Fatal(err)
+				}
+				e.After = strPtr(`{"quantity":99}`)
+				e.Hash = auditHash(&e)
+				if err := db.Save(&e).Error; err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantBroken: 3,
+			wantReason: "prevHash",
+		},
+		{
+			name: "último evento borrado",
+			tamper: func(t *testing.T, db *gorm.DB) {
+				mustExec(t, db, `DELETE FROM audit_events WHERE seq = ?`, events)
+			},
+			// truncar el final no rompe ningún eslabón: lo detecta quien
+			// compare LastSeq/LastHash con un valor anclado fuera de la BD
+			wantValid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openTestDB(t, &models.AuditEvent{})
+			seedAuditChain(t, db, events)
+			tt.tamper(t, db)
+
+			res, err := NewAuditService(db).Verify()
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if res.Valid != tt.wantValid {
+				t.Fatalf("valid = %v, want %v (%s)", res.Valid, tt.wantValid, res.Reason)
+			}
+			if tt.wantValid {
+				return
+			}
+			if res.BrokenSeq == nil || *res.BrokenSeq != tt.wantBroken {
+				t.Errorf("brokenSeq = %v, want %d", res.BrokenSeq, tt.wantBroken)
+			}
+			if !strings.Contains(res.Reason, tt.wantReason) {
+				t.Errorf("reason %q no contiene %q", res.Reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestAuditChainIntact(t *testing.T) {
+	db := openTestDB(t, &models.AuditEvent{})
+	seedAuditChain(t, db, 3)
+
+	var chain []models.AuditEvent
+	if err := db.Order("seq").Find(&chain).Error; err != nil {
+		t.Fatal(err)
+	}
+	prev := auditGenesisHash
+	for _, e := range chain {
+		if e.PrevHash != prev {
+			t.Errorf("seq %d: prevHash %s, want %s", e.Seq, e.PrevHash, prev)
+		}
+		if got := auditHash(&e); got != e.Hash {
+			t.Errorf("seq %d: el hash leído de la BD no se reproduce", e.Seq)
+		}
+		prev = e.Hash
+	}
+
+	res, err := NewAuditService(db).Verify()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Valid || res.Checked != 3 || res.LastHash != prev {
+		t.Errorf("Verify = %+v", res)
+	}
+}
+
+func mustExec(t *testing.T, db *gorm.DB, sql string, args ...interface{}) {
+	t.Helper()
+	if err := db.Exec(sql, args...).Error; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -9,11 +9,11 @@ import (
 )
 
 type CandidateService interface {
-	Create(req dto.CreateCandidateRequest, userID, userRole string) (*dto.CandidateResponse, error)
-	GetAll() ([]dto.CandidateResponse, error)
+	Create(req dto.CreateCandidateRequest, userID, userRole, ip string) (*dto.CandidateResponse, error)
+	GetAll(electionID, userID, userRole string) ([]dto.CandidateResponse, error)
 	GetOne(id, userID, userRole string) (*dto.CandidateResponse, error)
-	Update(id string, req dto.UpdateCandidateRequest, userID, userRole string) (*dto.CandidateResponse, error)
-	Delete(id, userID, userRole string) error
+	Update(id string, req dto.UpdateCandidateRequest, userID, userRole, ip string) (*dto.CandidateResponse, error)
+	Delete(id, userID, userRole, ip string) error
 	GetPosition(candidateID, userID, userRole string) (*dto.PositionSimple, error)
 }
 
@@ -32,6 +32,7 @@ func mapModelToResponse(c models.Candidate) dto.CandidateResponse {
 		Description:   c.Description,
 		IsActive:      c.IsActive,
 		TypeCandidate: c.TypeCandidate,
+		Party:         c.Party,
 	}
 
 	if c.Image != nil {
@@ -42,6 +43,7 @@ func mapModelToResponse(c models.Candidate) dto.CandidateResponse {
 	if c.Position != nil {
 		response.Position = dto.PositionSimple{
 			ID:           c.Position.ID,
+			ElectionID:   c.Position.ElectionID,
 			Name:         c.Position.Name,
 			TypePosition: string(c.Position.TypePosition),
 		}
@@ -50,10 +52,22 @@ func mapModelToResponse(c models.Candidate) dto.CandidateResponse {
 	return response
 }
 
-func (s *candidateServiceImpl) GetAll() ([]dto.CandidateResponse, error) {
+func (s *candidateServiceImpl) GetAll(electionID, userID, userRole string) ([]dto.CandidateResponse, error) {
+	party, scoped, err := personeroParty(s.db, userID, userRole)
+	if err != nil {
+		return nil, err
+	}
+
 	var candidates []models.Candidate
 
-	if err := s.db.Preload("Position").Preload("Image").Find(&candidates).Error; err != nil {
+	q := s.db.Preload("Position").Preload("Image")
+	if electionID != "" {
+		q = q.Where("position_id IN (?)", s.db.Model(&models.Position{}).Select("id").Where("election_id = ?", electionID))
+	}
+	if scoped {
+		q = q.Where("party = ?", party)
+	}
+	if err := q.Find(&candidates).Error; err != nil {
 		return nil, err
 	}
 
@@ -75,28 +89,48 @@ func (s *candidateServiceImpl) GetOne(id, userID, userRole string) (*dto.Candida
 		return nil, err
 	}
 
+	if err := ensureCandidateParty(s.db, &candidate, userID, userRole); err != nil {
+		return nil, err
+	}
+
 	res := mapModelToResponse(candidate)
 	return &res, nil
 }
 
-func (s *candidateServiceImpl) Create(req dto.CreateCandidateRequest, userID, userRole string) (*dto.CandidateResponse, error) {
-	if userRole != "ADMIN" {
-		return nil, ErrUnauthorized
+func (s *candidateServiceImpl) Create(req dto.CreateCandidateRequest, userID, userRole, ip string) (*dto.CandidateResponse, error) {
+	var candidate *models.Candidate
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		candidate, err = createCandidate(tx, req, AuditActor{userID, userRole, ip})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetOne(candidate.ID, userID, userRole)
+}
+
+// createCandidate: reglas de alta de un candidato dentro de tx; las comparten
+// Create y la importación masiva
+func createCandidate(tx *gorm.DB, req dto.CreateCandidateRequest, actor AuditActor) (*models.Candidate, error) {
+	if req.Name == "" {
+		return nil, errors.New("nombre del candidato obligatorio")
+	}
+
+	if req.TypeCandidate != models.TCcandidate && req.TypeCandidate != models.TCnull {
+		return nil, errors.New("typeCandidate inválido, debe ser CANDIDATO o NULL")
 	}
 
 	if req.PositionID != "" {
-		var position models.Position
-		if err := s.db.First(&position, "id = ?", req.PositionID).Error; err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return nil, ErrPositionNotFound
-			}
+		if err := ensurePositionPhase(tx, req.PositionID, electionSetupPhases); err != nil {
 			return nil, err
 		}
 	}
 
 	if req.ImageID != "" {
 		var image models.Image
-		if err := s.db.First(&image, "id = ?", req.ImageID).Error; err != nil {
+		if err := tx.First(&image, "id = ?", req.ImageID).Error; err != nil {
 			if errors.Is(err, gorm.ErrRecordNotFound) {
 				return nil, errors.New("imagen no encontrada")
 			}
@@ -109,6 +143,7 @@ func (s *candidateServiceImpl) Create(req dto.CreateCandidateRequest, userID, us
 		Description:   req.Description,
 		IsActive:      true,
 		TypeCandidate: req.TypeCandidate,
+		Party:         req.Party,
 	}
 
 	if req.IsActive != nil {
@@ -121,18 +156,16 @@ func (s *candidateServiceImpl) Create(req dto.CreateCandidateRequest, userID, us
 		candidate.ImageID = &req.ImageID
 	}
 
-	if err := s.db.Create(&candidate).Error; err != nil {
+	if err := tx.Create(&candidate).Error; err != nil {
 		return nil, err
 	}
-
-	return s.GetOne(candidate.ID, userID, userRole)
-}
-
-func (s *candidateServiceImpl) Update(id string, req dto.UpdateCandidateRequest, userID, userRole string) (*dto.CandidateResponse, error) {
-	if userRole != "ADMIN" {
-		return nil, ErrUnauthorized
+	if err := auditCreated(tx, actor, "candidate.create", &models.Candidate{}, AuditCandidate, candidate.ID); err != nil {
+		return nil, err
 	}
+	return &candidate, nil
+}
 
+func (s *candidateServiceImpl) Update(id string, req dto.UpdateCandidateRequest, userID, userRole, ip string) (*dto.CandidateResponse, error) {
 	var candidate models.Candidate
 	if err := s.db.Where("id = ?", id).First(&candidate).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -141,6 +174,8 @@ func (s *candidateServiceImpl) Update(id string, req dto.UpdateCandidateRequest,
 		return nil, err
 	}
 
+	originalPositionID := candidate.PositionID
+
 	if req.Name != nil {
 		candidate.Name = *req.Name
 	}
@@ -150,18 +185,18 @@ func (s *candidateServiceImpl) Update(id string, req dto.UpdateCandidateRequest,
 	if req.IsActive != nil {
 		candidate.IsActive = *req.IsActive
 	}
+	if req.Party != nil {
+		if *req.Party == "" {
+			candidate.Party = nil
+		} else {
+			candidate.Party = req.Party
+		}
+	}
 
 	if req.PositionID != nil {
 		if *req.PositionID == "" {
 			candidate.PositionID = nil
 		} else {
-			var position models.Position
-			if err := s.db.First(&position, "id = ?", *req.PositionID).Error; err != nil {
-				if errors.Is(err, gorm.ErrRecordNotFound) {
-					return nil, ErrPositionNotFound
-				}
-				return nil, err
-			}
 			candidate.PositionID = req.PositionID
 		}
 	}
@@ -181,27 +216,73 @@ func (s *candidateServiceImpl) Update(id string, req dto.UpdateCandidateRequest,
 		}
 	}
 
-	if err := s.db.Save(&candidate).Error; err != nil {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		// La fase se verifica sobre la posición de origen y la de destino
+		for _, positionID := range []*string{originalPositionID, candidate.PositionID} {
+			if positionID == nil {
+				continue
+			}
+			if err := ensurePositionPhase(tx, *positionID, electionSetupPhases); err != nil {
+				return err
+			}
+		}
+
+		before, err := auditSnapshot(tx, &models.Candidate{}, candidate.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Save(&candidate).Error; err != nil {
+			return err
+		}
+
+		after, err := auditSnapshot(tx, &models.Candidate{}, candidate.ID)
+		if err != nil {
+			return err
+		}
+		return recordAudit(tx, AuditActor{userID, userRole, ip}, "candidate.update", AuditCandidate, candidate.ID, before, after)
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return s.GetOne(candidate.ID, userID, userRole)
 }
 
-func (s *candidateServiceImpl) Delete(id, userID, userRole string) error {
-	if userRole != "ADMIN" {
-		return ErrUnauthorized
+func (s *candidateServiceImpl) Delete(id, userID, userRole, ip string) error {
+	var candidate models.Candidate
+	if err := s.db.Select("id", "position_id").First(&candidate, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCandidateNotFound
+		}
+		return err
 	}
 
-	result := s.db.Delete(&models.Candidate{}, "id = ?", id)
-	if result.Error != nil {
-		return result.Error
-	}
-	if result.RowsAffected == 0 {
-		return ErrCandidateNotFound
-	}
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if candidate.PositionID != nil {
+			if err := ensurePositionPhase(tx, *candidate.PositionID, electionSetupPhases); err != nil {
+				return err
+			}
+		}
+
+		before, err := auditSnapshot(tx, &models.Candidate{}, id)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrCandidateNotFound
+			}
+			return err
+		}
+
+		result := tx.Delete(&models.Candidate{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrCandidateNotFound
+		}
 
-	return nil
+		return recordAudit(tx, AuditActor{userID, userRole, ip}, "candidate.delete", AuditCandidate, id, before, nil)
+	})
 }
 
 func (s *candidateServiceImpl) GetPosition(candidateID, userID, userRole string) (*dto.PositionSimple, error) {
@@ -214,12 +295,17 @@ func (s *candidateServiceImpl) GetPosition(candidateID, userID, userRole string)
 		return nil, err
 	}
 
+	if err := ensureCandidateParty(s.db, &candidate, userID, userRole); err != nil {
+		return nil, err
+	}
+
 	if candidate.Position == nil {
 		return nil, nil
 	}
 
 	return &dto.PositionSimple{
-		ID:   candidate.Position.ID,
-		Name: candidate.Position.Name,
+		ID:         candidate.Position.ID,
+		ElectionID: candidate.Position.ElectionID,
+		Name:       candidate.Position.Name,
 	}, nil
 }
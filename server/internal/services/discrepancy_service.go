@@ -0,0 +1,205 @@
+package services
+
+import (
+	"errors"
+	"server/internal/dto"
+	"server/internal/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DiscrepancyService interface {
+	GetAll(status, electionID, userID, userRole string) ([]dto.DiscrepancyResponse, error)
+	Resolve(id string, req dto.ResolveDiscrepancyRequest, userID, userRole, ip string) (*dto.DiscrepancyResponse, error)
+}
+
+type discrepancyServiceImpl struct {
+	db       *gorm.DB
+	notifier ResultNotifier
+}
+
+func NewDiscrepancyService(db *gorm.DB, notifier ResultNotifier) DiscrepancyService {
+	return &discrepancyServiceImpl{db: db, notifier: notifier}
+}
+
+func mapDiscrepancyToResponse(d models.Discrepancy) dto.DiscrepancyResponse {
+	res := dto.DiscrepancyResponse{
+		ID:            d.ID,
+		MesaID:        d.MesaID,
+		Mesa:          d.Mesa.Number,
+		CandidateID:   d.CandidateID,
+		CandidateName: d.Candidate.Name,
+		TypeVote:      string(d.TypeVote),
+		Status:        string(d.Status),
+		ResolvedBy:    d.ResolvedBy,
+		ResolvedVote:  d.ResolvedVote,
+		Reason:        d.Reason,
+		VoteID:        d.VoteID,
+		CreatedAt:     d.CreatedAt.Format(time.RFC3339),
+		Captures:      make([]dto.CaptureResponse, len(d.Captures)),
+	}
+
+	for i, c := range d.Captures {
+		res.Captures[i] = dto.CaptureResponse{
+			ID:         c.ID,
+			UserID:     c.UserID,
+			TotalVotes: c.Vote,
+			Status:     string(c.Status),
+			CreatedAt:  c.CreatedAt.Format(time.RFC3339),
+		}
+	}
+
+	return res
+}
+
+// GetAll: cola de discrepancias; por defecto solo las abiertas, "ALL" para todas
+func (s *discrepancyServiceImpl) GetAll(status, electionID, userID, userRole string) ([]dto.DiscrepancyResponse, error) {
+	party, scoped, err := personeroParty(s.db, userID, userRole)
+	if err != nil {
+		return nil, err
+	}
+
+	q := s.db.Preload("Mesa").Preload("Candidate").
+		Preload("Captures", func(db *gorm.DB) *gorm.DB { return db.Order("created_at") }).
+		Order("created_at")
+
+	if electionID != "" {
+		q = q.Where("mesa_id IN (?)", s.db.Model(&models.Mesa{}).Select("id").Where("election_id = ?", electionID))
+	}
+
+	if scoped {
+		q = q.Where("candidate_id IN (?)", s.db.Model(&models.Candidate{}).Select("id").Where("party = ?", party))
+	}
+
+	switch strings.ToUpper(status) {
+	case "ALL":
+	case "":
+		q = q.Where("status = ?", models.DSopen)
+	default:
+		q = q.Where("status = ?", strings.ToUpper(status))
+	}
+
+	var discrepancies []models.Discrepancy
+	if err := q.Find(&discrepancies).Error; err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.DiscrepancyResponse, len(discrepancies))
+	for i, d := range discrepancies {
+		res[i] = mapDiscrepancyToResponse(d)
+	}
+	return res, nil
+}
+
+// Resolve: el supervisor fija el valor definitivo; se crea el voto confirmado
+// y las capturas quedan CONFIRMED o REJECTED según coincidan con él. Solo
+// mientras la mesa siga abierta, como cualquier otro voto.
+func (s *discrepancyServiceImpl) Resolve(id string, req dto.ResolveDiscrepancyRequest, userID, userRole, ip string) (*dto.DiscrepancyResponse, error) {
+	if req.TotalVotes == nil || *req.TotalVotes < 0 {
+		return nil, ErrInvalidVoteCount
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return nil, ErrReasonRequired
+	}
+
+	actor := AuditActor{userID, userRole, ip}
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var d models.Discrepancy
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&d, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrDiscrepancyNotFound
+			}
+			return err
+		}
+
+		if d.Status != models.DSopen {
+			return ErrDiscrepancyResolved
+		}
+
+		// mismas comprobaciones que un voto nuevo: mesa abierta, fase de
+		// votación y sin voto vigente para el candidato
+		if err := lockVoteTarget(tx, d.MesaID, d.CandidateID, d.TypeVote); err != nil {
+			return err
+		}
+
+		vote := models.Vote{
+			MesaID:      d.MesaID,
+			CandidateID: d.CandidateID,
+			TypeVote:    d.TypeVote,
+			Vote:        *req.TotalVotes,
+		}
+
+		// si la discrepancia salió de dos actas, el voto pertenece al acta
+		var acta models.Acta
+		if err := tx.Select("id").Where("mesa_id = ? AND type_vote = ?", d.MesaID, d.TypeVote).
+			Limit(1).Find(&acta).Error; err != nil {
+			return err
+		}
+		if acta.ID != "" {
+			vote.ActaID = &acta.ID
+		}
+		if err := tx.Create(&vote).Error; err != nil {
+			return err
+		}
+		if err := auditCreated(tx, actor, "vote.create", &models.Vote{}, AuditVote, vote.ID); err != nil {
+			return err
+		}
+
+		var captures []models.VoteCapture
+		if err := tx.Select("id", "vote").Where("discrepancy_id = ?", d.ID).
+			Order("created_at").Find(&captures).Error; err != nil {
+			return err
+		}
+		for _, c := range captures {
+			action, updates := "vote.capture_reject", map[string]interface{}{"status": models.CSrejected}
+			if c.Vote == vote.Vote {
+				action, updates = "vote.capture_confirm", map[string]interface{}{"status": models.CSconfirmed, "vote_id": vote.ID}
+			}
+			if err := auditUpdatedCapture(tx, actor, action, c.ID, updates); err != nil {
+				return err
+			}
+		}
+
+		before, err := auditSnapshot(tx, &models.Discrepancy{}, d.ID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&d).Updates(map[string]interface{}{
+			"status":        models.DSresolved,
+			"resolved_by":   userID,
+			"resolved_vote": vote.Vote,
+			"reason":        reason,
+			"vote_id":       vote.ID,
+		}).Error; err != nil {
+			return err
+		}
+		after, err := auditSnapshot(tx, &models.Discrepancy{}, d.ID)
+		if err != nil {
+			return err
+		}
+		return recordAudit(tx, actor, "discrepancy.resolve", AuditDiscrepancy, d.ID, before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var d models.Discrepancy
+	if err := s.db.Preload("Mesa").Preload("Candidate").
+		Preload("Captures", func(db *gorm.DB) *gorm.DB { return db.Order("created_at") }).
+		First(&d, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+
+	if d.Candidate.PositionID != nil {
+		s.notifier.VotesChanged(*d.Candidate.PositionID)
+	}
+
+	res := mapDiscrepancyToResponse(d)
+	return &res, nil
+}
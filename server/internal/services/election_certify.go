@@ -0,0 +1,140 @@
+package services
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"server/internal/dto"
+	"server/internal/models"
+	"server/pkgs/security"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const certifiedResultsType = "election-results-certificate"
+
+func mapCertificateToResponse(c models.ResultCertificate) dto.CertificateResponse {
+	return dto.CertificateResponse{
+		ElectionID:   c.ElectionID,
+		Document:     json.RawMessage(c.Document),
+		DocumentHash: c.DocumentHash,
+		Signature:    c.Signature,
+		KeyID:        c.KeyID,
+		PublicKey:    c.PublicKey,
+		CertifiedBy:  c.CertifiedBy,
+		CertifiedAt:  c.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// Certify: pasa una elección CLOSED a CERTIFIED congelando sus resultados en
+// un JSON canónico firmado con Ed25519. El documento, la firma y la clave
+// pública quedan guardados para que cualquiera pueda verificarlos.
+func (s *electionServiceImpl) Certify(id, userID, userRole, ip string) (*dto.CertificateResponse, error) {
+	if s.signer == nil {
+		return nil, ErrCertificationUnavailable
+	}
+
+	var cert models.ResultCertificate
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var election models.Election
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&election, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrElectionNotFound
+			}
+			return err
+		}
+
+		if election.Status != models.ESclosed {
+			return fmt.Errorf("%w: %s → %s", ErrElectionTransition, election.Status, models.EScertified)
+		}
+		if err := checkTransitionPreconditions(tx, &election, models.EScertified); err != nil {
+			return err
+		}
+
+		results, err := NewResultService(tx).GetAll(election.ID)
+		if err != nil {
+			return err
+		}
+
+		certifiedAt := time.Now().UTC()
+		raw, err := json.Marshal(dto.CertifiedResultsDocument{
+			Type:         certifiedResultsType,
+			Version:      1,
+			ElectionID:   election.ID,
+			ElectionName: election.Name,
+			CertifiedAt:  certifiedAt.Format(time.RFC3339),
+			CertifiedBy:  userID,
+			KeyID:        s.signer.KeyID,
+			Results:      *results,
+		})
+		if err != nil {
+			return err
+		}
+
+		document, signature, err := s.signer.Sign(raw)
+		if err != nil {
+			return err
+		}
+
+		cert = models.ResultCertificate{
+			ElectionID:   election.ID,
+			Document:     string(document),
+			DocumentHash: security.DocumentHash(document),
+			Signature:    signature,
+			KeyID:        s.signer.KeyID,
+			PublicKey:    base64.StdEncoding.EncodeToString(s.signer.PublicKey()),
+			CertifiedBy:  userID,
+		}
+		cert.CreatedAt = certifiedAt
+		if err := tx.Create(&cert).Error; err != nil {
+			return err
+		}
+
+		before, err := auditSnapshot(tx, &models.Election{}, election.ID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&election).Update("status", models.EScertified).Error; err != nil {
+			return err
+		}
+		after, err := auditSnapshot(tx, &models.Election{}, election.ID)
+		if err != nil {
+			return err
+		}
+		if err := recordAudit(tx, AuditActor{userID, userRole, ip}, "election.certify", AuditElection, election.ID, before, after); err != nil {
+			return err
+		}
+
+		return enqueueWebhookEvent(tx, WebhookElectionCertified, map[string]interface{}{
+			"electionId":   election.ID,
+			"electionName": election.Name,
+			"certifiedAt":  certifiedAt.Format(time.RFC3339),
+			"documentHash": cert.DocumentHash,
+			"keyId":        cert.KeyID,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := mapCertificateToResponse(cert)
+	return &res, nil
+}
+
+// GetCertificate: documento firmado de una elección certificada
+func (s *electionServiceImpl) GetCertificate(id string) (*dto.CertificateResponse, error) {
+	var cert models.ResultCertificate
+	if err := s.db.First(&cert, "election_id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCertificateNotFound
+		}
+		return nil, err
+	}
+
+	res := mapCertificateToResponse(cert)
+	return &res, nil
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"server/internal/dto"
+	"server/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// electionTransitions: ciclo de vida de una elección
+// DRAFT → CONFIGURED → OPEN → CLOSED → CERTIFIED (CONFIGURED puede volver a DRAFT).
+// El paso a CERTIFIED solo lo hace Certify, que además firma los resultados.
+var electionTransitions = map[models.ElectionStatus][]models.ElectionStatus{
+	models.ESdraft:      {models.ESconfigured},
+	models.ESconfigured: {models.ESdraft, models.ESopen},
+	models.ESopen:       {models.ESclosed},
+	models.ESclosed:     {models.EScertified},
+}
+
+// Fases en las que se permite cada tipo de cambio
+var (
+	electionSetupPhases      = []models.ElectionStatus{models.ESdraft, models.ESconfigured}
+	electionVotingPhases     = []models.ElectionStatus{models.ESopen}
+	electionCorrectionPhases = []models.ElectionStatus{models.ESopen, models.ESclosed}
+	electionMutablePhases    = []models.ElectionStatus{models.ESdraft, models.ESconfigured, models.ESopen, models.ESclosed}
+)
+
+// Transition: cambia el estado de la elección validando las precondiciones del destino
+func (s *electionServiceImpl) Transition(id string, req dto.TransitionElectionRequest, userID, userRole string) (*dto.ElectionResponse, error) {
+	next := models.ElectionStatus(req.Status)
+	switch next {
+	case models.ESdraft, models.ESconfigured, models.ESopen, models.ESclosed:
+	case models.EScertified:
+		return nil, ErrCertificationRequired
+	default:
+		return nil, ErrInvalidElectionStatus
+	}
+
+	var election models.Election
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&election, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrElectionNotFound
+			}
+			return err
+		}
+
+		if !containsStatus(electionTransitions[election.Status], next) {
+			return fmt.Errorf("%w: %s → %s", ErrElectionTransition, election.Status, next)
+		}
+
+		if err := checkTransitionPreconditions(tx, &election, next); err != nil {
+			return err
+		}
+
+		election.Status = next
+		return tx.Model(&election).Update("status", next).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := mapElectionToResponse(election)
+	return &res, nil
+}
+
+func checkTransitionPreconditions(tx *gorm.DB, election *models.Election, next models.ElectionStatus) error {
+	switch next {
+	case models.ESconfigured, models.ESopen:
+		var positions []models.Position
+		if err := tx.Preload("Candidates").Where("election_id = ?", election.ID).Find(&positions).Error; err != nil {
+			return err
+		}
+		if len(positions) == 0 {
+			return fmt.Errorf("%w: no hay posiciones registradas", ErrElectionPrecondition)
+		}
+		for _, p := range positions {
+			candidates := 0
+			for _, c := range p.Candidates {
+				if c.TypeCandidate == models.TCcandidate && c.IsActive {
+					candidates++
+				}
+			}
+			if candidates == 0 {
+				return fmt.Errorf("%w: la posición %s no tiene candidatos", ErrElectionPrecondition, p.Name)
+			}
+		}
+
+		var mesas int64
+		if err := tx.Model(&models.Mesa{}).Where("election_id = ?", election.ID).Count(&mesas).Error; err != nil {
+			return err
+		}
+		if mesas == 0 {
+			return fmt.Errorf("%w: no hay mesas registradas", ErrElectionPrecondition)
+		}
+
+	case models.ESclosed, models.EScertified:
+		var open int64
+		if err := tx.Model(&models.Discrepancy{}).
+			Where("status = ? AND mesa_id IN (?)", models.DSopen,
+				tx.Model(&models.Mesa{}).Select("id").Where("election_id = ?", election.ID)).
+			Count(&open).Error; err != nil {
+			return err
+		}
+		if open > 0 {
+			return fmt.Errorf("%w: hay %d discrepancias sin resolver", ErrElectionPrecondition, open)
+		}
+
+		if next == models.EScertified {
+			var pending int64
+			if err := tx.Model(&models.Mesa{}).
+				Where("election_id = ? AND status NOT IN ?", election.ID, []models.MesaStatus{models.MScounted, models.MSannulled}).
+				Count(&pending).Error; err != nil {
+				return err
+			}
+			if pending > 0 {
+				return fmt.Errorf("%w: hay %d mesas sin escrutar", ErrElectionPrecondition, pending)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ensureElectionPhase: verifica que la elección esté en una de las fases permitidas.
+// Toma FOR SHARE sobre la fila: llamada dentro de la transacción de escritura,
+// una Transition (FOR UPDATE) concurrente espera a que esa escritura termine.
+func ensureElectionPhase(db *gorm.DB, electionID string, allowed []models.ElectionStatus) error {
+	election, err := findElection(db.Clauses(clause.Locking{Strength: "SHARE"}), electionID)
+	if err != nil {
+		return err
+	}
+
+	if !containsStatus(allowed, election.Status) {
+		return fmt.Errorf("%w (%s)", ErrElectionLocked, election.Status)
+	}
+	return nil
+}
+
+// ensurePositionPhase: igual que ensureElectionPhase a partir de una posición
+func ensurePositionPhase(db *gorm.DB, positionID string, allowed []models.ElectionStatus) error {
+	var position models.Position
+	if err := db.Select("id", "election_id").First(&position, "id = ?", positionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPositionNotFound
+		}
+		return err
+	}
+	return ensureElectionPhase(db, position.ElectionID, allowed)
+}
+
+func containsStatus(list []models.ElectionStatus, status models.ElectionStatus) bool {
+	for _, s := range list {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
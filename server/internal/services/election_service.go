@@ -0,0 +1,244 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"server/internal/dto"
+	"server/internal/models"
+	"server/pkgs/security"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ElectionService interface {
+	GetAll() ([]dto.ElectionResponse, error)
+	GetOne(id string) (*dto.ElectionResponse, error)
+	Create(req dto.CreateElectionRequest, userID, userRole string) (*dto.ElectionResponse, error)
+	Update(id string, req dto.UpdateElectionRequest, userID, userRole, ip string) (*dto.ElectionResponse, error)
+	Delete(id, userID, userRole string) error
+	Transition(id string, req dto.TransitionElectionRequest, userID, userRole string) (*dto.ElectionResponse, error)
+	Certify(id, userID, userRole, ip string) (*dto.CertificateResponse, error)
+	GetCertificate(id string) (*dto.CertificateResponse, error)
+}
+
+type electionServiceImpl struct {
+	db     *gorm.DB
+	signer *security.DocumentSigner
+}
+
+// NewElectionService: signer puede ser nil; en ese caso Certify no está disponible
+func NewElectionService(db *gorm.DB, signer *security.DocumentSigner) ElectionService {
+	return &electionServiceImpl{db: db, signer: signer}
+}
+
+func mapElectionToResponse(e models.Election) dto.ElectionResponse {
+	res := dto.ElectionResponse{
+		ID:          e.ID,
+		Name:        e.Name,
+		Description: e.Description,
+		Status:      string(e.Status),
+		CreatedAt:   e.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   e.UpdatedAt.Format(time.RFC3339),
+	}
+
+	if e.StartDate != nil {
+		start := e.StartDate.Format(time.RFC3339)
+		res.StartDate = &start
+	}
+	if e.EndDate != nil {
+		end := e.EndDate.Format(time.RFC3339)
+		res.EndDate = &end
+	}
+
+	return res
+}
+
+func (s *electionServiceImpl) GetAll() ([]dto.ElectionResponse, error) {
+	var elections []models.Election
+	if err := s.db.Order("created_at DESC").Find(&elections).Error; err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.ElectionResponse, len(elections))
+	for i, e := range elections {
+		res[i] = mapElectionToResponse(e)
+	}
+	return res, nil
+}
+
+func (s *electionServiceImpl) GetOne(id string) (*dto.ElectionResponse, error) {
+	election, err := findElection(s.db, id)
+	if err != nil {
+		return nil, err
+	}
+
+	res := mapElectionToResponse(*election)
+	return &res, nil
+}
+
+func (s *electionServiceImpl) Create(req dto.CreateElectionRequest, userID, userRole string) (*dto.ElectionResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("nombre de la elección obligatorio")
+	}
+
+	if err := validateElectionDates(req.StartDate, req.EndDate); err != nil {
+		return nil, err
+	}
+
+	if err := ensureElectionNameAvailable(s.db, name, ""); err != nil {
+		return nil, err
+	}
+
+	election := models.Election{
+		Name:        name,
+		Description: req.Description,
+		StartDate:   req.StartDate,
+		EndDate:     req.EndDate,
+		Status:      models.ESdraft,
+	}
+
+	if err := s.db.Create(&election).Error; err != nil {
+		return nil, err
+	}
+
+	res := mapElectionToResponse(election)
+	return &res, nil
+}
+
+// Update: bloquea la fila para que una Transition concurrente no cambie la fase
+// entre la comprobación y la escritura; solo toca los campos editables
+func (s *electionServiceImpl) Update(id string, req dto.UpdateElectionRequest, userID, userRole, ip string) (*dto.ElectionResponse, error) {
+	var election models.Election
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&election, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrElectionNotFound
+			}
+			return err
+		}
+
+		if !containsStatus(electionMutablePhases, election.Status) {
+			return fmt.Errorf("%w (%s)", ErrElectionLocked, election.Status)
+		}
+
+		updates := map[string]interface{}{}
+		if req.Name != nil {
+			name := strings.TrimSpace(*req.Name)
+			if name == "" {
+				return fmt.Errorf("el nombre no puede estar vacío")
+			}
+			if err := ensureElectionNameAvailable(tx, name, election.ID); err != nil {
+				return err
+			}
+			updates["name"] = name
+		}
+		if req.Description != nil {
+			updates["description"] = req.Description
+		}
+		start, end := election.StartDate, election.EndDate
+		if req.StartDate != nil {
+			start = req.StartDate
+			updates["start_date"] = req.StartDate
+		}
+		if req.EndDate != nil {
+			end = req.EndDate
+			updates["end_date"] = req.EndDate
+		}
+		if err := validateElectionDates(start, end); err != nil {
+			return err
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+
+		before, err := auditSnapshot(tx, &models.Election{}, election.ID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Election{}).Where("id = ?", election.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&election, "id = ?", election.ID).Error; err != nil {
+			return err
+		}
+		after, err := auditSnapshot(tx, &models.Election{}, election.ID)
+		if err != nil {
+			return err
+		}
+		return recordAudit(tx, AuditActor{userID, userRole, ip}, "election.update", AuditElection, election.ID, before, after)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := mapElectionToResponse(election)
+	return &res, nil
+}
+
+func (s *electionServiceImpl) Delete(id, userID, userRole string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := ensureElectionPhase(tx, id, []models.ElectionStatus{models.ESdraft}); err != nil {
+			return err
+		}
+
+		var positions int64
+		if err := tx.Model(&models.Position{}).Where("election_id = ?", id).Count(&positions).Error; err != nil {
+			return err
+		}
+		var mesas int64
+		if err := tx.Model(&models.Mesa{}).Where("election_id = ?", id).Count(&mesas).Error; err != nil {
+			return err
+		}
+		if positions > 0 || mesas > 0 {
+			return ErrElectionInUse
+		}
+
+		result := tx.Delete(&models.Election{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrElectionNotFound
+		}
+		return nil
+	})
+}
+
+func ensureElectionNameAvailable(db *gorm.DB, name, exceptID string) error {
+	q := db.Model(&models.Election{}).Where("name = ?", name)
+	if exceptID != "" {
+		q = q.Where("id <> ?", exceptID)
+	}
+
+	var count int64
+	if err := q.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrElectionNameTaken
+	}
+	return nil
+}
+
+func findElection(db *gorm.DB, id string) (*models.Election, error) {
+	var election models.Election
+	if err := db.First(&election, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrElectionNotFound
+		}
+		return nil, err
+	}
+	return &election, nil
+}
+
+func validateElectionDates(start, end *time.Time) error {
+	if start != nil && end != nil && end.Before(*start) {
+		return fmt.Errorf("la fecha de fin no puede ser anterior a la de inicio")
+	}
+	return nil
+}
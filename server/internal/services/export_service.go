@@ -0,0 +1,671 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"server/internal/dto"
+	"server/internal/models"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+type ExportFormat string
+
+const (
+	ExportCSV  ExportFormat = "csv"
+	ExportXLSX ExportFormat = "xlsx"
+)
+
+// ParseExportFormat: valor de ?format=, sin distinguir mayúsculas
+func ParseExportFormat(s string) (ExportFormat, error) {
+	switch ExportFormat(strings.ToLower(strings.TrimSpace(s))) {
+	case ExportCSV:
+		return ExportCSV, nil
+	case ExportXLSX:
+		return ExportXLSX, nil
+	}
+	return "", ErrInvalidExportFormat
+}
+
+func (f ExportFormat) ContentType() string {
+	if f == ExportXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Export: archivo listo para enviarse. Las consultas que pueden fallar antes
+// de empezar la respuesta ya se hicieron; Write escribe el contenido una sola
+// vez y libera el cursor o los temporales del libro.
+type Export struct {
+	Filename    string
+	ContentType string
+	write       func(w io.Writer) error
+	close       func()
+}
+
+func (e *Export) Write(w io.Writer) error {
+	defer e.Close()
+	return e.write(w)
+}
+
+// Close: libera los recursos si el archivo no llega a escribirse
+func (e *Export) Close() {
+	if e.close != nil {
+		e.close()
+		e.close = nil
+	}
+}
+
+type ExportService interface {
+	Votes(electionID, userID, userRole string, format ExportFormat) (*Export, error)
+	Results(electionID string, format ExportFormat) (*Export, error)
+	PositionResults(positionID string, format ExportFormat) (*Export, error)
+}
+
+type exportServiceImpl struct {
+	db *gorm.DB
+}
+
+// NewExportService: exporta votos y resultados recorriendo la BD con cursores,
+// sin cargar todos los votos en memoria como VoteService.GetAll
+func NewExportService(db *gorm.DB) ExportService {
+	return &exportServiceImpl{db: db}
+}
+
+// voteExportRow: un voto con su mesa, candidato y posición
+type voteExportRow struct {
+	ID            string
+	MesaNumber    string
+	MesaStatus    string
+	PositionName  string
+	TypePosition  string
+	CandidateName string
+	TypeCandidate string
+	TypeVote      string
+	Vote          int
+	Revision      int
+	IsAnnulled    bool
+}
+
+var voteExportHeader = []string{
+	"Voto ID", "Mesa", "Estado mesa", "Posición", "Tipo posición",
+	"Candidato", "Tipo candidato", "Tipo voto", "Votos", "Revisión", "Anulado",
+}
+
+// Votes: una fila por voto, incluidos los anulados, ordenados por mesa
+func (s *exportServiceImpl) Votes(electionID, userID, userRole string, format ExportFormat) (*Export, error) {
+	party, scoped, err := personeroParty(s.db, userID, userRole)
+	if err != nil {
+		return nil, err
+	}
+
+	q := s.db.Model(&models.Vote{}).
+		Select(`votes.id, mesas.number AS mesa_number, mesas.status AS mesa_status,
+			positions.name AS position_name, positions.type_position,
+			candidates.name AS candidate_name, candidates.type_candidate,
+			votes.type_vote, votes.vote, votes.revision, votes.is_annulled`).
+		Joins("JOIN mesas ON mesas.id = votes.mesa_id").
+		Joins("JOIN candidates ON candidates.id = votes.candidate_id").
+		Joins("LEFT JOIN positions ON positions.id = candidates.position_id").
+		Order("mesas.number, positions.name, candidates.name, votes.type_vote")
+	if electionID != "" {
+		q = q.Where("mesas.election_id = ?", electionID)
+	}
+	if scoped {
+		q = q.Where("candidates.party = ?", party)
+	}
+
+	rows, err := q.Rows()
+	if err != nil {
+		return nil, err
+	}
+
+	writeRows := func(t tableWriter) error {
+		if err := t.header(cells(voteExportHeader)...); err != nil {
+			return err
+		}
+		for rows.Next() {
+			var v voteExportRow
+			if err := s.db.ScanRows(rows, &v); err != nil {
+				return err
+			}
+			if err := t.row(v.ID, v.MesaNumber, v.MesaStatus, v.PositionName, v.TypePosition,
+				v.CandidateName, v.TypeCandidate, v.TypeVote, v.Vote, v.Revision, v.IsAnnulled); err != nil {
+				return err
+			}
+		}
+		return rows.Err()
+	}
+
+	if format == ExportCSV {
+		return newCSVExport("votos", writeRows, func() { rows.Close() }), nil
+	}
+
+	defer rows.Close()
+	book := newXLSXBook()
+	if err := book.sheet("Votos", writeRows); err != nil {
+		book.file.Close()
+		return nil, err
+	}
+	return book.export("votos"), nil
+}
+
+// exportPosition: posición con sus candidatos y su resultado ya tabulado
+type exportPosition struct {
+	position models.Position
+	result   dto.PositionResultResponse
+}
+
+// Results: una hoja por posición con el desglose por mesa; en CSV, las
+// posiciones van una tras otra en formato largo
+func (s *exportServiceImpl) Results(electionID string, format ExportFormat) (*Export, error) {
+	var positions []models.Position
+	q := s.db.Preload("Candidates").Order("name")
+	if electionID != "" {
+		q = q.Where("election_id = ?", electionID)
+	}
+	if err := q.Find(&positions).Error; err != nil {
+		return nil, err
+	}
+
+	tallies, err := (&resultServiceImpl{db: s.db}).loadTallies(electionID)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]exportPosition, len(positions))
+	for i, p := range positions {
+		list[i] = exportPosition{position: p, result: tabulatePosition(p, tallies)}
+	}
+	return s.results("resultados", list, format, true)
+}
+
+func (s *exportServiceImpl) PositionResults(positionID string, format ExportFormat) (*Export, error) {
+	var position models.Position
+	if err := s.db.Preload("Candidates").First(&position, "id = ?", positionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPositionNotFound
+		}
+		return nil, err
+	}
+
+	tallies, err := (&resultServiceImpl{db: s.db}).loadTallies(position.ElectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	list := []exportPosition{{position: position, result: tabulatePosition(position, tallies)}}
+	return s.results("resultados-"+slugify(position.Name), list, format, false)
+}
+
+func (s *exportServiceImpl) results(name string, list []exportPosition, format ExportFormat, summary bool) (*Export, error) {
+	if format == ExportCSV {
+		return newCSVExport(name, func(t tableWriter) error {
+			if err := t.header(cells(resultCSVHeader)...); err != nil {
+				return err
+			}
+			for _, p := range list {
+				if err := s.resultCSVRows(t, p); err != nil {
+					return err
+				}
+			}
+			return nil
+		}, nil), nil
+	}
+
+	book := newXLSXBook()
+	err := func() error {
+		if summary {
+			if err := book.sheet("Resumen", func(t tableWriter) error {
+				return resultSummaryRows(t, list)
+			}); err != nil {
+				return err
+			}
+		}
+		for _, p := range list {
+			if err := book.sheet(p.position.Name, func(t tableWriter) error {
+				return s.resultSheetRows(t, p)
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}()
+	if err != nil {
+		book.file.Close()
+		return nil, err
+	}
+	return book.export(name), nil
+}
+
+// mesaBreakdown: votos vigentes de una posición en una mesa, por candidato y tipo de voto
+type mesaBreakdown struct {
+	Number string
+	Status string
+	votes  map[string]map[models.TypeVote]int
+}
+
+func (m mesaBreakdown) get(candidateID string, tv models.TypeVote) int {
+	return m.votes[candidateID][tv]
+}
+
+// mesaVoteRow: fila agregada del cursor de eachMesaBreakdown
+type mesaVoteRow struct {
+	MesaID      string
+	MesaNumber  string
+	MesaStatus  string
+	CandidateID string
+	TypeVote    models.TypeVote
+	Total       int
+}
+
+// eachMesaBreakdown: recorre con un cursor los votos de la posición agregados
+// por mesa y llama a fn con cada mesa completa. Aplica los mismos filtros que
+// loadTallies, de modo que la suma de las mesas coincide con el resultado.
+func (s *exportServiceImpl) eachMesaBreakdown(positionID string, fn func(mesaBreakdown) error) error {
+	rows, err := s.db.Model(&models.Vote{}).
+		Select(`mesas.id AS mesa_id, mesas.number AS mesa_number, mesas.status AS mesa_status,
+			votes.candidate_id, votes.type_vote, SUM(votes.vote) AS total`).
+		Joins("JOIN mesas ON mesas.id = votes.mesa_id").
+		Joins("JOIN candidates ON candidates.id = votes.candidate_id").
+		Where("candidates.position_id = ? AND mesas.status <> ? AND votes.is_annulled = false", positionID, models.MSannulled).
+		Group("mesas.id, mesas.number, mesas.status, votes.candidate_id, votes.type_vote").
+		Order("mesas.number, mesas.id").
+		Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	return scanMesaBreakdown(s.db, rows, fn)
+}
+
+func scanMesaBreakdown(db *gorm.DB, rows *sql.Rows, fn func(mesaBreakdown) error) error {
+	var current *mesaBreakdown
+	currentID := ""
+	for rows.Next() {
+		var r mesaVoteRow
+		if err := db.ScanRows(rows, &r); err != nil {
+			return err
+		}
+		if current == nil || r.MesaID != currentID {
+			if current != nil {
+				if err := fn(*current); err != nil {
+					return err
+				}
+			}
+			current = &mesaBreakdown{Number: r.MesaNumber, Status: r.MesaStatus, votes: map[string]map[models.TypeVote]int{}}
+			currentID = r.MesaID
+		}
+		if current.votes[r.CandidateID] == nil {
+			current.votes[r.CandidateID] = map[models.TypeVote]int{}
+		}
+		current.votes[r.CandidateID][r.TypeVote] += r.Total
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if current != nil {
+		return fn(*current)
+	}
+	return nil
+}
+
+// exportColumns: candidatos en el orden del resultado (por votos) seguidos de
+// los votos nulos, que el resultado no lista como candidatos
+func exportColumns(p exportPosition) []models.Candidate {
+	byID := make(map[string]models.Candidate, len(p.position.Candidates))
+	for _, c := range p.position.Candidates {
+		byID[c.ID] = c
+	}
+
+	columns := make([]models.Candidate, 0, len(p.position.Candidates))
+	for _, c := range p.result.Candidates {
+		columns = append(columns, byID[c.ID])
+	}
+	for _, c := range p.position.Candidates {
+		if c.TypeCandidate == models.TCnull {
+			columns = append(columns, c)
+		}
+	}
+	return columns
+}
+
+var resultCSVHeader = []string{
+	"Posición", "Tipo posición", "Mesa", "Estado mesa", "Candidato", "Tipo candidato",
+	"DOCENTES", "PUBLICO", "Votos ponderados", "Porcentaje", "Ganador",
+}
+
+// resultCSVRows: una fila por mesa y candidato y, al final de la posición,
+// una fila TOTAL por candidato con los votos ponderados
+func (s *exportServiceImpl) resultCSVRows(t tableWriter, p exportPosition) error {
+	columns := exportColumns(p)
+	name, typePosition := p.position.Name, string(p.position.TypePosition)
+
+	err := s.eachMesaBreakdown(p.position.ID, func(m mesaBreakdown) error {
+		for _, c := range columns {
+			if err := t.row(name, typePosition, m.Number, m.Status, c.Name, string(c.TypeCandidate),
+				m.get(c.ID, models.TVpersonnel), m.get(c.ID, models.TVpublic), nil, nil, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, c := range p.result.Candidates {
+		if err := t.row(name, typePosition, "TOTAL", "", c.Name, string(models.TCcandidate),
+			c.VotesDocentes, c.VotesPublico, c.Votes, c.Percentage, c.IsWinner); err != nil {
+			return err
+		}
+	}
+	return t.row(name, typePosition, "TOTAL", "", "Votos nulos", string(models.TCnull),
+		nil, nil, p.result.NullVotes, nil, nil)
+}
+
+// resultSheetRows: hoja de una posición con una fila por mesa, dos columnas
+// (DOCENTES y PUBLICO) por candidato y debajo el resultado y su validez
+func (s *exportServiceImpl) resultSheetRows(t tableWriter, p exportPosition) error {
+	r := p.result
+	columns := exportColumns(p)
+
+	if err := t.header("Posición", r.PositionName); err != nil {
+		return err
+	}
+	if err := t.row("Tipo", r.TypePosition); err != nil {
+		return err
+	}
+	if p.position.TypePosition == models.TAposition {
+		if err := t.row("Factor de ponderación DOCENTES", r.WeightFactor); err != nil {
+			return err
+		}
+	}
+	if err := t.row(); err != nil {
+		return err
+	}
+
+	header := []string{"Mesa", "Estado"}
+	for _, c := range columns {
+		header = append(header, c.Name+" DOCENTES", c.Name+" PUBLICO")
+	}
+	if err := t.header(cells(append(header, "Total"))...); err != nil {
+		return err
+	}
+
+	totals := make([]int, 2*len(columns))
+	err := s.eachMesaBreakdown(p.position.ID, func(m mesaBreakdown) error {
+		values := []interface{}{m.Number, m.Status}
+		sum := 0
+		for i, c := range columns {
+			docentes, publico := m.get(c.ID, models.TVpersonnel), m.get(c.ID, models.TVpublic)
+			totals[2*i] += docentes
+			totals[2*i+1] += publico
+			sum += docentes + publico
+			values = append(values, docentes, publico)
+		}
+		return t.row(append(values, sum)...)
+	})
+	if err != nil {
+		return err
+	}
+
+	values := []interface{}{"TOTAL", ""}
+	for _, v := range totals {
+		values = append(values, v)
+	}
+	if err := t.header(append(values, r.EmittedVotes)...); err != nil {
+		return err
+	}
+	if err := t.row(); err != nil {
+		return err
+	}
+
+	if err := t.header("Candidato", "DOCENTES", "PUBLICO", "Votos ponderados", "Porcentaje", "Ganador"); err != nil {
+		return err
+	}
+	for _, c := range r.Candidates {
+		if err := t.row(c.Name, c.VotesDocentes, c.VotesPublico, c.Votes, c.Percentage, c.IsWinner); err != nil {
+			return err
+		}
+	}
+
+	rows := [][]interface{}{
+		{},
+		{"Votos válidos", r.ValidVotes},
+		{"Votos nulos", r.NullVotes},
+		{"Votos emitidos", r.EmittedVotes},
+		{"Electorado", r.Validity.Electorate},
+		{"Participación válida", r.Validity.Turnout},
+		{"Participación requerida", r.Validity.RequiredPercentage},
+		{"Validez", reportVerdicts[r.Validity.Status]},
+		{"Empate", r.IsTie},
+	}
+	for _, row := range rows {
+		if err := t.row(row...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resultSummaryRows: primera hoja del libro de una elección, una fila por posición
+func resultSummaryRows(t tableWriter, list []exportPosition) error {
+	if err := t.header("Posición", "Tipo", "Votos válidos", "Votos nulos", "Votos emitidos",
+		"Participación válida", "Ganador", "Validez"); err != nil {
+		return err
+	}
+	for _, p := range list {
+		r := p.result
+		if err := t.row(r.PositionName, r.TypePosition, r.ValidVotes, r.NullVotes, r.EmittedVotes,
+			r.Validity.Turnout, winnerLabel(r), reportVerdicts[r.Validity.Status]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newCSVExport: el CSV se genera mientras se envía la respuesta. Lleva BOM
+// para que Excel reconozca el UTF-8 al abrirlo con doble clic.
+func newCSVExport(name string, fill func(t tableWriter) error, close func()) *Export {
+	return &Export{
+		Filename:    name + ".csv",
+		ContentType: ExportCSV.ContentType(),
+		close:       close,
+		write: func(w io.Writer) error {
+			if _, err := io.WriteString(w, "\ufeff"); err != nil {
+				return err
+			}
+			cw := csv.NewWriter(w)
+			if err := fill(&csvTable{w: cw}); err != nil {
+				return err
+			}
+			cw.Flush()
+			return cw.Error()
+		},
+	}
+}
+
+// xlsxBook: libro escrito hoja a hoja con StreamWriter, que pasa a un archivo
+// temporal las filas que exceden su búfer en lugar de mantenerlas en memoria
+type xlsxBook struct {
+	file   *excelize.File
+	bold   int
+	sheets int
+	names  map[string]bool
+}
+
+func newXLSXBook() *xlsxBook {
+	f := excelize.NewFile()
+	bold, _ := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	return &xlsxBook{file: f, bold: bold, names: map[string]bool{}}
+}
+
+// sheet: crea la hoja (la primera reutiliza la hoja por defecto) y la llena
+func (b *xlsxBook) sheet(title string, fill func(t tableWriter) error) error {
+	name := b.sheetName(title)
+	if b.sheets == 0 {
+		if err := b.file.SetSheetName(b.file.GetSheetName(0), name); err != nil {
+			return err
+		}
+	} else if _, err := b.file.NewSheet(name); err != nil {
+		return err
+	}
+	b.sheets++
+
+	sw, err := b.file.NewStreamWriter(name)
+	if err != nil {
+		return err
+	}
+	if err := sw.SetColWidth(1, 1, 28); err != nil {
+		return err
+	}
+	if err := fill(&xlsxTable{sw: sw, bold: b.bold}); err != nil {
+		return err
+	}
+	return sw.Flush()
+}
+
+// sheetName: Excel limita los nombres a 31 caracteres sin []:*?/\ y únicos
+func (b *xlsxBook) sheetName(title string) string {
+	clean := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return ' '
+		}
+		return r
+	}, title)
+	clean = strings.Trim(strings.Join(strings.Fields(clean), " "), "'")
+	if clean == "" {
+		clean = "Hoja"
+	}
+
+	name := truncateRunes(clean, 31)
+	for i := 2; b.names[strings.ToLower(name)]; i++ {
+		suffix := " (" + strconv.Itoa(i) + ")"
+		name = truncateRunes(clean, 31-len(suffix)) + suffix
+	}
+	b.names[strings.ToLower(name)] = true
+	return name
+}
+
+func truncateRunes(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return strings.TrimSpace(string(runes[:max]))
+}
+
+func (b *xlsxBook) export(name string) *Export {
+	return &Export{
+		Filename:    name + ".xlsx",
+		ContentType: ExportXLSX.ContentType(),
+		close:       func() { b.file.Close() },
+		write: func(w io.Writer) error {
+			return b.file.Write(w)
+		},
+	}
+}
+
+// tableWriter: filas comunes a CSV y XLSX; header marca la fila en negrita
+// en XLSX y en CSV es una fila más
+type tableWriter interface {
+	header(values ...interface{}) error
+	row(values ...interface{}) error
+}
+
+func cells(values []string) []interface{} {
+	res := make([]interface{}, len(values))
+	for i, v := range values {
+		res[i] = v
+	}
+	return res
+}
+
+// exportCell: texto de una celda CSV; los booleanos van como SI/NO también en XLSX
+func exportCell(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		if v {
+			return "SI"
+		}
+		return "NO"
+	}
+	return fmt.Sprint(v)
+}
+
+type csvTable struct {
+	w *csv.Writer
+}
+
+func (t *csvTable) header(values ...interface{}) error {
+	return t.row(values...)
+}
+
+func (t *csvTable) row(values ...interface{}) error {
+	record := make([]string, len(values))
+	for i, v := range values {
+		record[i] = exportCell(v)
+		if _, text := v.(string); text {
+			record[i] = csvSafeText(record[i])
+		}
+	}
+	return t.w.Write(record)
+}
+
+// csvSafeText: un texto que empieza como fórmula (nombres, partidos) se
+// prefija con ' para que la hoja de cálculo no lo evalúe. Los números no pasan
+// por aquí, así que un negativo sigue siendo número.
+func csvSafeText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+type xlsxTable struct {
+	sw   *excelize.StreamWriter
+	bold int
+	next int
+}
+
+func (t *xlsxTable) header(values ...interface{}) error {
+	return t.write(values, t.bold)
+}
+
+func (t *xlsxTable) row(values ...interface{}) error {
+	return t.write(values, 0)
+}
+
+func (t *xlsxTable) write(values []interface{}, style int) error {
+	row := make([]interface{}, len(values))
+	for i, v := range values {
+		if b, ok := v.(bool); ok {
+			v = exportCell(b)
+		}
+		row[i] = excelize.Cell{StyleID: style, Value: v}
+	}
+
+	t.next++
+	cell, err := excelize.CoordinatesToCellName(1, t.next)
+	if err != nil {
+		return err
+	}
+	return t.sw.SetRow(cell, row)
+}
@@ -2,23 +2,64 @@
 package services
 
 import (
+	"time"
 
 	"gorm.io/gorm"
 	"server/internal/dto"
+	"server/pkgs/logger"
 	"server/pkgs/security"
 )
 
 
 
+// googleProvider: valor de Account.Provider para cuentas vinculadas con Google
+const googleProvider = "google"
+
+// AuthSettings: parámetros de configuración del login
+type AuthSettings struct {
+	RefreshTTL       time.Duration
+	MFAIssuer        string
+	MFARequiredRoles []string
+
+	LoginWindow       time.Duration
+	LoginIPLimit      int
+	LoginEmailLimit   int
+	LoginMaxFailures  int
+	LoginLockDuration time.Duration
+
+	PasswordPolicy *security.PasswordPolicy
+}
+
 type authServiceImpl struct {
-	db    *gorm.DB
-	argon *security.Argon2Service
+	db       *gorm.DB
+	argon    *security.Argon2Service
+	google   *security.GoogleVerifier
+	jwt      *security.JWTService
+	settings AuthSettings
+
+	// dummyHash: hash contra el que se compara la contraseña de un email
+	// desconocido, para que tarde lo mismo que uno registrado
+	dummyHash string
 }
 
 type AuthService interface {
-	Signin(req dto.SigninRequest) (*dto.AuthResponse, error)
+	Signin(req dto.SigninRequest, ip string) (*dto.AuthResponse, error)
+	Refresh(req dto.RefreshRequest) (*dto.AuthResponse, error)
+	Logout(req dto.RefreshRequest) error
+	LogoutAll(userID string) error
+	ChangePassword(userID string, req dto.ChangePasswordRequest) (*dto.AuthResponse, error)
+	AcceptInvite(req dto.AcceptInviteRequest) (*dto.AuthResponse, error)
+
+	EnrollMFA(userID string) (*dto.MFAEnrollResponse, error)
+	VerifyMFA(userID string, req dto.MFACodeRequest, enrollment bool) (*dto.MFAVerifyResponse, error)
+	DisableMFA(userID string, req dto.MFACodeRequest) error
+	ChallengeMFA(req dto.MFAChallengeRequest, ip string) (*dto.AuthResponse, error)
 }
 
-func NewAuthService(db *gorm.DB, argon *security.Argon2Service) AuthService {
-	return &authServiceImpl{db, argon}
+func NewAuthService(db *gorm.DB, argon *security.Argon2Service, google *security.GoogleVerifier, jwt *security.JWTService, settings AuthSettings) AuthService {
+	dummyHash, err := argon.HashPassword(security.GenerateRandomToken(16))
+	if err != nil {
+		logger.Log.Errorf("❌ No se pudo generar el hash de relleno del login: %v", err)
+	}
+	return &authServiceImpl{db, argon, google, jwt, settings, dummyHash}
 }
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"errors"
+	"server/internal/dto"
+	"server/internal/models"
+	"server/pkgs/security"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	mfaChallengeTTL   = 5 * time.Minute
+	mfaEnrollTTL      = 15 * time.Minute
+	recoveryCodeCount = 10
+
+	mfaStatusRequired           = "mfa_required"
+	mfaStatusEnrollmentRequired = "mfa_enrollment_required"
+)
+
+// completeSignin: tras validar la primera credencial decide si se emiten los
+// tokens o se exige el segundo factor (o su enrolamiento, si la política del
+// rol lo obliga y el usuario aún no lo tiene)
+func (s *authServiceImpl) completeSignin(u *models.User) (*dto.AuthResponse, error) {
+	var purpose, status string
+	switch {
+	case u.TOTPEnabled:
+		purpose, status = security.ChallengeMFA, mfaStatusRequired
+	case s.mfaRequired(u.Rol):
+		purpose, status = security.ChallengeMFAEnroll, mfaStatusEnrollmentRequired
+	default:
+		return s.buildAuthResponseWithToken(u)
+	}
+
+	ttl := mfaChallengeTTL
+	if purpose == security.ChallengeMFAEnroll {
+		ttl = mfaEnrollTTL
+	}
+	token, err := s.jwt.GenerateChallengeToken(u.ID, purpose, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.AuthResponse{
+		ID:        u.ID,
+		Email:     u.Email,
+		Name:      &u.Name,
+		Role:      string(u.Rol),
+		MFAStatus: status,
+		MFAToken:  token,
+	}, nil
+}
+
+// EnrollMFA: genera un secreto nuevo pendiente de verificación
+func (s *authServiceImpl) EnrollMFA(userID string) (*dto.MFAEnrollResponse, error) {
+	user, err := s.findActiveUser(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TOTPEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	secret, err := security.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Model(user).Updates(map[string]interface{}{
+		"totp_secret":    secret,
+		"totp_last_step": 0,
+	}).Error; err != nil {
+		return nil, err
+	}
+
+	return &dto.MFAEnrollResponse{
+		Secret:          secret,
+		ProvisioningURI: security.TOTPProvisioningURI(s.settings.MFAIssuer, user.Email, secret),
+	}, nil
+}
+
+// VerifyMFA: confirma el enrolamiento con el primer código y entrega los
+// códigos de recuperación (solo se muestran esta vez). Si se llegó aquí con
+// el challenge de enrolamiento obligatorio, también se emiten los tokens.
+func (s *authServiceImpl) VerifyMFA(userID string, req dto.MFACodeRequest, enrollment bool) (*dto.MFAVerifyResponse, error) {
+	var res dto.MFAVerifyResponse
+	var user *models.User
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		user, err = s.findActiveUser(tx.Clauses(clause.Locking{Strength: "UPDATE"}), userID)
+		if err != nil {
+			return err
+		}
+		if user.TOTPEnabled {
+			return ErrMFAAlreadyEnabled
+		}
+		if user.TOTPSecret == nil {
+			return ErrMFANotEnrolled
+		}
+
+		step, ok := security.VerifyTOTP(*user.TOTPSecret, req.Code, time.Now())
+		if !ok {
+			return ErrMFAInvalidCode
+		}
+
+		codes, err := replaceRecoveryCodes(tx, user.ID)
+		if err != nil {
+			return err
+		}
+		res.RecoveryCodes = codes
+
+		user.TOTPEnabled = true
+		user.TOTPLastStep = step
+		return tx.Model(user).Updates(map[string]interface{}{
+			"totp_enabled":   true,
+			"totp_last_step": step,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if enrollment {
+		auth, err := s.buildAuthResponseWithToken(user)
+		if err != nil {
+			return nil, err
+		}
+		res.Auth = auth
+	}
+
+	return &res, nil
+}
+
+// DisableMFA: exige un código válido y no se permite si el rol lo requiere
+func (s *authServiceImpl) DisableMFA(userID string, req dto.MFACodeRequest) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		user, err := s.findActiveUser(tx.Clauses(clause.Locking{Strength: "UPDATE"}), userID)
+		if err != nil {
+			return err
+		}
+		if !user.TOTPEnabled {
+			return ErrMFANotEnabled
+		}
+		if s.mfaRequired(user.Rol) {
+			return ErrMFARequiredByPolicy
+		}
+
+		if err := s.checkSecondFactor(tx, user, req.Code, req.RecoveryCode); err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", user.ID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(user).Updates(map[string]interface{}{
+			"totp_enabled":   false,
+			"totp_secret":    nil,
+			"totp_last_step": 0,
+		}).Error
+	})
+}
+
+// ChallengeMFA: canjea el challenge de Signin por los tokens de sesión. Los
+// códigos erróneos cuentan para los mismos límites y bloqueo que la contraseña.
+func (s *authServiceImpl) ChallengeMFA(req dto.MFAChallengeRequest, ip string) (*dto.AuthResponse, error) {
+	userID, err := s.jwt.ValidateChallengeToken(req.MFAToken, security.ChallengeMFA)
+	if err != nil {
+		return nil, ErrMFATokenInvalid
+	}
+
+	user, err := s.findActiveUser(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+	attempt, err := s.beginLoginAttempt(user.Email, ip)
+	if err != nil {
+		return nil, err
+	}
+	success, reason := false, "error"
+	defer func() { s.finishLoginAttempt(attempt, success, reason) }()
+
+	if err := checkAccountLock(user); err != nil {
+		reason = "locked"
+		return nil, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		user, err = s.findActiveUser(tx.Clauses(clause.Locking{Strength: "UPDATE"}), userID)
+		if err != nil {
+			return err
+		}
+		if !user.TOTPEnabled {
+			return ErrMFANotEnabled
+		}
+		return s.checkSecondFactor(tx, user, req.Code, req.RecoveryCode)
+	})
+	if errors.Is(err, ErrMFAInvalidCode) {
+		reason = "bad_mfa_code"
+		if err := s.registerLoginFailure(user); err != nil {
+			return nil, err
+		}
+		return nil, ErrMFAInvalidCode
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	success, reason = true, "mfa"
+	if err := s.resetLoginFailures(user); err != nil {
+		return nil, err
+	}
+
+	return s.buildAuthResponseWithToken(user)
+}
+
+// checkSecondFactor: acepta un código TOTP no usado antes o un código de
+// recuperación pendiente, que queda consumido. El usuario debe estar bloqueado.
+func (s *authServiceImpl) checkSecondFactor(tx *gorm.DB, user *models.User, code, recoveryCode string) error {
+	if code != "" {
+		step, ok := security.VerifyTOTP(*user.TOTPSecret, code, time.Now())
+		if !ok || step <= user.TOTPLastStep {
+			return ErrMFAInvalidCode
+		}
+		user.TOTPLastStep = step
+		return tx.Model(user).Update("totp_last_step", step).Error
+	}
+
+	recoveryCode = strings.ToLower(strings.TrimSpace(recoveryCode))
+	if recoveryCode == "" {
+		return ErrMFAInvalidCode
+	}
+
+	result := tx.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", user.ID, security.HashToken(recoveryCode)).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrMFAInvalidCode
+	}
+	return nil
+}
+
+func (s *authServiceImpl) mfaRequired(rol models.Rol) bool {
+	for _, r := range s.settings.MFARequiredRoles {
+		if models.Rol(r) == rol {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *authServiceImpl) findActiveUser(db *gorm.DB, userID string) (*models.User, error) {
+	var user models.User
+	if err := db.First(&user, "id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, ErrUserInactive
+	}
+	return &user, nil
+}
+
+// replaceRecoveryCodes: invalida los códigos anteriores y genera un juego nuevo
+func replaceRecoveryCodes(tx *gorm.DB, userID string) ([]string, error) {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return nil, err
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	rows := make([]models.RecoveryCode, recoveryCodeCount)
+	for i := range codes {
+		code := security.GenerateRecoveryCode()
+		if code == "" {
+			return nil, errors.New("no se pudo generar el código de recuperación")
+		}
+		codes[i] = code
+		rows[i] = models.RecoveryCode{UserID: userID, CodeHash: security.HashToken(code)}
+	}
+
+	if err := tx.Create(&rows).Error; err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"errors"
+	"server/internal/dto"
+	"server/internal/models"
+	"server/pkgs/logger"
+	"server/pkgs/security"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ChangePassword: el usuario cambia su propia contraseña aportando la actual.
+// Se cierran todas las sesiones y se devuelve una nueva para quien la cambió.
+func (s *authServiceImpl) ChangePassword(userID string, req dto.ChangePasswordRequest) (*dto.AuthResponse, error) {
+	if req.CurrentPassword == "" || req.NewPassword == "" {
+		return nil, ErrInvalidRequestBody
+	}
+
+	user, err := s.findActiveUser(s.db, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.argon.ComparePassword(user.Password, req.CurrentPassword); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	if req.NewPassword == req.CurrentPassword {
+		return nil, ErrPasswordUnchanged
+	}
+	if err := s.settings.PasswordPolicy.Validate(req.NewPassword, user.Email); err != nil {
+		return nil, err
+	}
+
+	hashed, err := s.argon.HashPassword(req.NewPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	var res *dto.AuthResponse
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(user, "id = ?", user.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(user).Updates(map[string]interface{}{
+			"password":             hashed,
+			"must_change_password": false,
+		}).Error; err != nil {
+			return err
+		}
+		user.MustChangePassword = false
+		if err := revokeRefreshTokens(tx.Where("user_id = ?", user.ID)); err != nil {
+			return err
+		}
+
+		res, _, err = s.issueTokens(tx, user, uuid.NewString())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// rehashIfNeeded: actualiza el hash si se creó con parámetros argon2 antiguos.
+// Solo se llama con la contraseña ya verificada; un fallo no impide el login.
+func (s *authServiceImpl) rehashIfNeeded(user *models.User, password string) {
+	if !s.argon.NeedsRehash(user.Password) {
+		return
+	}
+
+	hashed, err := s.argon.HashPassword(password)
+	if err != nil {
+		logger.Log.Errorf("❌ Rehash failed for %s: %v", user.Email, err)
+		return
+	}
+
+	if err := s.db.Model(user).Update("password", hashed).Error; err != nil {
+		logger.Log.Errorf("❌ Rehash failed for %s: %v", user.Email, err)
+		return
+	}
+	logger.Log.Infof("🔐 Password hash upgraded for %s", user.Email)
+}
+
+// AcceptInvite: canjea una invitación de un solo uso fijando la contraseña
+// inicial; después el login continúa como un signin normal (incluido 2FA)
+func (s *authServiceImpl) AcceptInvite(req dto.AcceptInviteRequest) (*dto.AuthResponse, error) {
+	if req.Token == "" || req.Password == "" {
+		return nil, ErrInvalidRequestBody
+	}
+
+	var user models.User
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var invite models.Invitation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("User").
+			First(&invite, "token_hash = ?", security.HashToken(req.Token)).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvitationInvalid
+			}
+			return err
+		}
+
+		if invite.UsedAt != nil || time.Now().After(invite.ExpiresAt) {
+			return ErrInvitationInvalid
+		}
+		if !invite.User.IsActive {
+			return ErrUserInactive
+		}
+
+		if err := s.settings.PasswordPolicy.Validate(req.Password, invite.User.Email); err != nil {
+			return err
+		}
+
+		hashed, err := s.argon.HashPassword(req.Password)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&invite).Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+		user = invite.User
+		user.Password = hashed
+		user.MustChangePassword = false
+		return tx.Model(&user).Updates(map[string]interface{}{
+			"password":             hashed,
+			"must_change_password": false,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.completeSignin(&user)
+}
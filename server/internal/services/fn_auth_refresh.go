@@ -0,0 +1,125 @@
+package services
+
+import (
+	"errors"
+	"server/internal/dto"
+	"server/internal/models"
+	"server/pkgs/security"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Refresh: rota el refresh token. Presentar uno ya rotado o revocado se trata
+// como robo y revoca toda la familia, cerrando también la sesión legítima.
+func (s *authServiceImpl) Refresh(req dto.RefreshRequest) (*dto.AuthResponse, error) {
+	if req.RefreshToken == "" {
+		return nil, ErrRefreshTokenInvalid
+	}
+
+	var res *dto.AuthResponse
+	var reused, inactive bool
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var current models.RefreshToken
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("User").
+			First(&current, "token_hash = ?", security.HashToken(req.RefreshToken)).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRefreshTokenInvalid
+			}
+			return err
+		}
+
+		if current.RevokedAt != nil {
+			reused = true
+			return revokeRefreshTokens(tx.Where("family_id = ?", current.FamilyID))
+		}
+
+		if time.Now().After(current.ExpiresAt) {
+			return ErrRefreshTokenExpired
+		}
+
+		if !current.User.IsActive {
+			inactive = true
+			return revokeRefreshTokens(tx.Where("user_id = ?", current.UserID))
+		}
+
+		issued, next, err := s.issueTokens(tx, &current.User, current.FamilyID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&current).Updates(map[string]interface{}{
+			"revoked_at":     time.Now(),
+			"replaced_by_id": next.ID,
+		}).Error; err != nil {
+			return err
+		}
+
+		res = issued
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// la revocación de la familia (o de todas las sesiones del usuario
+	// inactivo) ya quedó confirmada en la transacción
+	if reused {
+		return nil, ErrRefreshTokenReused
+	}
+	if inactive {
+		return nil, ErrUserInactive
+	}
+
+	return res, nil
+}
+
+// Logout: revoca la familia del refresh token presentado (la sesión actual)
+func (s *authServiceImpl) Logout(req dto.RefreshRequest) error {
+	if req.RefreshToken == "" {
+		return ErrRefreshTokenInvalid
+	}
+
+	var token models.RefreshToken
+	if err := s.db.Select("id", "family_id").
+		First(&token, "token_hash = ?", security.HashToken(req.RefreshToken)).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRefreshTokenInvalid
+		}
+		return err
+	}
+
+	return revokeRefreshTokens(s.db.Where("family_id = ?", token.FamilyID))
+}
+
+// LogoutAll: revoca todas las sesiones del usuario
+func (s *authServiceImpl) LogoutAll(userID string) error {
+	return revokeRefreshTokens(s.db.Where("user_id = ?", userID))
+}
+
+// createRefreshToken: genera un token opaco y guarda únicamente su hash
+func (s *authServiceImpl) createRefreshToken(tx *gorm.DB, userID, familyID string) (string, *models.RefreshToken, error) {
+	raw := security.GenerateRandomToken(32)
+	if raw == "" {
+		return "", nil, errors.New("no se pudo generar el refresh token")
+	}
+
+	token := models.RefreshToken{
+		UserID:    userID,
+		TokenHash: security.HashToken(raw),
+		FamilyID:  familyID,
+		ExpiresAt: time.Now().Add(s.settings.RefreshTTL),
+	}
+	if err := tx.Create(&token).Error; err != nil {
+		return "", nil, err
+	}
+
+	return raw, &token, nil
+}
+
+// revokeRefreshTokens: marca como revocados los tokens activos del filtro dado
+func revokeRefreshTokens(scope *gorm.DB) error {
+	return scope.Model(&models.RefreshToken{}).
+		Where("revoked_at IS NULL").
+		Update("revoked_at", time.Now()).Error
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"server/internal/dto"
+	"server/internal/models"
+	"server/pkgs/security"
+)
+
+func newRefreshTestService(t *testing.T) (*authServiceImpl, *gorm.DB) {
+	t.Helper()
+
+	db := openTestDB(t, &models.User{}, &models.RefreshToken{})
+	jwt, err := security.NewJWTService("test", "test", time.Minute, security.NewHMACKey("test", "test-secret-0123456789-0123456789"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &authServiceImpl{db: db, jwt: jwt, settings: AuthSettings{RefreshTTL: time.Hour}}, db
+}
+
+// refreshTestLogin: usuario nuevo con una familia de refresh tokens recién emitida
+func refreshTestLogin(t *testing.T, s *authServiceImpl, db *gorm.DB) (models.User, string) {
+	t.Helper()
+
+	user := models.User{Name: "Test", Email: uuid.NewString() + "@test.local", Rol: models.RolAdmin}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	res, _, err := s.issueTokens(db, &user, uuid.NewString())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return user, res.RefreshToken
+}
+
+func refreshTestRotate(s *authServiceImpl, token string) (string, error) {
+	res, err := s.Refresh(dto.RefreshRequest{RefreshToken: token})
+	if err != nil {
+		return "", err
+	}
+	return res.RefreshToken, nil
+}
+
+func refreshTestActive(t *testing.T, db *gorm.DB, userID string) int64 {
+	t.Helper()
+
+	var n int64
+	if err := db.Model(&models.RefreshToken{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Count(&n).Error; err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestRefreshRotation(t *testing.T) {
+	s, db := newRefreshTestService(t)
+
+	tests := []struct {
+		name string
+		// run: ejecuta el escenario y devuelve el error del último Refresh;
+		// wantActive son los tokens que deben quedarle activos al usuario
+		run        func(t *testing.T, user models.User, first string) error
+		wantErr    error
+		wantActive int64
+	}{
+		{
+			name: "rota y revoca el anterior",
+			run: func(t *testing.T, user models.User, first string) error {
+				_, err := refreshTestRotate(s, first)
+				return err
+			},
+			wantActive: 1,
+		},
+		{
+			name: "cadena de rotaciones",
+			run: func(t *testing.T, user models.User, first string) error {
+				token := first
+				for i := 0; i < 3; i++ {
+					next, err := refreshTestRotate(s, token)
+					if err != nil {
+						return err
+					}
+					token = next
+				}
+				return nil
+			},
+			wantActive: 1,
+		},
+		{
+			name: "reutilizar un token rotado revoca la familia",
+			run: func(t *testing.T, user models.User, first string) error {
+				if _, err := refreshTestRotate(s, first); err != nil {
+					t.Fatal(err)
+				}
+				_, err := refreshTestRotate(s, first)
+				return err
+			},
+			wantErr:    ErrRefreshTokenReused,
+			wantActive: 0,
+		},
+		{
+			name: "el sucesor también queda revocado tras la reutilización",
+			run: func(t *testing.T, user models.User, first string) error {
+				second, err := refreshTestRotate(s, first)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := refreshTestRotate(s, first); !errors.Is(err, ErrRefreshTokenReused) {
+					t.Fatalf("reutilización: got %v", err)
+				}
+				_, err = refreshTestRotate(s, second)
+				return err
+			},
+			wantErr:    ErrRefreshTokenReused,
+			wantActive: 0,
+		},
+		{
+			name: "la reutilización no toca otras sesiones",
+			run: func(t *testing.T, user models.User, first string) error {
+				if _, _, err := s.issueTokens(db, &user, uuid.NewString()); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := refreshTestRotate(s, first); err != nil {
+					t.Fatal(err)
+				}
+				_, err := refreshTestRotate(s, first)
+				return err
+			},
+			wantErr:    ErrRefreshTokenReused,
+			wantActive: 1,
+		},
+		{
+			name: "token vencido",
+			run: func(t *testing.T, user models.User, first string) error {
+				if err := db.Model(&models.RefreshToken{}).
+					Where("token_hash = ?", security.HashToken(first)).
+					Update("expires_at", time.Now().Add(-time.Minute)).Error; err != nil {
+					t.Fatal(err)
+				}
+				_, err := refreshTestRotate(s, first)
+				return err
+			},
+			wantErr:    ErrRefreshTokenExpired,
+			wantActive: 1,
+		},
+		{
+			name: "usuario desactivado",
+			run: func(t *testing.T, user models.User, first string) error {
+				if err := db.Model(&user).Update("is_active", false).Error; err != nil {
+					t.Fatal(err)
+				}
+				_, err := refreshTestRotate(s, first)
+				return err
+			},
+			wantErr:    ErrUserInactive,
+			wantActive: 0,
+		},
+		{
+			name: "token desconocido",
+			run: func(t *testing.T, user models.User, first string) error {
+				_, err := refreshTestRotate(s, security.GenerateRandomToken(32))
+				return err
+			},
+			wantErr:    ErrRefreshTokenInvalid,
+			wantActive: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, first := refreshTestLogin(t, s, db)
+
+			err := tt.run(t, user, first)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Refresh: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			if got := refreshTestActive(t, db, user.ID); got != tt.wantActive {
+				t.Errorf("%d tokens activos, want %d", got, tt.wantActive)
+			}
+		})
+	}
+}
@@ -2,27 +2,26 @@ package services
 
 import (
 	"errors"
-	"os"
 	"server/internal/dto"
 	"server/internal/models"
-	"time"
+	"strings"
 
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 // Signin: maneja login con provider "credentials" o "google"
-func (s *authServiceImpl) Signin(req dto.SigninRequest) (*dto.AuthResponse, error) {
+func (s *authServiceImpl) Signin(req dto.SigninRequest, ip string) (*dto.AuthResponse, error) {
 	if req.Provider == nil || *req.Provider == "" {
 		return nil, ErrInvalidRequestBody
 	}
 
 	switch *req.Provider {
 	case "google":
-		if req.Email == "" {
-			return nil, ErrSigninEmailRequired
+		if req.IDToken == "" {
+			return nil, ErrGoogleTokenRequired
 		}
-		return s.signinWithGoogle(req.Email)
+		return s.signinWithGoogle(req.IDToken)
 
 	case "credentials":
 		if req.Email == "" {
@@ -35,36 +34,100 @@ func (s *authServiceImpl) Signin(req dto.SigninRequest) (*dto.AuthResponse, erro
 			return nil, ErrSigninPasswordRequired
 		}
 
-		var user models.User
-		if err := s.db.Where("email = ?", req.Email).First(&user).Error; err != nil {
-			if errors.Is(err, gorm.ErrRecordNotFound) {
-				return nil, ErrUserNotFound
-			}
-			return nil, err
-		}
+		email := strings.ToLower(strings.TrimSpace(req.Email))
+		return s.signinWithPassword(email, req.Password, ip)
 
-		if !user.IsActive {
-			return nil, ErrUserInactive
-		}
+	default:
+		return nil, ErrInvalidRequestBody
+	}
+}
 
-		if err := s.argon.ComparePassword(user.Password, req.Password); err != nil {
+// signinWithPassword: login con email y contraseña. Los límites se comprueban
+// antes de argon2 para no pagar su coste; a partir de ahí toda salida resuelve
+// el intento, que si no seguiría pendiente y contando como fallo.
+func (s *authServiceImpl) signinWithPassword(email, password, ip string) (*dto.AuthResponse, error) {
+	attempt, err := s.beginLoginAttempt(email, ip)
+	if err != nil {
+		return nil, err
+	}
+	success, reason := false, "error"
+	defer func() { s.finishLoginAttempt(attempt, success, reason) }()
+
+	var user models.User
+	if err := s.db.Where("LOWER(email) = ?", email).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// mismo coste y misma respuesta que una contraseña incorrecta, para
+			// no revelar qué emails están registrados
+			s.argon.ComparePassword(s.dummyHash, password)
+			reason = "unknown_email"
 			return nil, ErrInvalidCredentials
 		}
+		return nil, err
+	}
 
-		return s.buildAuthResponseWithToken(&user)
+	if err := checkAccountLock(&user); err != nil {
+		reason = "locked"
+		return nil, err
+	}
 
-	default:
-		return nil, ErrInvalidRequestBody
+	if !user.IsActive {
+		reason = "inactive"
+		return nil, ErrUserInactive
 	}
+
+	if err := s.argon.ComparePassword(user.Password, password); err != nil {
+		reason = "bad_password"
+		if err := s.registerLoginFailure(&user); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidCredentials
+	}
+
+	// la contraseña es correcta: un error posterior es del servidor, no un fallo
+	success, reason = true, ""
+	if err := s.resetLoginFailures(&user); err != nil {
+		return nil, err
+	}
+	s.rehashIfNeeded(&user, password)
+
+	return s.completeSignin(&user)
 }
 
-// signinWithGoogle: login mediante Google
-func (s *authServiceImpl) signinWithGoogle(email string) (*dto.AuthResponse, error) {
+// signinWithGoogle: login mediante Google. El ID token se verifica contra el
+// JWKS configurado; la cuenta se busca por el sub de Google y, la primera vez,
+// se vincula al usuario con el mismo email.
+func (s *authServiceImpl) signinWithGoogle(idToken string) (*dto.AuthResponse, error) {
+	claims, err := s.google.Verify(idToken)
+	if err != nil {
+		return nil, err
+	}
+
 	var user models.User
-	if err := s.db.Where("email = ?", email).First(&user).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrGoogleUserNotRegistered
+	var account models.Account
+	err = s.db.Preload("User").
+		Where("provider = ? AND provider_account_id = ?", googleProvider, claims.Subject).
+		First(&account).Error
+	switch {
+	case err == nil:
+		user = account.User
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if err := s.db.Where("LOWER(email) = LOWER(?)", claims.Email).First(&user).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrGoogleUserNotRegistered
+			}
+			return nil, err
+		}
+
+		account = models.Account{
+			UserID:            user.ID,
+			Type:              "oidc",
+			Provider:          googleProvider,
+			ProviderAccountID: claims.Subject,
+		}
+		if err := s.db.Create(&account).Error; err != nil {
+			return nil, err
 		}
+	default:
 		return nil, err
 	}
 
@@ -72,34 +135,36 @@ func (s *authServiceImpl) signinWithGoogle(email string) (*dto.AuthResponse, err
 		return nil, ErrUserInactive
 	}
 
-	return s.buildAuthResponseWithToken(&user)
+	return s.completeSignin(&user)
 }
 
-// buildAuthResponseWithToken: genera JWT y construye la respuesta
+// buildAuthResponseWithToken: inicia una nueva familia de refresh tokens
 func (s *authServiceImpl) buildAuthResponseWithToken(u *models.User) (*dto.AuthResponse, error) {
-	jwtSecret := []byte(os.Getenv("JWT_SECRET"))
-	if len(jwtSecret) == 0 {
-		return nil, errors.New("JWT_SECRET not set")
-	}
+	res, _, err := s.issueTokens(s.db, u, uuid.NewString())
+	return res, err
+}
 
-	claims := jwt.MapClaims{
-		"id":    u.ID,
-		"email": u.Email,
-		"role":  string(u.Rol),
-		"exp":   time.Now().Add(365 * 24 * time.Hour).Unix(),
+// issueTokens: genera el access token (JWT corto) y un refresh token opaco
+// dentro de la familia indicada
+func (s *authServiceImpl) issueTokens(tx *gorm.DB, u *models.User, familyID string) (*dto.AuthResponse, *models.RefreshToken, error) {
+	tokenString, expiresAt, err := s.jwt.GenerateToken(u.ID, u.Email, string(u.Rol), u.MustChangePassword)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString(jwtSecret)
+	refresh, stored, err := s.createRefreshToken(tx, u.ID, familyID)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	return &dto.AuthResponse{
-		ID:    u.ID,
-		Email: u.Email,
-		Name:  &u.Name,
-		Role:  string(u.Rol),
-		Token: tokenString,
-	}, nil
+		ID:                 u.ID,
+		Email:              u.Email,
+		Name:               &u.Name,
+		Role:               string(u.Rol),
+		Token:              tokenString,
+		MustChangePassword: u.MustChangePassword,
+		ExpiresAt:          expiresAt.Unix(),
+		RefreshToken:       refresh,
+	}, stored, nil
 }
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"server/internal/dto"
+	"server/internal/models"
+	"server/pkgs/security"
+)
+
+func TestSigninResolvesAttempt(t *testing.T) {
+	db := openTestDB(t, &models.User{}, &models.RefreshToken{}, &models.LoginAttempt{})
+	argon := security.NewArgon2ServiceWithParams(&security.Argon2Params{
+		Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32,
+	})
+	jwt, err := security.NewJWTService("test", "test", time.Minute, security.NewHMACKey("test", "test-secret-0123456789-0123456789"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewAuthService(db, argon, nil, jwt, AuthSettings{
+		RefreshTTL:      time.Hour,
+		LoginWindow:     time.Hour,
+		LoginIPLimit:    100,
+		LoginEmailLimit: 100,
+	}).(*authServiceImpl)
+
+	hash, err := argon.HashPassword("correcta-123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lockedUntil := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name        string
+		user        *models.User
+		password    string
+		wantErr     error
+		wantSuccess bool
+		wantReason  string
+	}{
+		{"email desconocido", nil, "correcta-123", ErrInvalidCredentials, false, "unknown_email"},
+		{"contraseña incorrecta", &models.User{IsActive: true}, "otra", ErrInvalidCredentials, false, "bad_password"},
+		{"usuario inactivo", &models.User{IsActive: false}, "correcta-123", ErrUserInactive, false, "inactive"},
+		{"cuenta bloqueada", &models.User{IsActive: true, LockedUntil: &lockedUntil}, "correcta-123", ErrAccountLocked, false, "locked"},
+		{"login correcto", &models.User{IsActive: true}, "correcta-123", nil, true, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			email := uuid.NewString() + "@test.local"
+			if tt.user != nil {
+				u := *tt.user
+				u.Name, u.Email, u.Password, u.Rol = "Test", email, hash, models.RolAdmin
+				if err := db.Create(&u).Error; err != nil {
+					t.Fatal(err)
+				}
+				// IsActive tiene default en la BD: el false de la tabla no se insertaría
+				if err := db.Model(&u).Update("is_active", tt.user.IsActive).Error; err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			provider := "credentials"
+			_, err := s.Signin(dto.SigninRequest{Provider: &provider, Email: email, Password: tt.password}, "10.0.0.1")
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Signin: %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+
+			var attempts []models.LoginAttempt
+			if err := db.Where("email = ?", email).Find(&attempts).Error; err != nil {
+				t.Fatal(err)
+			}
+			if len(attempts) != 1 {
+				t.Fatalf("%d intentos registrados, want 1", len(attempts))
+			}
+			if a := attempts[0]; a.Success != tt.wantSuccess || a.Reason != tt.wantReason {
+				t.Errorf("intento = {success=%v reason=%q}, want {success=%v reason=%q}",
+					a.Success, a.Reason, tt.wantSuccess, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestSigninUnknownIPSkipsIPWindow(t *testing.T) {
+	db := openTestDB(t, &models.User{}, &models.LoginAttempt{})
+	s := &authServiceImpl{db: db, settings: AuthSettings{
+		LoginWindow:     time.Hour,
+		LoginIPLimit:    1,
+		LoginEmailLimit: 100,
+	}}
+
+	// con la IP desconocida, emails distintos no comparten ventana
+	for i := 0; i < 3; i++ {
+		attempt, err := s.beginLoginAttempt(uuid.NewString()+"@test.local", "")
+		if err != nil {
+			t.Fatalf("intento %d: %v", i+1, err)
+		}
+		s.finishLoginAttempt(attempt, false, "bad_password")
+	}
+
+	if _, err := s.beginLoginAttempt(uuid.NewString()+"@test.local", "10.0.0.1"); err != nil {
+		t.Fatal(err)
+	}
+	var limited *RateLimitError
+	if _, err := s.beginLoginAttempt(uuid.NewString()+"@test.local", "10.0.0.1"); !errors.As(err, &limited) {
+		t.Errorf("misma IP conocida: got %v, want RateLimitError", err)
+	}
+}
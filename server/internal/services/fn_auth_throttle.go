@@ -0,0 +1,160 @@
+package services
+
+import (
+	"fmt"
+	"server/internal/models"
+	"server/pkgs/logger"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RateLimitError: login rechazado por límite o bloqueo; RetryAfter indica
+// cuándo volver a intentarlo (cabecera Retry-After)
+type RateLimitError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s (retry in %ds)", e.Err.Error(), e.RetrySeconds())
+}
+
+func (e *RateLimitError) Unwrap() error { return e.Err }
+
+// RetrySeconds: RetryAfter redondeado hacia arriba, mínimo 1 segundo
+func (e *RateLimitError) RetrySeconds() int {
+	secs := int((e.RetryAfter + time.Second - 1) / time.Second)
+	if secs < 1 {
+		return 1
+	}
+	return secs
+}
+
+// loginAttemptLock: espacio de los advisory locks por IP y por email que
+// serializan la comprobación del límite con el registro del intento
+const loginAttemptLock = 0x6c6f67
+
+// beginLoginAttempt: ventana deslizante de intentos fallidos sobre
+// login_attempts, por IP y por email. Con los advisory locks de ambas claves
+// tomados, comprueba los límites y deja registrado el intento como pendiente,
+// que cuenta como fallo hasta que finishLoginAttempt lo resuelva; así varias
+// peticiones simultáneas no pueden pasar todas por el mismo hueco.
+// Con ip vacía (IP del cliente desconocida) solo se aplica el límite por email.
+func (s *authServiceImpl) beginLoginAttempt(email, ip string) (*models.LoginAttempt, error) {
+	attempt := &models.LoginAttempt{Email: email, IP: ip, Success: false, Reason: "pending"}
+	since := time.Now().Add(-s.settings.LoginWindow)
+
+	keys := []string{"email:" + email}
+	if ip != "" {
+		// siempre en el mismo orden (IP, email) para no provocar interbloqueos
+		keys = append([]string{"ip:" + ip}, keys...)
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		for _, key := range keys {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?, hashtext(?))", loginAttemptLock, key).Error; err != nil {
+				return err
+			}
+		}
+
+		if ip != "" {
+			if err := s.checkAttemptWindow(tx, "ip = ?", ip, since, s.settings.LoginIPLimit); err != nil {
+				return err
+			}
+		}
+		if err := s.checkAttemptWindow(tx, "email = ?", email, since, s.settings.LoginEmailLimit); err != nil {
+			return err
+		}
+		return tx.Create(attempt).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return attempt, nil
+}
+
+// checkAttemptWindow: solo cuentan los intentos fallidos (y los pendientes)
+func (s *authServiceImpl) checkAttemptWindow(tx *gorm.DB, cond string, value string, since time.Time, limit int) error {
+	if limit <= 0 {
+		return nil
+	}
+
+	failures := func() *gorm.DB {
+		return tx.Model(&models.LoginAttempt{}).
+			Where(cond+" AND success = ? AND created_at > ?", value, false, since)
+	}
+
+	var count int64
+	if err := failures().Count(&count).Error; err != nil {
+		return err
+	}
+	if count < int64(limit) {
+		return nil
+	}
+
+	// Se libera un hueco cuando sale de la ventana el fallo número count-limit+1
+	var oldest models.LoginAttempt
+	if err := failures().Select("created_at").
+		Order("created_at").
+		Offset(int(count) - limit).
+		First(&oldest).Error; err != nil {
+		return err
+	}
+
+	return &RateLimitError{
+		Err:        ErrTooManyAttempts,
+		RetryAfter: time.Until(oldest.CreatedAt.Add(s.settings.LoginWindow)),
+	}
+}
+
+// finishLoginAttempt: resuelve el intento pendiente; es best-effort, un fallo
+// no bloquea el login (el intento sigue contando como fallido)
+func (s *authServiceImpl) finishLoginAttempt(attempt *models.LoginAttempt, success bool, reason string) {
+	if err := s.db.Model(attempt).Updates(map[string]interface{}{
+		"success": success,
+		"reason":  reason,
+	}).Error; err != nil {
+		logger.Log.Errorf("❌ No se pudo registrar el intento de login: %v", err)
+	}
+}
+
+func checkAccountLock(user *models.User) error {
+	if user.LockedUntil != nil && time.Now().Before(*user.LockedUntil) {
+		return &RateLimitError{Err: ErrAccountLocked, RetryAfter: time.Until(*user.LockedUntil)}
+	}
+	return nil
+}
+
+// registerLoginFailure: cuenta el fallo y bloquea la cuenta al llegar al máximo
+func (s *authServiceImpl) registerLoginFailure(user *models.User) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var current models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "failed_attempts").First(&current, "id = ?", user.ID).Error; err != nil {
+			return err
+		}
+
+		failures := current.FailedAttempts + 1
+		updates := map[string]interface{}{"failed_attempts": failures}
+		if s.settings.LoginMaxFailures > 0 && failures >= s.settings.LoginMaxFailures {
+			lockedUntil := time.Now().Add(s.settings.LoginLockDuration)
+			updates["failed_attempts"] = 0
+			updates["locked_until"] = lockedUntil
+			logger.Log.Warnf("⚠️ Cuenta %s bloqueada hasta %s", user.Email, lockedUntil.Format(time.RFC3339))
+		}
+
+		return tx.Model(&current).Updates(updates).Error
+	})
+}
+
+func (s *authServiceImpl) resetLoginFailures(user *models.User) error {
+	if user.FailedAttempts == 0 && user.LockedUntil == nil {
+		return nil
+	}
+	return s.db.Model(user).Updates(map[string]interface{}{
+		"failed_attempts": 0,
+		"locked_until":    nil,
+	}).Error
+}
@@ -14,8 +14,26 @@ var (
 	ErrUserInactive            = errors.New("user is inactive")
 	ErrInvalidLoginMethod      = errors.New("invalid login method, use OAuth provider")
 	ErrGoogleUserNotRegistered = errors.New("google user not registered")
+	ErrGoogleTokenRequired     = errors.New("google id token is required")
+	ErrRefreshTokenInvalid     = errors.New("refresh token is invalid")
+	ErrRefreshTokenExpired     = errors.New("refresh token has expired")
+	ErrRefreshTokenReused      = errors.New("refresh token reuse detected, session revoked")
+	ErrMFAInvalidCode          = errors.New("invalid two-factor code")
+	ErrMFANotEnabled           = errors.New("two-factor authentication is not enabled")
+	ErrMFAAlreadyEnabled       = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnrolled          = errors.New("two-factor enrollment has not been started")
+	ErrMFARequiredByPolicy     = errors.New("two-factor authentication is required for this role")
+	ErrMFATokenInvalid         = errors.New("two-factor challenge is invalid or expired")
+	ErrTooManyAttempts         = errors.New("too many login attempts, try again later")
+	ErrAccountLocked           = errors.New("account temporarily locked after repeated failed attempts")
+	ErrPasswordUnchanged       = errors.New("the new password must be different from the current one")
+	ErrInvitationInvalid       = errors.New("invitation is invalid, expired or already used")
+	ErrSessionRevoked          = errors.New("session expired or revoked")
 	ErrInvalidRequestBody      = errors.New("invalid request body")
 	ErrUserNotFound            = errors.New("user not found")
+	ErrInvalidRole             = errors.New("rol inválido")
+	ErrUserSelfDeactivate      = errors.New("no puedes desactivar tu propio usuario")
+	ErrLastSuperAdmin          = errors.New("debe quedar al menos un SUPERADMIN activo")
 
 	ErrSignupEmailRequired            = errors.New("email is required")
 	ErrSignupEmailInvalid             = errors.New("email is invalid")
@@ -26,11 +44,63 @@ var (
 
 	ErrVoteNotFound       = errors.New("voto no encontrado")
 	ErrUnauthorizedAction = errors.New("no tienes permisos para esta acción")
+	ErrPartyRequired      = errors.New("el personero no tiene partido asignado")
 	ErrPositionNotFound   = errors.New("position not found")
 
-	ErrMesaNotFound     = errors.New("mesa no encontrada")
+	ErrElectionNotFound      = errors.New("elección no encontrada")
+	ErrElectionNameTaken     = errors.New("ya existe una elección con ese nombre")
+	ErrElectionInUse         = errors.New("la elección tiene posiciones o mesas registradas")
+	ErrInvalidElectionStatus = errors.New("estado de elección inválido")
+	ErrElectionTransition    = errors.New("transición de estado de elección no permitida")
+	ErrElectionPrecondition  = errors.New("la elección no cumple las condiciones para cambiar de estado")
+	ErrElectionLocked        = errors.New("la elección no admite cambios en su estado actual")
+	ErrElectionMismatch      = errors.New("la mesa y el candidato pertenecen a elecciones distintas")
+
+	ErrCertificationRequired    = errors.New("la elección se certifica con POST /elections/:id/certify, que firma sus resultados")
+	ErrCertificationUnavailable = errors.New("no hay clave de firma de certificación configurada")
+	ErrCertificateNotFound      = errors.New("la elección no tiene resultados certificados")
+
+	ErrMesaNotFound          = errors.New("mesa no encontrada")
+	ErrMesaNotOpen           = errors.New("la mesa no está abierta")
+	ErrMesaNumberTaken       = errors.New("ya existe una mesa con ese número")
+	ErrMesaHasVotes          = errors.New("la mesa tiene votos registrados")
+	ErrInvalidMesaStatus     = errors.New("estado de mesa inválido: debe ser PENDING, OPEN, CLOSED, COUNTED o ANNULLED")
+	ErrInvalidMesaTransition = errors.New("transición de estado de mesa no permitida")
+
+	ErrActaAlreadySubmitted = errors.New("ya se registró el acta de esta mesa para este tipo de voto")
+	ErrActaIncomplete       = errors.New("el acta está incompleta")
+	ErrActaInvalid          = errors.New("el acta es inválida")
+	ErrActaExceedsElectors  = errors.New("el acta supera el número de electores de la mesa")
+	ErrActaMismatch         = errors.New("el acta no coincide con la primera digitación en cédulas o votos en blanco")
+
 	ErrDuplicateVote    = errors.New("ya existe un voto para este candidato en esta mesa")
 	ErrInvalidVoteCount = errors.New("el número de votos no puede ser negativo")
+	ErrReasonRequired   = errors.New("el motivo es obligatorio")
+	ErrVoteAnnulled     = errors.New("el voto ya fue anulado")
+	ErrVoteUnchanged    = errors.New("el nuevo valor es igual al actual")
+
+	ErrCaptureAlreadySubmitted = errors.New("ya registraste una captura para este candidato en esta mesa")
+	ErrDiscrepancyPending      = errors.New("existe una discrepancia pendiente para este candidato en esta mesa")
+	ErrDiscrepancyNotFound     = errors.New("discrepancia no encontrada")
+	ErrDiscrepancyResolved     = errors.New("la discrepancia ya fue resuelta")
+
+	ErrWebhookNotFound         = errors.New("webhook no encontrado")
+	ErrWebhookDeliveryNotFound = errors.New("entrega de webhook no encontrada")
+	ErrWebhookDeliveryNotDead  = errors.New("solo se pueden reintentar entregas descartadas")
+	ErrInvalidWebhookURL       = errors.New("la URL del webhook debe ser http o https")
+	ErrWebhookTargetBlocked    = errors.New("el webhook no puede apuntar a una dirección interna")
+	ErrInvalidWebhookEvent     = errors.New("evento de webhook desconocido")
+
+	ErrMesaLocked       = errors.New("la mesa está siendo editada por otro digitador")
+	ErrMesaLockRequired = errors.New("debes tener el bloqueo de edición de la mesa para registrar votos")
+
+	ErrInvalidExportFormat = errors.New("formato de exportación inválido: debe ser csv o xlsx")
+
+	ErrImportFileType    = errors.New("el archivo debe ser .csv o .xlsx")
+	ErrImportPhotosType  = errors.New("las fotos deben enviarse en un archivo .zip")
+	ErrImportEmpty       = errors.New("el archivo no tiene filas para importar")
+	ErrImportTooManyRows = errors.New("el archivo supera el máximo de filas por importación")
+	ErrImportHeader      = errors.New("encabezado inválido")
 
 	ErrUnauthorized      = errors.New("solo ADMIN puede realizar esta acción")
 	ErrCandidateNotFound = errors.New("candidato no encontrado")
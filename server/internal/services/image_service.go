@@ -25,9 +25,9 @@ const (
 )
 
 type ImageService interface {
-	SaveImage(file *multipart.FileHeader) (*models.Image, error)
+	SaveImage(file *multipart.FileHeader, userID, userRole, ip string) (*models.Image, error)
 	GetByID(id string) (*models.Image, error)
-	Delete(id string) error
+	Delete(id, userID, userRole, ip string) error
 }
 
 type imageServiceImpl struct {
@@ -38,13 +38,30 @@ func NewImageService(db *gorm.DB) ImageService {
 	return &imageServiceImpl{db: db}
 }
 
-func (s *imageServiceImpl) SaveImage(file *multipart.FileHeader) (*models.Image, error) {
+// SaveImage: registra la imagen; el handler guarda el archivo en UploadFolder
+// con el Filename devuelto
+func (s *imageServiceImpl) SaveImage(file *multipart.FileHeader, userID, userRole, ip string) (*models.Image, error) {
+	var image *models.Image
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		image, err = createImage(tx, file.Filename, file.Size, AuditActor{userID, userRole, ip})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	if file.Size > MaxImageSize {
+	return image, nil
+}
+
+// createImage: valida tamaño y extensión y crea el registro con un nombre de
+// archivo único dentro de tx; no escribe el archivo
+func createImage(tx *gorm.DB, originalName string, size int64, actor AuditActor) (*models.Image, error) {
+	if size > MaxImageSize {
 		return nil, ErrImageTooLarge
 	}
 
-	ext := strings.ToLower(filepath.Ext(file.Filename))
+	ext := strings.ToLower(filepath.Ext(originalName))
 	allowedExts := map[string]bool{
 		".jpg":  true,
 		".jpeg": true,
@@ -61,30 +78,21 @@ func (s *imageServiceImpl) SaveImage(file *multipart.FileHeader) (*models.Image,
 		return nil, fmt.Errorf("error al crear carpeta uploads: %w", err)
 	}
 
-	src, err := file.Open()
-	if err != nil {
-		return nil, fmt.Errorf("error al abrir archivo: %w", err)
-	}
-	defer src.Close()
-
-	data := make([]byte, file.Size)
-	if _, err := src.Read(data); err != nil {
-		return nil, fmt.Errorf("error al leer archivo: %w", err)
-	}
-
 	timestamp := time.Now().UnixNano()
 	filename := fmt.Sprintf("img_%d%s", timestamp, ext)
 
 	image := models.Image{
 		Filename: filename,
-		Name:     file.Filename,
+		Name:     originalName,
 		URL:      fmt.Sprintf("/uploads/%s", filename),
 	}
 
-	if err := s.db.Create(&image).Error; err != nil {
+	if err := tx.Create(&image).Error; err != nil {
 		return nil, fmt.Errorf("error al guardar registro de imagen: %w", err)
 	}
-
+	if err := auditCreated(tx, actor, "image.create", &models.Image{}, AuditImage, image.ID); err != nil {
+		return nil, err
+	}
 	return &image, nil
 }
 
@@ -99,23 +107,35 @@ func (s *imageServiceImpl) GetByID(id string) (*models.Image, error) {
 	return &image, nil
 }
 
-func (s *imageServiceImpl) Delete(id string) error {
-	var image models.Image
-	if err := s.db.First(&image, "id = ?", id).Error; err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return ErrImageNotFound
+// Delete: el archivo se elimina al final de la transacción, así un fallo al
+// borrarlo deshace también el registro y su evento de auditoría
+func (s *imageServiceImpl) Delete(id, userID, userRole, ip string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var image models.Image
+		if err := tx.First(&image, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrImageNotFound
+			}
+			return err
 		}
-		return err
-	}
 
-	filepath := filepath.Join(UploadFolder, image.Filename)
-	if err := os.Remove(filepath); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("error al eliminar archivo: %w", err)
-	}
+		before, err := auditSnapshot(tx, &models.Image{}, image.ID)
+		if err != nil {
+			return err
+		}
 
-	if err := s.db.Delete(&image).Error; err != nil {
-		return err
-	}
+		if err := tx.Delete(&image).Error; err != nil {
+			return err
+		}
 
-	return nil
+		if err := recordAudit(tx, AuditActor{userID, userRole, ip}, "image.delete", AuditImage, image.ID, before, nil); err != nil {
+			return err
+		}
+
+		filepath := filepath.Join(UploadFolder, image.Filename)
+		if err := os.Remove(filepath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error al eliminar archivo: %w", err)
+		}
+		return nil
+	})
 }
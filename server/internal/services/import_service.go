@@ -0,0 +1,607 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"os"
+	"path"
+	"path/filepath"
+	"server/internal/dto"
+	"server/internal/models"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	importMaxRows   = 2000
+	importSavepoint = "import_row"
+)
+
+// Columnas de cada hoja; el encabezado no distingue mayúsculas, espacios ni guiones
+var (
+	positionImportColumns = importColumns{
+		required: []string{"name", "typePosition", "totalVotes", "validPercentage"},
+		optional: []string{"description"},
+	}
+	candidateImportColumns = importColumns{
+		required: []string{"name"},
+		optional: []string{"description", "typeCandidate", "party", "position", "isActive", "photo"},
+	}
+)
+
+// errImportRollback: deshace la transacción de un dryRun o de una importación con errores
+var errImportRollback = errors.New("importación deshecha")
+
+type ImportService interface {
+	Positions(electionID string, file *multipart.FileHeader, dryRun bool, userID, userRole, ip string) (*dto.ImportResult, error)
+	Candidates(electionID string, file, photos *multipart.FileHeader, dryRun bool, userID, userRole, ip string) (*dto.ImportResult, error)
+}
+
+type importServiceImpl struct {
+	db *gorm.DB
+}
+
+// NewImportService: cada fila se aplica con las mismas reglas que
+// PositionService.Create y CandidateService.Create, todas en una transacción
+func NewImportService(db *gorm.DB) ImportService {
+	return &importServiceImpl{db: db}
+}
+
+// Positions: columnas name, typePosition, totalVotes, validPercentage y
+// description; validPercentage admite 0.5 o 50%
+func (s *importServiceImpl) Positions(electionID string, file *multipart.FileHeader, dryRun bool, userID, userRole, ip string) (*dto.ImportResult, error) {
+	if err := s.ensureElection(electionID); err != nil {
+		return nil, err
+	}
+
+	sheet, err := readImportSheet(file, positionImportColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	actor := AuditActor{userID, userRole, ip}
+	return s.run(electionID, sheet, dryRun, func(tx *gorm.DB, row importRow) (dto.ImportItem, error) {
+		totalVotes, err := parseImportInt(sheet.get(row, "totalVotes"))
+		if err != nil {
+			return dto.ImportItem{}, &importCellError{"totalVotes", err}
+		}
+		validPercentage, err := parseImportPercentage(sheet.get(row, "validPercentage"))
+		if err != nil {
+			return dto.ImportItem{}, &importCellError{"validPercentage", err}
+		}
+
+		position, err := createPosition(tx, dto.CreatePositionRequest{
+			ElectionID:      electionID,
+			Name:            sheet.get(row, "name"),
+			Description:     optionalImportValue(sheet.get(row, "description")),
+			TypePosition:    strings.ToUpper(sheet.get(row, "typePosition")),
+			TotalVotes:      totalVotes,
+			ValidPercentage: validPercentage,
+		}, actor)
+		if err != nil {
+			return dto.ImportItem{}, err
+		}
+		return dto.ImportItem{ID: position.ID, Name: position.Name}, nil
+	}, nil)
+}
+
+// importPhoto: foto del ZIP pendiente de escribirse en UploadFolder
+type importPhoto struct {
+	entry    *zip.File
+	filename string
+}
+
+// Candidates: columnas name, description, typeCandidate, party, position
+// (nombre o ID de una posición de la elección), isActive y photo (nombre de
+// archivo dentro del ZIP de fotos). Las fotos se escriben solo si todas las
+// filas son válidas, justo antes de confirmar la transacción.
+func (s *importServiceImpl) Candidates(electionID string, file, photos *multipart.FileHeader, dryRun bool, userID, userRole, ip string) (*dto.ImportResult, error) {
+	if err := s.ensureElection(electionID); err != nil {
+		return nil, err
+	}
+
+	sheet, err := readImportSheet(file, candidateImportColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	var album map[string]*zip.File
+	if photos != nil {
+		src, err := openImportPhotos(photos)
+		if err != nil {
+			return nil, err
+		}
+		defer src.Close()
+		album = src.entries
+	}
+
+	var positions []models.Position
+	if err := s.db.Select("id", "name").Where("election_id = ?", electionID).Find(&positions).Error; err != nil {
+		return nil, err
+	}
+
+	actor := AuditActor{userID, userRole, ip}
+	var pending []importPhoto
+	var written []string
+
+	res, err := s.run(electionID, sheet, dryRun, func(tx *gorm.DB, row importRow) (dto.ImportItem, error) {
+		positionID, err := resolveImportPosition(positions, sheet.get(row, "position"))
+		if err != nil {
+			return dto.ImportItem{}, &importCellError{"position", err}
+		}
+		isActive, err := parseImportBool(sheet.get(row, "isActive"))
+		if err != nil {
+			return dto.ImportItem{}, &importCellError{"isActive", err}
+		}
+
+		typeCandidate := models.TypeCandidates(strings.ToUpper(sheet.get(row, "typeCandidate")))
+		if typeCandidate == "" {
+			typeCandidate = models.TCcandidate
+		}
+
+		req := dto.CreateCandidateRequest{
+			Name:          sheet.get(row, "name"),
+			Description:   optionalImportValue(sheet.get(row, "description")),
+			IsActive:      isActive,
+			PositionID:    positionID,
+			TypeCandidate: typeCandidate,
+			Party:         optionalImportValue(sheet.get(row, "party")),
+		}
+
+		var photo *importPhoto
+		if name := sheet.get(row, "photo"); name != "" {
+			entry, err := findImportPhoto(album, name)
+			if err != nil {
+				return dto.ImportItem{}, &importCellError{"photo", err}
+			}
+			image, err := createImage(tx, path.Base(entry.Name), int64(entry.UncompressedSize64), actor)
+			if err != nil {
+				return dto.ImportItem{}, &importCellError{"photo", err}
+			}
+			req.ImageID = image.ID
+			photo = &importPhoto{entry: entry, filename: image.Filename}
+		}
+
+		candidate, err := createCandidate(tx, req, actor)
+		if err != nil {
+			return dto.ImportItem{}, err
+		}
+		if photo != nil {
+			pending = append(pending, *photo)
+		}
+		return dto.ImportItem{ID: candidate.ID, Name: candidate.Name}, nil
+	}, func(tx *gorm.DB) error {
+		for _, p := range pending {
+			target := filepath.Join(UploadFolder, p.filename)
+			written = append(written, target)
+			if err := extractImportPhoto(p.entry, target); err != nil {
+				return fmt.Errorf("error al guardar la foto %s: %w", path.Base(p.entry.Name), err)
+			}
+		}
+		return nil
+	})
+
+	// si la transacción no se confirmó, las fotos ya escritas sobran
+	if err != nil || !res.Applied {
+		for _, target := range written {
+			os.Remove(target)
+		}
+	}
+	return res, err
+}
+
+func (s *importServiceImpl) ensureElection(electionID string) error {
+	if electionID == "" {
+		return fmt.Errorf("elección obligatoria")
+	}
+	return ensureElectionPhase(s.db, electionID, electionSetupPhases)
+}
+
+// run: aplica cada fila en su propio savepoint dentro de una sola transacción
+// para reunir todos los errores. Con dryRun o con algún error se deshace
+// entera; si no, beforeCommit (opcional) es lo último antes de confirmarla.
+// La fase de la elección se vuelve a verificar con la fila bloqueada.
+func (s *importServiceImpl) run(electionID string, sheet *importSheet, dryRun bool, apply func(tx *gorm.DB, row importRow) (dto.ImportItem, error), beforeCommit func(tx *gorm.DB) error) (*dto.ImportResult, error) {
+	res := &dto.ImportResult{
+		DryRun:    dryRun,
+		TotalRows: len(sheet.rows),
+		Items:     []dto.ImportItem{},
+		Errors:    []dto.ImportRowError{},
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := ensureElectionPhase(tx, electionID, electionSetupPhases); err != nil {
+			return err
+		}
+
+		for _, row := range sheet.rows {
+			if err := tx.SavePoint(importSavepoint).Error; err != nil {
+				return err
+			}
+
+			item, err := apply(tx, row)
+			if err != nil {
+				if err := tx.RollbackTo(importSavepoint).Error; err != nil {
+					return err
+				}
+				res.Errors = append(res.Errors, newImportRowError(row.line, err))
+				continue
+			}
+
+			item.Row = row.line
+			res.Items = append(res.Items, item)
+		}
+
+		if dryRun || len(res.Errors) > 0 {
+			return errImportRollback
+		}
+		if beforeCommit != nil {
+			return beforeCommit(tx)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errImportRollback) {
+		return nil, err
+	}
+
+	res.Valid = len(res.Items)
+	res.Applied = err == nil
+	if !res.Applied {
+		// los IDs eran de una transacción deshecha
+		for i := range res.Items {
+			res.Items[i].ID = ""
+		}
+	}
+	return res, nil
+}
+
+// importCellError: error atribuible a una columna concreta de la fila
+type importCellError struct {
+	column string
+	err    error
+}
+
+func (e *importCellError) Error() string {
+	return e.err.Error()
+}
+
+func (e *importCellError) Unwrap() error {
+	return e.err
+}
+
+func newImportRowError(line int, err error) dto.ImportRowError {
+	rowErr := dto.ImportRowError{Row: line, Message: err.Error()}
+	var cellErr *importCellError
+	if errors.As(err, &cellErr) {
+		rowErr.Column = cellErr.column
+	}
+	return rowErr
+}
+
+type importColumns struct {
+	required []string
+	optional []string
+}
+
+// importRow: valores de una fila y su número en la hoja
+type importRow struct {
+	line   int
+	values []string
+}
+
+type importSheet struct {
+	columns map[string]int
+	rows    []importRow
+}
+
+// get: valor sin espacios de la columna; "" si la hoja no la tiene o la fila es corta
+func (t *importSheet) get(row importRow, column string) string {
+	i, ok := t.columns[column]
+	if !ok || i >= len(row.values) {
+		return ""
+	}
+	return strings.TrimSpace(row.values[i])
+}
+
+// readImportSheet: lee un CSV (separado por comas o por punto y coma) o la
+// primera hoja de un XLSX, valida el encabezado y omite las filas vacías
+func readImportSheet(file *multipart.FileHeader, columns importColumns) (*importSheet, error) {
+	if file == nil {
+		return nil, ErrImportFileType
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("error al abrir archivo: %w", err)
+	}
+	defer src.Close()
+
+	var records [][]string
+	switch strings.ToLower(filepath.Ext(file.Filename)) {
+	case ".csv":
+		records, err = readImportCSV(src)
+	case ".xlsx":
+		records, err = readImportXLSX(src)
+	default:
+		return nil, ErrImportFileType
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, ErrImportEmpty
+	}
+
+	sheet := &importSheet{columns: map[string]int{}}
+	if err := sheet.parseHeader(records[0], columns); err != nil {
+		return nil, err
+	}
+
+	for i, values := range records[1:] {
+		if isBlankImportRow(values) {
+			continue
+		}
+		sheet.rows = append(sheet.rows, importRow{line: i + 2, values: values})
+	}
+
+	if len(sheet.rows) == 0 {
+		return nil, ErrImportEmpty
+	}
+	if len(sheet.rows) > importMaxRows {
+		return nil, fmt.Errorf("%w (%d)", ErrImportTooManyRows, importMaxRows)
+	}
+	return sheet, nil
+}
+
+func (t *importSheet) parseHeader(header []string, columns importColumns) error {
+	known := map[string]string{}
+	for _, c := range append(columns.required, columns.optional...) {
+		known[normalizeImportHeader(c)] = c
+	}
+
+	for i, h := range header {
+		key := normalizeImportHeader(h)
+		if key == "" {
+			continue
+		}
+		column, ok := known[key]
+		if !ok {
+			return fmt.Errorf("%w: columna desconocida %q", ErrImportHeader, strings.TrimSpace(h))
+		}
+		if _, dup := t.columns[column]; dup {
+			return fmt.Errorf("%w: columna repetida %q", ErrImportHeader, column)
+		}
+		t.columns[column] = i
+	}
+
+	for _, c := range columns.required {
+		if _, ok := t.columns[c]; !ok {
+			return fmt.Errorf("%w: falta la columna %q", ErrImportHeader, c)
+		}
+	}
+	return nil
+}
+
+func normalizeImportHeader(h string) string {
+	h = strings.TrimPrefix(strings.TrimSpace(h), "\ufeff")
+	return strings.ToLower(strings.NewReplacer(" ", "", "_", "", "-", "").Replace(h))
+}
+
+func isBlankImportRow(values []string) bool {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func readImportCSV(src io.Reader) ([][]string, error) {
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return nil, fmt.Errorf("error al leer archivo: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	// Excel en español exporta CSV separados por punto y coma
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		r.Comma = ';'
+	}
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("CSV inválido: %w", err)
+	}
+	return records, nil
+}
+
+func readImportXLSX(src io.Reader) ([][]string, error) {
+	f, err := excelize.OpenReader(src)
+	if err != nil {
+		return nil, fmt.Errorf("XLSX inválido: %w", err)
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, ErrImportEmpty
+	}
+	rows, err := f.GetRows(sheets[0])
+	if err != nil {
+		return nil, fmt.Errorf("XLSX inválido: %w", err)
+	}
+	return rows, nil
+}
+
+// importPhotos: ZIP abierto con sus fotos indexadas por nombre de archivo en
+// minúsculas; nil marca un nombre repetido en distintas carpetas
+type importPhotos struct {
+	src     multipart.File
+	entries map[string]*zip.File
+}
+
+func (p *importPhotos) Close() error {
+	return p.src.Close()
+}
+
+func openImportPhotos(file *multipart.FileHeader) (*importPhotos, error) {
+	if strings.ToLower(filepath.Ext(file.Filename)) != ".zip" {
+		return nil, ErrImportPhotosType
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("error al abrir archivo: %w", err)
+	}
+	zr, err := zip.NewReader(src, file.Size)
+	if err != nil {
+		src.Close()
+		return nil, fmt.Errorf("ZIP de fotos inválido: %w", err)
+	}
+
+	entries := map[string]*zip.File{}
+	for _, f := range zr.File {
+		base := path.Base(f.Name)
+		if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") || strings.HasPrefix(base, ".") {
+			continue
+		}
+		key := strings.ToLower(base)
+		if _, dup := entries[key]; dup {
+			entries[key] = nil
+			continue
+		}
+		entries[key] = f
+	}
+	return &importPhotos{src: src, entries: entries}, nil
+}
+
+func findImportPhoto(album map[string]*zip.File, name string) (*zip.File, error) {
+	if album == nil {
+		return nil, fmt.Errorf("la foto %q requiere el ZIP de fotos", name)
+	}
+	entry, ok := album[strings.ToLower(path.Base(name))]
+	if !ok {
+		return nil, fmt.Errorf("la foto %q no está en el ZIP", name)
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("la foto %q aparece más de una vez en el ZIP", name)
+	}
+	return entry, nil
+}
+
+// extractImportPhoto: copia la foto al destino sin pasar de MaxImageSize,
+// aunque el tamaño declarado en el ZIP sea falso
+func extractImportPhoto(entry *zip.File, target string) error {
+	src, err := entry.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(target)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(dst, io.LimitReader(src, MaxImageSize+1))
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if n > MaxImageSize {
+		return ErrImageTooLarge
+	}
+	return nil
+}
+
+// resolveImportPosition: la columna position admite el ID o el nombre de una
+// posición de la elección; vacía deja al candidato sin posición
+func resolveImportPosition(positions []models.Position, value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	var matches []string
+	for _, p := range positions {
+		if p.ID == value {
+			return p.ID, nil
+		}
+		if strings.EqualFold(strings.TrimSpace(p.Name), value) {
+			matches = append(matches, p.ID)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("posición %q no encontrada en la elección", value)
+	case 1:
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("hay varias posiciones llamadas %q, usa su ID", value)
+}
+
+func optionalImportValue(v string) *string {
+	if v == "" {
+		return nil
+	}
+	return &v
+}
+
+func parseImportInt(v string) (int, error) {
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%q no es un número entero", v)
+	}
+	return n, nil
+}
+
+// parseImportPercentage: fracción (0.5 o 0,5) o porcentaje (50%), que es como
+// Excel muestra las celdas con formato de porcentaje
+func parseImportPercentage(v string) (float64, error) {
+	if v == "" {
+		return 0, nil
+	}
+
+	raw := strings.ReplaceAll(v, ",", ".")
+	percent := strings.HasSuffix(raw, "%")
+	raw = strings.TrimSpace(strings.TrimSuffix(raw, "%"))
+
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q no es un porcentaje válido", v)
+	}
+	if percent {
+		f /= 100
+	}
+	return f, nil
+}
+
+func parseImportBool(v string) (*bool, error) {
+	var b bool
+	switch strings.ToLower(v) {
+	case "":
+		return nil, nil
+	case "true", "si", "sí", "1", "x":
+		b = true
+	case "false", "no", "0":
+		b = false
+	default:
+		return nil, fmt.Errorf("%q no es un valor válido, usa SI o NO", v)
+	}
+	return &b, nil
+}
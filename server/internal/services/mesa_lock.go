@@ -0,0 +1,265 @@
+package services
+
+import (
+	"crypto/subtle"
+	"server/internal/dto"
+	"server/pkgs/security"
+	"sort"
+	"sync"
+	"time"
+)
+
+// mesaLockClientBuffer: mensajes pendientes por cliente antes de descartar
+const mesaLockClientBuffer = 16
+
+// MesaLockChecker: lo usan VoteService y ActaService para exigir el bloqueo
+// antes de escribir
+type MesaLockChecker interface {
+	Check(mesaID, userID, token string) error
+}
+
+type mesaLock struct {
+	clientID  string
+	token     string
+	expiresAt time.Time
+}
+
+type mesaLockClient struct {
+	id     string
+	userID string
+	email  string
+	role   string
+	mesaID string
+	send   chan dto.MesaWSMessage
+}
+
+// MesaLockManager concede a un solo cliente WebSocket a la vez el bloqueo de
+// edición de cada mesa. El bloqueo caduca si no se renueva dentro del TTL y
+// se libera al desconectarse el cliente; cada cambio se difunde como presencia
+// a todos los conectados. El estado vive en memoria: un reinicio libera todo.
+type MesaLockManager struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	locks   map[string]*mesaLock
+	clients map[string]*mesaLockClient
+}
+
+func NewMesaLockManager(ttl time.Duration) *MesaLockManager {
+	m := &MesaLockManager{
+		ttl:     ttl,
+		locks:   make(map[string]*mesaLock),
+		clients: make(map[string]*mesaLockClient),
+	}
+	go m.sweep()
+	return m
+}
+
+// Connect registra un cliente y devuelve su id y el canal de mensajes salientes
+func (m *MesaLockManager) Connect(userID, email, role string) (string, <-chan dto.MesaWSMessage) {
+	client := &mesaLockClient{
+		id:     security.GenerateRandomToken(8),
+		userID: userID,
+		email:  email,
+		role:   role,
+		send:   make(chan dto.MesaWSMessage, mesaLockClientBuffer),
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.clients[client.id] = client
+	m.push(client, dto.MesaWSMessage{Type: dto.MesaWSWelcome, ClientID: client.id})
+	m.broadcastPresence()
+	return client.id, client.send
+}
+
+// Disconnect libera el bloqueo del cliente y cierra su canal
+func (m *MesaLockManager) Disconnect(clientID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	client, ok := m.clients[clientID]
+	if !ok {
+		return
+	}
+	m.releaseMesa(client)
+	delete(m.clients, clientID)
+	close(client.send)
+	m.broadcastPresence()
+}
+
+// Acquire concede o renueva el bloqueo de la mesa para el cliente y suelta el
+// que tuviera sobre otra. Si lo tiene otro cliente el anunciado queda como
+// presencia y se devuelve ErrMesaLocked con el mensaje lock_denied.
+func (m *MesaLockManager) Acquire(clientID, mesaID string) (dto.MesaWSMessage, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	client, ok := m.clients[clientID]
+	if !ok {
+		return dto.MesaWSMessage{}, ErrMesaLockRequired
+	}
+	if client.mesaID != mesaID {
+		m.releaseMesa(client)
+		client.mesaID = mesaID
+	}
+
+	now := time.Now()
+	lock, held := m.locks[mesaID]
+	if held && lock.clientID != clientID && now.Before(lock.expiresAt) {
+		holder := m.presenceOf(m.clients[lock.clientID])
+		m.broadcastPresence()
+		return dto.MesaWSMessage{
+			Type:    dto.MesaWSLockDenied,
+			MesaID:  mesaID,
+			Holder:  &holder,
+			Message: ErrMesaLocked.Error(),
+		}, ErrMesaLocked
+	}
+
+	if !held || lock.clientID != clientID {
+		lock = &mesaLock{clientID: clientID, token: security.GenerateRandomToken(16)}
+		m.locks[mesaID] = lock
+	}
+	lock.expiresAt = now.Add(m.ttl)
+
+	m.broadcastPresence()
+	return dto.MesaWSMessage{
+		Type:      dto.MesaWSLockGranted,
+		MesaID:    mesaID,
+		Token:     lock.token,
+		ExpiresAt: lock.expiresAt.Format(time.RFC3339),
+	}, nil
+}
+
+// Release: el cliente deja la mesa y, si lo tenía, su bloqueo
+func (m *MesaLockManager) Release(clientID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	client, ok := m.clients[clientID]
+	if !ok || client.mesaID == "" {
+		return
+	}
+	m.releaseMesa(client)
+	client.mesaID = ""
+	m.broadcastPresence()
+}
+
+// Check: nil solo si token es el bloqueo vigente de la mesa y pertenece al usuario
+func (m *MesaLockManager) Check(mesaID, userID, token string) error {
+	if token == "" {
+		return ErrMesaLockRequired
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	lock, ok := m.locks[mesaID]
+	if !ok || !time.Now().Before(lock.expiresAt) {
+		return ErrMesaLockRequired
+	}
+	if subtle.ConstantTimeCompare([]byte(lock.token), []byte(token)) != 1 {
+		return ErrMesaLockRequired
+	}
+	if client, ok := m.clients[lock.clientID]; !ok || client.userID != userID {
+		return ErrMesaLockRequired
+	}
+	return nil
+}
+
+// Presence: clientes conectados ordenados por mesa
+func (m *MesaLockManager) Presence() []dto.MesaPresence {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.presence()
+}
+
+// sweep retira los bloqueos caducados y avisa a quien los tenía
+func (m *MesaLockManager) sweep() {
+	interval := m.ttl / 4
+	if interval < time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		m.mu.Lock()
+		changed := false
+		for mesaID, lock := range m.locks {
+			if now.Before(lock.expiresAt) {
+				continue
+			}
+			delete(m.locks, mesaID)
+			changed = true
+			if client, ok := m.clients[lock.clientID]; ok {
+				m.push(client, dto.MesaWSMessage{Type: dto.MesaWSLockExpired, MesaID: mesaID})
+			}
+		}
+		if changed {
+			m.broadcastPresence()
+		}
+		m.mu.Unlock()
+	}
+}
+
+// releaseMesa: suelta el bloqueo del cliente sobre su mesa actual; requiere mu
+func (m *MesaLockManager) releaseMesa(client *mesaLockClient) {
+	if client.mesaID == "" {
+		return
+	}
+	if lock, ok := m.locks[client.mesaID]; ok && lock.clientID == client.id {
+		delete(m.locks, client.mesaID)
+	}
+}
+
+func (m *MesaLockManager) presenceOf(client *mesaLockClient) dto.MesaPresence {
+	if client == nil {
+		return dto.MesaPresence{}
+	}
+	p := dto.MesaPresence{
+		ClientID: client.id,
+		UserID:   client.userID,
+		Email:    client.email,
+		Role:     client.role,
+		MesaID:   client.mesaID,
+	}
+	if lock, ok := m.locks[client.mesaID]; ok && lock.clientID == client.id {
+		p.Locked = true
+		p.ExpiresAt = lock.expiresAt.Format(time.RFC3339)
+	}
+	return p
+}
+
+func (m *MesaLockManager) presence() []dto.MesaPresence {
+	list := make([]dto.MesaPresence, 0, len(m.clients))
+	for _, client := range m.clients {
+		list = append(list, m.presenceOf(client))
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].MesaID != list[j].MesaID {
+			return list[i].MesaID < list[j].MesaID
+		}
+		return list[i].ClientID < list[j].ClientID
+	})
+	return list
+}
+
+// broadcastPresence: la presencia es el estado completo, así que a un cliente
+// lento le basta con el siguiente mensaje si pierde uno; requiere mu
+func (m *MesaLockManager) broadcastPresence() {
+	msg := dto.MesaWSMessage{Type: dto.MesaWSPresence, Clients: m.presence()}
+	for _, client := range m.clients {
+		m.push(client, msg)
+	}
+}
+
+// push no bloquea nunca con mu tomado; si el buffer está lleno se descarta
+func (m *MesaLockManager) push(client *mesaLockClient, msg dto.MesaWSMessage) {
+	select {
+	case client.send <- msg:
+	default:
+	}
+}
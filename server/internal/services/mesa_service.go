@@ -0,0 +1,304 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"server/internal/dto"
+	"server/internal/models"
+	"server/pkgs/logger"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// mesaTransitions: ciclo de vida permitido de una mesa
+// PENDING → OPEN → CLOSED → COUNTED; cualquier estado no final puede anularse.
+var mesaTransitions = map[models.MesaStatus][]models.MesaStatus{
+	models.MSpending: {models.MSopen, models.MSannulled},
+	models.MSopen:    {models.MSclosed, models.MSannulled},
+	models.MSclosed:  {models.MScounted, models.MSopen, models.MSannulled},
+	models.MScounted: {models.MSannulled},
+}
+
+type MesaService interface {
+	GetAll(electionID string) ([]dto.MesaResponse, error)
+	GetOne(id string) (*dto.MesaResponse, error)
+	Create(req dto.CreateMesaRequest, userID, userRole string) (*dto.MesaResponse, error)
+	Update(id string, req dto.UpdateMesaRequest, userID, userRole string) (*dto.MesaResponse, error)
+	Delete(id, userID, userRole string) error
+}
+
+type mesaServiceImpl struct {
+	db       *gorm.DB
+	notifier ResultNotifier
+}
+
+// NewMesaService: notifier recibe las posiciones de la elección cuando una
+// mesa cambia de estado, porque el porcentaje contado y las anuladas cambian
+func NewMesaService(db *gorm.DB, notifier ResultNotifier) MesaService {
+	return &mesaServiceImpl{db: db, notifier: notifier}
+}
+
+func mapMesaToResponse(m models.Mesa) dto.MesaResponse {
+	return dto.MesaResponse{
+		ID:               m.ID,
+		ElectionID:       m.ElectionID,
+		Number:           m.Number,
+		Location:         m.Location,
+		ElectorsDocentes: m.ElectorsDocentes,
+		ElectorsPublico:  m.ElectorsPublico,
+		Status:           string(m.Status),
+		CreatedAt:        m.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:        m.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func (s *mesaServiceImpl) GetAll(electionID string) ([]dto.MesaResponse, error) {
+	var mesas []models.Mesa
+	q := s.db.Order("number")
+	if electionID != "" {
+		q = q.Where("election_id = ?", electionID)
+	}
+	if err := q.Find(&mesas).Error; err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.MesaResponse, len(mesas))
+	for i, m := range mesas {
+		res[i] = mapMesaToResponse(m)
+	}
+	return res, nil
+}
+
+func (s *mesaServiceImpl) GetOne(id string) (*dto.MesaResponse, error) {
+	var mesa models.Mesa
+	if err := s.db.First(&mesa, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrMesaNotFound
+		}
+		return nil, err
+	}
+
+	res := mapMesaToResponse(mesa)
+	return &res, nil
+}
+
+func (s *mesaServiceImpl) Create(req dto.CreateMesaRequest, userID, userRole string) (*dto.MesaResponse, error) {
+	if req.ElectionID == "" {
+		return nil, fmt.Errorf("elección obligatoria")
+	}
+
+	number := strings.ToUpper(strings.TrimSpace(req.Number))
+	if number == "" {
+		return nil, fmt.Errorf("número de mesa obligatorio")
+	}
+
+	if req.ElectorsDocentes < 0 || req.ElectorsPublico < 0 {
+		return nil, fmt.Errorf("el número de electores no puede ser negativo")
+	}
+
+	mesa := models.Mesa{
+		ElectionID:       req.ElectionID,
+		Number:           number,
+		Location:         req.Location,
+		ElectorsDocentes: req.ElectorsDocentes,
+		ElectorsPublico:  req.ElectorsPublico,
+		Status:           models.MSpending,
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := ensureElectionPhase(tx, req.ElectionID, electionSetupPhases); err != nil {
+			return err
+		}
+		if err := ensureMesaNumberAvailable(tx, req.ElectionID, number, ""); err != nil {
+			return err
+		}
+		return tx.Create(&mesa).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := mapMesaToResponse(mesa)
+	return &res, nil
+}
+
+func (s *mesaServiceImpl) Update(id string, req dto.UpdateMesaRequest, userID, userRole string) (*dto.MesaResponse, error) {
+	var mesa models.Mesa
+	statusChanged := false
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&mesa, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrMesaNotFound
+			}
+			return err
+		}
+
+		if err := ensureElectionPhase(tx, mesa.ElectionID, electionMutablePhases); err != nil {
+			return err
+		}
+
+		if req.Number != nil {
+			number := strings.ToUpper(strings.TrimSpace(*req.Number))
+			if number == "" {
+				return fmt.Errorf("el número de mesa no puede estar vacío")
+			}
+			if err := ensureMesaNumberAvailable(tx, mesa.ElectionID, number, mesa.ID); err != nil {
+				return err
+			}
+			mesa.Number = number
+		}
+
+		if req.Location != nil {
+			mesa.Location = req.Location
+		}
+
+		// El padrón queda fijo al salir de CONFIGURED: los porcentajes de
+		// participación ya publicados dependen de él
+		electorsChanged := (req.ElectorsDocentes != nil && *req.ElectorsDocentes != mesa.ElectorsDocentes) ||
+			(req.ElectorsPublico != nil && *req.ElectorsPublico != mesa.ElectorsPublico)
+		if electorsChanged {
+			if err := ensureElectionPhase(tx, mesa.ElectionID, electionSetupPhases); err != nil {
+				return err
+			}
+		}
+
+		if req.ElectorsDocentes != nil {
+			if *req.ElectorsDocentes < 0 {
+				return fmt.Errorf("el número de electores no puede ser negativo")
+			}
+			mesa.ElectorsDocentes = *req.ElectorsDocentes
+		}
+
+		if req.ElectorsPublico != nil {
+			if *req.ElectorsPublico < 0 {
+				return fmt.Errorf("el número de electores no puede ser negativo")
+			}
+			mesa.ElectorsPublico = *req.ElectorsPublico
+		}
+
+		counted := false
+		if req.Status != nil {
+			next := models.MesaStatus(*req.Status)
+			if err := validateMesaTransition(mesa.Status, next); err != nil {
+				return err
+			}
+			if next == models.MSopen && mesa.Status != models.MSopen {
+				if err := ensureElectionPhase(tx, mesa.ElectionID, electionVotingPhases); err != nil {
+					return err
+				}
+			}
+			counted = next == models.MScounted && mesa.Status != models.MScounted
+			statusChanged = next != mesa.Status
+			mesa.Status = next
+		}
+
+		if err := tx.Save(&mesa).Error; err != nil {
+			return err
+		}
+		if !counted {
+			return nil
+		}
+		return enqueueWebhookEvent(tx, WebhookMesaCounted, map[string]interface{}{
+			"mesaId":     mesa.ID,
+			"electionId": mesa.ElectionID,
+			"number":     mesa.Number,
+			"status":     mesa.Status,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if statusChanged {
+		s.notifyElection(mesa.ElectionID)
+	}
+
+	res := mapMesaToResponse(mesa)
+	return &res, nil
+}
+
+// notifyElection: avisa a los suscriptores de resultados de todas las
+// posiciones de la elección; un error aquí no revierte el cambio ya confirmado
+func (s *mesaServiceImpl) notifyElection(electionID string) {
+	var positionIDs []string
+	if err := s.db.Model(&models.Position{}).Where("election_id = ?", electionID).Pluck("id", &positionIDs).Error; err != nil {
+		logger.Log.Errorf("❌ No se pudo notificar resultados de la elección %s: %v", electionID, err)
+		return
+	}
+	if len(positionIDs) > 0 {
+		s.notifier.VotesChanged(positionIDs...)
+	}
+}
+
+func (s *mesaServiceImpl) Delete(id, userID, userRole string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		var mesa models.Mesa
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "election_id").First(&mesa, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrMesaNotFound
+			}
+			return err
+		}
+
+		if err := ensureElectionPhase(tx, mesa.ElectionID, electionSetupPhases); err != nil {
+			return err
+		}
+
+		var votes int64
+		if err := tx.Model(&models.Vote{}).Where("mesa_id = ?", id).Count(&votes).Error; err != nil {
+			return err
+		}
+		if votes > 0 {
+			return ErrMesaHasVotes
+		}
+
+		result := tx.Delete(&models.Mesa{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrMesaNotFound
+		}
+		return nil
+	})
+}
+
+func ensureMesaNumberAvailable(db *gorm.DB, electionID, number, exceptID string) error {
+	q := db.Model(&models.Mesa{}).Where("election_id = ? AND number = ?", electionID, number)
+	if exceptID != "" {
+		q = q.Where("id <> ?", exceptID)
+	}
+
+	var count int64
+	if err := q.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrMesaNumberTaken
+	}
+	return nil
+}
+
+func validateMesaTransition(from, to models.MesaStatus) error {
+	switch to {
+	case models.MSpending, models.MSopen, models.MSclosed, models.MScounted, models.MSannulled:
+	default:
+		return ErrInvalidMesaStatus
+	}
+
+	if from == to {
+		return nil
+	}
+
+	for _, allowed := range mesaTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s → %s", ErrInvalidMesaTransition, from, to)
+}
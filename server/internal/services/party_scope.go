@@ -0,0 +1,42 @@
+package services
+
+import (
+	"errors"
+	"server/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// personeroParty: devuelve el partido al que se limitan las lecturas del
+// usuario. scoped es false para cualquier rol distinto de PERSONERO.
+func personeroParty(db *gorm.DB, userID, userRole string) (party string, scoped bool, err error) {
+	if models.Rol(userRole) != models.RolPersonero {
+		return "", false, nil
+	}
+
+	var user models.User
+	if err := db.Select("id", "party").First(&user, "id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", true, ErrUserNotFound
+		}
+		return "", true, err
+	}
+
+	if user.Party == nil || *user.Party == "" {
+		return "", true, ErrPartyRequired
+	}
+	return *user.Party, true, nil
+}
+
+// ensureCandidateParty: un PERSONERO solo puede ver candidatos de su partido
+func ensureCandidateParty(db *gorm.DB, candidate *models.Candidate, userID, userRole string) error {
+	party, scoped, err := personeroParty(db, userID, userRole)
+	if err != nil || !scoped {
+		return err
+	}
+
+	if candidate.Party == nil || *candidate.Party != party {
+		return ErrUnauthorizedAction
+	}
+	return nil
+}
@@ -11,10 +11,10 @@ import (
 )
 
 type PositionService interface {
-	GetAll() ([]dto.PositionResponse, error)
-	Create(req dto.CreatePositionRequest, userID, userRole string) (*dto.PositionResponse, error)
-	Update(id string, req dto.UpdatePositionRequest, userID, userRole string) (*dto.PositionResponse, error)
-	Delete(id, userID, userRole string) error
+	GetAll(electionID string) ([]dto.PositionResponse, error)
+	Create(req dto.CreatePositionRequest, userID, userRole, ip string) (*dto.PositionResponse, error)
+	Update(id string, req dto.UpdatePositionRequest, userID, userRole, ip string) (*dto.PositionResponse, error)
+	Delete(id, userID, userRole, ip string) error
 }
 
 type positionServiceImpl struct {
@@ -25,9 +25,13 @@ func NewPositionService(db *gorm.DB) PositionService {
 	return &positionServiceImpl{db: db}
 }
 
-func (s *positionServiceImpl) GetAll() ([]dto.PositionResponse, error) {
+func (s *positionServiceImpl) GetAll(electionID string) ([]dto.PositionResponse, error) {
 	var positions []models.Position
-	if err := s.db.Find(&positions).Error; err != nil {
+	q := s.db
+	if electionID != "" {
+		q = q.Where("election_id = ?", electionID)
+	}
+	if err := q.Find(&positions).Error; err != nil {
 		return nil, err
 	}
 
@@ -35,6 +39,7 @@ func (s *positionServiceImpl) GetAll() ([]dto.PositionResponse, error) {
 	for _, p := range positions {
 		result = append(result, dto.PositionResponse{
 			ID:              p.ID,
+			ElectionID:      p.ElectionID,
 			Name:            p.Name,
 			Description:     p.Description,
 			TypePosition:    string(p.TypePosition),
@@ -47,9 +52,39 @@ func (s *positionServiceImpl) GetAll() ([]dto.PositionResponse, error) {
 	return result, nil
 }
 
-func (s *positionServiceImpl) Create(req dto.CreatePositionRequest, userID, userRole string) (*dto.PositionResponse, error) {
-	if userRole != "ADMIN" {
-		return nil, ErrUnauthorizedAction
+func (s *positionServiceImpl) Create(req dto.CreatePositionRequest, userID, userRole, ip string) (*dto.PositionResponse, error) {
+	var position *models.Position
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		position, err = createPosition(tx, req, AuditActor{userID, userRole, ip})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.PositionResponse{
+		ID:              position.ID,
+		ElectionID:      position.ElectionID,
+		Name:            position.Name,
+		Description:     position.Description,
+		TypePosition:    string(position.TypePosition),
+		TotalVotes:      position.TotalVotes,
+		ValidPercentage: position.ValidPercentage,
+		CreatedAt:       position.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:       position.UpdatedAt.Format(time.RFC3339),
+	}, nil
+}
+
+// createPosition: reglas de alta de una posición dentro de tx; las comparten
+// Create y la importación masiva
+func createPosition(tx *gorm.DB, req dto.CreatePositionRequest, actor AuditActor) (*models.Position, error) {
+	if req.ElectionID == "" {
+		return nil, fmt.Errorf("elección obligatoria")
+	}
+
+	if err := ensureElectionPhase(tx, req.ElectionID, electionSetupPhases); err != nil {
+		return nil, err
 	}
 
 	if req.Name == "" {
@@ -86,6 +121,7 @@ func (s *positionServiceImpl) Create(req dto.CreatePositionRequest, userID, user
 	}
 
 	position := models.Position{
+		ElectionID:      req.ElectionID,
 		Name:            req.Name,
 		Description:     req.Description,
 		TypePosition:    models.TypePositions(req.TypePosition),
@@ -93,27 +129,16 @@ func (s *positionServiceImpl) Create(req dto.CreatePositionRequest, userID, user
 		ValidPercentage: req.ValidPercentage,
 	}
 
-	if err := s.db.Create(&position).Error; err != nil {
+	if err := tx.Create(&position).Error; err != nil {
 		return nil, err
 	}
-
-	return &dto.PositionResponse{
-		ID:              position.ID,
-		Name:            position.Name,
-		Description:     position.Description,
-		TypePosition:    string(position.TypePosition),
-		TotalVotes:      position.TotalVotes,
-		ValidPercentage: position.ValidPercentage,
-		CreatedAt:       position.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:       position.UpdatedAt.Format(time.RFC3339),
-	}, nil
-}
-
-func (s *positionServiceImpl) Update(id string, req dto.UpdatePositionRequest, userID, userRole string) (*dto.PositionResponse, error) {
-	if userRole != "ADMIN" {
-		return nil, ErrUnauthorizedAction
+	if err := auditCreated(tx, actor, "position.create", &models.Position{}, AuditPosition, position.ID); err != nil {
+		return nil, err
 	}
+	return &position, nil
+}
 
+func (s *positionServiceImpl) Update(id string, req dto.UpdatePositionRequest, userID, userRole, ip string) (*dto.PositionResponse, error) {
 	var position models.Position
 	if err := s.db.First(&position, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -168,12 +193,33 @@ func (s *positionServiceImpl) Update(id string, req dto.UpdatePositionRequest, u
 		position.ValidPercentage = *req.ValidPercentage
 	}
 
-	if err := s.db.Save(&position).Error; err != nil {
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := ensureElectionPhase(tx, position.ElectionID, electionSetupPhases); err != nil {
+			return err
+		}
+
+		before, err := auditSnapshot(tx, &models.Position{}, position.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Save(&position).Error; err != nil {
+			return err
+		}
+
+		after, err := auditSnapshot(tx, &models.Position{}, position.ID)
+		if err != nil {
+			return err
+		}
+		return recordAudit(tx, AuditActor{userID, userRole, ip}, "position.update", AuditPosition, position.ID, before, after)
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return &dto.PositionResponse{
 		ID:              position.ID,
+		ElectionID:      position.ElectionID,
 		Name:            position.Name,
 		Description:     position.Description,
 		TypePosition:    string(position.TypePosition),
@@ -184,14 +230,21 @@ func (s *positionServiceImpl) Update(id string, req dto.UpdatePositionRequest, u
 	}, nil
 }
 
-func (s *positionServiceImpl) Delete(id, userID, userRole string) error {
-	if userRole != "ADMIN" {
-		return ErrUnauthorizedAction
-	}
+func (s *positionServiceImpl) Delete(id, userID, userRole, ip string) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := ensurePositionPhase(tx, id, electionSetupPhases); err != nil {
+			return err
+		}
 
-	if err := s.db.Delete(&models.Position{}, "id = ?", id).Error; err != nil {
-		return ErrPositionNotFound
-	}
+		before, err := auditSnapshot(tx, &models.Position{}, id)
+		if err != nil {
+			return ErrPositionNotFound
+		}
+
+		if err := tx.Delete(&models.Position{}, "id = ?", id).Error; err != nil {
+			return ErrPositionNotFound
+		}
 
-	return nil
+		return recordAudit(tx, AuditActor{userID, userRole, ip}, "position.delete", AuditPosition, id, before, nil)
+	})
 }
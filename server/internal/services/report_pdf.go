@@ -0,0 +1,487 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"server/internal/dto"
+	"server/internal/models"
+	"server/pkgs/logger"
+	"strings"
+	"sync"
+
+	"github.com/go-pdf/fpdf"
+	xdraw "golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// Maquetación del acta (A4, milímetros)
+const (
+	reportMargin       = 15.0
+	reportFooterHeight = 18.0
+	reportPhotoSize    = 12.0
+	reportRowHeight    = 14.0
+	// reportPhotoPixels: lado máximo de la foto incrustada
+	reportPhotoPixels = 240
+	// reportPhotoMaxSource: píxeles máximos del original que se acepta decodificar
+	reportPhotoMaxSource = 24_000_000
+	// reportPhotoCacheSize: fotos ya convertidas que se guardan en memoria
+	reportPhotoCacheSize = 512
+)
+
+// reportPhotoCache: JPEG ya reducidos por ruta; la clave incluye tamaño y
+// fecha de modificación para que un archivo reemplazado se vuelva a convertir.
+// Al llenarse se vacía entera: las fotos de un acta se vuelven a generar rápido.
+var reportPhotoCache = struct {
+	sync.Mutex
+	entries map[string][]byte
+}{entries: make(map[string][]byte)}
+
+var reportVerdicts = map[string]string{
+	ValidityValid:          "ELECCIÓN VÁLIDA",
+	ValidityInvalidTurnout: "ELECCIÓN NO VÁLIDA POR PARTICIPACIÓN",
+	ValidityInvalidNulls:   "ELECCIÓN NO VÁLIDA POR VOTOS NULOS",
+}
+
+// resultsPDF: estado de la maquetación; tr convierte UTF-8 a cp1252, que es
+// lo que admiten las fuentes estándar del PDF
+type resultsPDF struct {
+	pdf    *fpdf.Fpdf
+	tr     func(string) string
+	data   *reportData
+	photos map[string]string
+}
+
+// renderResultsPDF: con summary añade una portada con el resumen de todas las
+// posiciones; cada posición va en su propia página con su bloque de firmas
+func renderResultsPDF(data *reportData, summary bool) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(reportMargin, reportMargin, reportMargin)
+	pdf.SetAutoPageBreak(true, reportFooterHeight+5)
+	pdf.AliasNbPages("")
+	// fechas fijas para que el mismo documento genere los mismos bytes
+	pdf.SetCreationDate(data.generated)
+	pdf.SetModificationDate(data.generated)
+	pdf.SetCatalogSort(true)
+	pdf.SetTitle("Acta de resultados - "+data.doc.ElectionName, true)
+
+	r := &resultsPDF{
+		pdf:    pdf,
+		tr:     pdf.UnicodeTranslatorFromDescriptor(""),
+		data:   data,
+		photos: make(map[string]string),
+	}
+	pdf.SetFooterFunc(r.footer)
+
+	if summary {
+		r.summaryPage()
+	}
+	for _, p := range data.doc.Positions {
+		r.positionPage(p)
+	}
+	if len(data.doc.Positions) == 0 && !summary {
+		pdf.AddPage()
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("no se pudo generar el PDF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (r *resultsPDF) header(title string) {
+	pdf := r.pdf
+	pdf.SetTextColor(30, 64, 175)
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(0, 8, r.tr(title), "", 1, "L", false, 0, "")
+
+	pdf.SetTextColor(71, 85, 105)
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(0, 6, r.tr(r.data.doc.ElectionName), "", 1, "L", false, 0, "")
+
+	pdf.SetDrawColor(30, 64, 175)
+	pdf.SetLineWidth(0.6)
+	pdf.Line(reportMargin, pdf.GetY()+1, 210-reportMargin, pdf.GetY()+1)
+	pdf.SetLineWidth(0.2)
+	pdf.Ln(5)
+}
+
+func (r *resultsPDF) infoRow(label, value string) {
+	pdf := r.pdf
+	pdf.SetTextColor(71, 85, 105)
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.CellFormat(50, 5.5, r.tr(label), "", 0, "L", false, 0, "")
+	pdf.SetTextColor(30, 41, 59)
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.CellFormat(0, 5.5, r.tr(value), "", 1, "L", false, 0, "")
+}
+
+func (r *resultsPDF) mesaProgress() string {
+	doc := r.data.doc
+	if doc.TotalMesas == 0 {
+		return "sin mesas registradas"
+	}
+	return fmt.Sprintf("%d de %d (%.2f%%)", doc.CountedMesas, doc.TotalMesas,
+		mesaProgress{counted: doc.CountedMesas, total: doc.TotalMesas}.percentage())
+}
+
+func (r *resultsPDF) summaryPage() {
+	pdf := r.pdf
+	pdf.AddPage()
+	r.header("ACTA GENERAL DE RESULTADOS")
+
+	r.infoRow("Estado de la elección", r.data.doc.ElectionStatus)
+	r.infoRow("Mesas escrutadas", r.mesaProgress())
+	r.infoRow("Posiciones", fmt.Sprint(len(r.data.doc.Positions)))
+	pdf.Ln(4)
+
+	widths := []float64{58, 24, 22, 42, 34}
+	r.tableHeader(widths, []string{"Posición", "Tipo", "Emitidos", "Ganador", "Validez"})
+
+	pdf.SetFont("Helvetica", "", 8.5)
+	for i, p := range r.data.doc.Positions {
+		fill := i%2 == 1
+		pdf.SetFillColor(248, 250, 252)
+		pdf.SetTextColor(51, 65, 85)
+		pdf.CellFormat(widths[0], 7, r.tr(truncate(p.PositionName, 38)), "B", 0, "L", fill, 0, "")
+		pdf.CellFormat(widths[1], 7, p.TypePosition, "B", 0, "C", fill, 0, "")
+		pdf.CellFormat(widths[2], 7, fmt.Sprint(p.EmittedVotes), "B", 0, "C", fill, 0, "")
+		pdf.CellFormat(widths[3], 7, r.tr(truncate(winnerLabel(p), 28)), "B", 0, "L", fill, 0, "")
+		if p.Validity.Status != ValidityValid {
+			pdf.SetTextColor(185, 28, 28)
+		}
+		pdf.CellFormat(widths[4], 7, r.tr(validityShort(p.Validity.Status)), "B", 1, "C", fill, 0, "")
+	}
+
+	r.signatures()
+}
+
+func (r *resultsPDF) positionPage(p dto.PositionResultResponse) {
+	pdf := r.pdf
+	pdf.AddPage()
+	r.header("ACTA DE RESULTADOS")
+
+	typeLabel := "ÓRGANO (DOCENTES + PÚBLICO)"
+	if p.TypePosition == string(models.TAposition) {
+		typeLabel = "AUTORIDAD (voto docente ponderado)"
+	}
+	r.infoRow("Posición", p.PositionName)
+	r.infoRow("Tipo", typeLabel)
+	r.infoRow("Electorado", fmt.Sprint(p.TotalVotes))
+	r.infoRow("Votos válidos requeridos", fmt.Sprintf("%.2f%% del electorado", p.ValidPercentage*100))
+	r.infoRow("Mesas escrutadas", r.mesaProgress())
+	if p.TypePosition == string(models.TAposition) {
+		r.infoRow("Factor de ponderación", fmt.Sprintf("%.2f (%.0f × público / docentes)", p.WeightFactor, AutoridadDocentesWeight))
+	}
+	pdf.Ln(4)
+
+	widths := []float64{16, 66, 22, 22, 28, 26}
+	r.tableHeader(widths, []string{"Foto", "Candidato", "Docentes", "Público", "Ponderado", "%"})
+
+	for i, c := range p.Candidates {
+		r.candidateRow(widths, c, i%2 == 1)
+	}
+	if len(p.Candidates) == 0 {
+		pdf.SetFont("Helvetica", "I", 9)
+		pdf.CellFormat(0, 8, r.tr("Sin candidatos registrados"), "B", 1, "C", false, 0, "")
+	}
+
+	// totales: los nulos no se desglosan por tipo de voto
+	var weighted float64
+	for _, c := range p.Candidates {
+		weighted += c.Votes
+	}
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.SetTextColor(30, 41, 59)
+	pdf.SetFillColor(219, 234, 254)
+	label := widths[0] + widths[1]
+	pdf.CellFormat(label, 7, r.tr("Votos válidos"), "B", 0, "R", true, 0, "")
+	pdf.CellFormat(widths[2], 7, fmt.Sprint(p.VotesDocentes), "B", 0, "C", true, 0, "")
+	pdf.CellFormat(widths[3], 7, fmt.Sprint(p.VotesPublico), "B", 0, "C", true, 0, "")
+	pdf.CellFormat(widths[4], 7, fmt.Sprintf("%.2f", round2(weighted)), "B", 0, "C", true, 0, "")
+	pdf.CellFormat(widths[5], 7, "", "B", 1, "C", true, 0, "")
+	pdf.CellFormat(label, 7, r.tr("Votos nulos"), "B", 0, "R", false, 0, "")
+	pdf.CellFormat(widths[2]+widths[3], 7, fmt.Sprint(p.NullVotes), "B", 0, "C", false, 0, "")
+	pdf.CellFormat(widths[4]+widths[5], 7, "", "B", 1, "C", false, 0, "")
+	pdf.CellFormat(label, 7, r.tr("Votos emitidos"), "B", 0, "R", false, 0, "")
+	pdf.CellFormat(widths[2]+widths[3], 7, fmt.Sprint(p.EmittedVotes), "B", 0, "C", false, 0, "")
+	pdf.CellFormat(widths[4]+widths[5], 7, "", "B", 1, "C", false, 0, "")
+	pdf.Ln(5)
+
+	r.verdict(p)
+	r.signatures()
+}
+
+func (r *resultsPDF) tableHeader(widths []float64, titles []string) {
+	pdf := r.pdf
+	pdf.SetFillColor(30, 64, 175)
+	pdf.SetTextColor(255, 255, 255)
+	pdf.SetFont("Helvetica", "B", 9)
+	for i, title := range titles {
+		ln := 0
+		if i == len(titles)-1 {
+			ln = 1
+		}
+		pdf.CellFormat(widths[i], 8, r.tr(title), "", ln, "C", true, 0, "")
+	}
+}
+
+func (r *resultsPDF) candidateRow(widths []float64, c dto.CandidateResult, alt bool) {
+	pdf := r.pdf
+	_, pageHeight := pdf.GetPageSize()
+	if pdf.GetY()+reportRowHeight > pageHeight-reportFooterHeight-5 {
+		pdf.AddPage()
+		r.tableHeader(widths, []string{"Foto", "Candidato", "Docentes", "Público", "Ponderado", "%"})
+	}
+
+	switch {
+	case c.IsWinner:
+		pdf.SetFillColor(220, 252, 231)
+	case alt:
+		pdf.SetFillColor(248, 250, 252)
+	default:
+		pdf.SetFillColor(255, 255, 255)
+	}
+	x, y := pdf.GetX(), pdf.GetY()
+
+	pdf.CellFormat(widths[0], reportRowHeight, "", "B", 0, "C", true, 0, "")
+	if name := r.photo(c.ID); name != "" {
+		offset := (reportRowHeight - reportPhotoSize) / 2
+		pdf.ImageOptions(name, x+(widths[0]-reportPhotoSize)/2, y+offset, reportPhotoSize, reportPhotoSize,
+			false, fpdf.ImageOptions{ImageType: "JPG"}, 0, "")
+	}
+
+	// nombre y, debajo, partido o la marca de ganador
+	detail := ""
+	if cand, ok := r.data.candidates[c.ID]; ok && cand.Party != nil {
+		detail = *cand.Party
+	}
+	if c.IsWinner {
+		detail = strings.TrimPrefix(detail+" · GANADOR", " · ")
+	}
+	pdf.SetXY(x+widths[0], y)
+	pdf.CellFormat(widths[1], reportRowHeight, "", "B", 0, "L", true, 0, "")
+	pdf.SetXY(x+widths[0]+1, y+2)
+	pdf.SetTextColor(30, 41, 59)
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.CellFormat(widths[1]-2, 5, r.tr(truncate(c.Name, 42)), "", 2, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 7.5)
+	pdf.SetTextColor(100, 116, 139)
+	pdf.CellFormat(widths[1]-2, 5, r.tr(truncate(detail, 52)), "", 0, "L", false, 0, "")
+
+	pdf.SetXY(x+widths[0]+widths[1], y)
+	pdf.SetTextColor(51, 65, 85)
+	pdf.SetFont("Helvetica", "", 9)
+	pdf.CellFormat(widths[2], reportRowHeight, fmt.Sprint(c.VotesDocentes), "B", 0, "C", true, 0, "")
+	pdf.CellFormat(widths[3], reportRowHeight, fmt.Sprint(c.VotesPublico), "B", 0, "C", true, 0, "")
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.CellFormat(widths[4], reportRowHeight, fmt.Sprintf("%.2f", c.Votes), "B", 0, "C", true, 0, "")
+	pdf.CellFormat(widths[5], reportRowHeight, fmt.Sprintf("%.2f%%", c.Percentage), "B", 1, "C", true, 0, "")
+}
+
+func (r *resultsPDF) verdict(p dto.PositionResultResponse) {
+	pdf := r.pdf
+	v := p.Validity
+
+	var detail string
+	switch v.Status {
+	case ValidityValid:
+		pdf.SetFillColor(220, 252, 231)
+		pdf.SetTextColor(21, 128, 61)
+		detail = fmt.Sprintf("Votos válidos %d de %d electores (%.2f%%), mínimo requerido %.2f%%; superan a los %d votos nulos.",
+			v.ValidVotes, v.Electorate, v.Turnout*100, v.RequiredPercentage*100, v.NullVotes)
+	case ValidityInvalidTurnout:
+		pdf.SetFillColor(254, 226, 226)
+		pdf.SetTextColor(185, 28, 28)
+		detail = fmt.Sprintf("Los votos válidos (%d de %d electores, %.2f%%) no alcanzan el mínimo requerido del %.2f%%.",
+			v.ValidVotes, v.Electorate, v.Turnout*100, v.RequiredPercentage*100)
+	default:
+		pdf.SetFillColor(254, 226, 226)
+		pdf.SetTextColor(185, 28, 28)
+		detail = fmt.Sprintf("Los votos nulos (%d) igualan o superan a los votos válidos (%d).", v.NullVotes, v.ValidVotes)
+	}
+
+	pdf.SetFont("Helvetica", "B", 11)
+	pdf.CellFormat(0, 8, r.tr(reportVerdicts[v.Status]), "", 1, "C", true, 0, "")
+	pdf.SetFont("Helvetica", "", 8.5)
+	pdf.MultiCell(0, 5, r.tr(detail), "", "C", true)
+
+	if p.IsTie {
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.SetTextColor(180, 83, 9)
+		pdf.CellFormat(0, 7, r.tr("EMPATE: varios candidatos tienen la mayor votación ponderada"), "", 1, "C", false, 0, "")
+	}
+}
+
+// signatures: espacio para las firmas del comité electoral
+func (r *resultsPDF) signatures() {
+	pdf := r.pdf
+	_, pageHeight := pdf.GetPageSize()
+	if pdf.GetY()+40 > pageHeight-reportFooterHeight-5 {
+		pdf.AddPage()
+	}
+	pdf.Ln(20)
+
+	pdf.SetDrawColor(100, 116, 139)
+	pdf.SetTextColor(71, 85, 105)
+	pdf.SetFont("Helvetica", "", 8.5)
+
+	y := pdf.GetY()
+	width := (210 - 2*reportMargin - 20) / 3
+	for i, role := range []string{"Presidente del comité", "Secretario del comité", "Vocal del comité"} {
+		x := reportMargin + float64(i)*(width+10)
+		pdf.Line(x, y, x+width, y)
+		pdf.SetXY(x, y+1)
+		pdf.CellFormat(width, 5, r.tr(role), "", 0, "C", false, 0, "")
+	}
+	pdf.SetXY(reportMargin, y+8)
+}
+
+// footer: fecha de emisión, hash del ReportDocument y paginación
+func (r *resultsPDF) footer() {
+	pdf := r.pdf
+	pdf.SetY(-reportFooterHeight)
+	pdf.SetDrawColor(226, 232, 240)
+	pdf.Line(reportMargin, pdf.GetY(), 210-reportMargin, pdf.GetY())
+	pdf.Ln(2)
+
+	pdf.SetTextColor(100, 116, 139)
+	pdf.SetFont("Helvetica", "", 7.5)
+	generated := r.data.generated.Format("2006-01-02 15:04:05") + " UTC"
+	pdf.CellFormat(0, 4, r.tr(fmt.Sprintf("Generado el %s · Página %d de {nb}", generated, pdf.PageNo())), "", 1, "C", false, 0, "")
+	pdf.SetFont("Courier", "", 7)
+	pdf.CellFormat(0, 4, "SHA-256: "+r.data.hash, "", 1, "C", false, 0, "")
+}
+
+// photo registra una vez la foto del candidato y devuelve su nombre en el PDF,
+// o "" si no tiene o no se pudo leer (el acta se emite igual, sin la foto)
+func (r *resultsPDF) photo(candidateID string) string {
+	if name, ok := r.photos[candidateID]; ok {
+		return name
+	}
+	r.photos[candidateID] = ""
+
+	img, ok := r.data.images[candidateID]
+	if !ok {
+		return ""
+	}
+	data, err := reportPhoto(filepath.Join(UploadFolder, filepath.Base(img.Filename)))
+	if err != nil {
+		logger.Log.Warnf("⚠️ Acta: no se pudo incluir la foto %s: %v", img.ID, err)
+		return ""
+	}
+
+	name := "photo-" + img.ID
+	r.pdf.RegisterImageOptionsReader(name, fpdf.ImageOptions{ImageType: "JPG"}, bytes.NewReader(data))
+	r.photos[candidateID] = name
+	return name
+}
+
+// reportPhoto: decodifica cualquier formato admitido por las subidas (también
+// webp, que el PDF no soporta), la reduce y la devuelve como JPEG sobre fondo blanco
+func reportPhoto(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	key := fmt.Sprintf("%s|%d|%d", path, info.Size(), info.ModTime().UnixNano())
+	reportPhotoCache.Lock()
+	cached, ok := reportPhotoCache.entries[key]
+	reportPhotoCache.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	// las dimensiones se leen de la cabecera antes de reservar memoria para
+	// la imagen completa
+	cfg, _, err := image.DecodeConfig(f)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > reportPhotoMaxSource {
+		return nil, fmt.Errorf("dimensiones no admitidas: %dx%d", cfg.Width, cfg.Height)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	src, _, err := image.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w == 0 || h == 0 {
+		return nil, fmt.Errorf("imagen vacía")
+	}
+	// recorte cuadrado centrado, como la foto del candidato en la web
+	side := w
+	if h < side {
+		side = h
+	}
+	crop := image.Rect(bounds.Min.X+(w-side)/2, bounds.Min.Y+(h-side)/2, 0, 0)
+	crop.Max = crop.Min.Add(image.Pt(side, side))
+
+	size := side
+	if size > reportPhotoPixels {
+		size = reportPhotoPixels
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, size, size))
+	xdraw.Draw(dst, dst.Bounds(), &image.Uniform{C: color.White}, image.Point{}, xdraw.Src)
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), src, crop, xdraw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+
+	reportPhotoCache.Lock()
+	if len(reportPhotoCache.entries) >= reportPhotoCacheSize {
+		clear(reportPhotoCache.entries)
+	}
+	reportPhotoCache.entries[key] = buf.Bytes()
+	reportPhotoCache.Unlock()
+	return buf.Bytes(), nil
+}
+
+func winnerLabel(p dto.PositionResultResponse) string {
+	if p.IsTie {
+		return "Empate"
+	}
+	for _, c := range p.Candidates {
+		if c.IsWinner {
+			return c.Name
+		}
+	}
+	return "-"
+}
+
+func validityShort(status string) string {
+	if status == ValidityValid {
+		return "VÁLIDA"
+	}
+	return "NO VÁLIDA"
+}
+
+func truncate(s string, max int) string {
+	runes := []rune(s)
+	if len(runes) <= max {
+		return s
+	}
+	return string(runes[:max-1]) + "…"
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"server/internal/dto"
+	"server/internal/models"
+	"server/pkgs/security"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Tipos de ReportDocument
+const (
+	reportPositionType = "position-results-report"
+	reportElectionType = "election-results-report"
+)
+
+type ReportService interface {
+	PositionPDF(positionID string) (*dto.ReportFile, error)
+	ElectionPDF(electionID string) (*dto.ReportFile, error)
+}
+
+type reportServiceImpl struct {
+	db *gorm.DB
+}
+
+func NewReportService(db *gorm.DB) ReportService {
+	return &reportServiceImpl{db: db}
+}
+
+// reportData: documento hasheado más lo que el PDF necesita y no forma parte
+// de los resultados (partido y foto de cada candidato)
+type reportData struct {
+	doc        dto.ReportDocument
+	hash       string
+	generated  time.Time
+	candidates map[string]models.Candidate
+	images     map[string]models.Image
+}
+
+// PositionPDF: acta de resultados de una posición
+func (s *reportServiceImpl) PositionPDF(positionID string) (*dto.ReportFile, error) {
+	result, err := NewResultService(s.db).GetByPosition(positionID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.load(reportPositionType, result.ElectionID, []dto.PositionResultResponse{*result})
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := renderResultsPDF(data, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.file(data, "acta-"+slugify(result.PositionName), content), nil
+}
+
+// ElectionPDF: resumen de la elección y un acta por cada posición
+func (s *reportServiceImpl) ElectionPDF(electionID string) (*dto.ReportFile, error) {
+	results, err := NewResultService(s.db).GetAll(electionID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.load(reportElectionType, electionID, results.Positions)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := renderResultsPDF(data, true)
+	if err != nil {
+		return nil, err
+	}
+	return s.file(data, "acta-"+slugify(data.doc.ElectionName), content), nil
+}
+
+func (s *reportServiceImpl) file(data *reportData, name string, content []byte) *dto.ReportFile {
+	return &dto.ReportFile{
+		Filename:    name + ".pdf",
+		ContentType: "application/pdf",
+		Content:     content,
+		Hash:        data.hash,
+		GeneratedAt: data.doc.GeneratedAt,
+	}
+}
+
+// load: completa el documento con la elección y el avance de mesas, calcula su
+// hash y carga candidatos e imágenes
+func (s *reportServiceImpl) load(reportType, electionID string, positions []dto.PositionResultResponse) (*reportData, error) {
+	var election models.Election
+	if err := s.db.First(&election, "id = ?", electionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrElectionNotFound
+		}
+		return nil, err
+	}
+
+	progress, err := loadMesaProgress(s.db, electionID)
+	if err != nil {
+		return nil, err
+	}
+
+	generated := time.Now().UTC().Truncate(time.Second)
+	data := &reportData{
+		doc: dto.ReportDocument{
+			Type:           reportType,
+			ElectionID:     election.ID,
+			ElectionName:   election.Name,
+			ElectionStatus: string(election.Status),
+			GeneratedAt:    generated.Format(time.RFC3339),
+			CountedMesas:   progress.counted,
+			TotalMesas:     progress.total,
+			Positions:      positions,
+		},
+		generated:  generated,
+		candidates: make(map[string]models.Candidate),
+		images:     make(map[string]models.Image),
+	}
+
+	raw, err := json.Marshal(data.doc)
+	if err != nil {
+		return nil, err
+	}
+	canonical, err := security.CanonicalJSON(raw)
+	if err != nil {
+		return nil, err
+	}
+	data.hash = security.DocumentHash(canonical)
+
+	var candidateIDs []string
+	for _, p := range positions {
+		for _, c := range p.Candidates {
+			candidateIDs = append(candidateIDs, c.ID)
+		}
+	}
+	if len(candidateIDs) == 0 {
+		return data, nil
+	}
+
+	var candidates []models.Candidate
+	if err := s.db.Preload("Image").Where("id IN ?", candidateIDs).Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+	for _, c := range candidates {
+		data.candidates[c.ID] = c
+		if c.Image != nil {
+			data.images[c.ID] = *c.Image
+		}
+	}
+	return data, nil
+}
+
+// slugify: nombre de archivo ASCII en minúsculas a partir de un texto libre
+func slugify(s string) string {
+	replacer := strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n")
+	s = replacer.Replace(strings.ToLower(s))
+
+	var b strings.Builder
+	dash := false
+	for _, r := range s {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+
+	slug := strings.TrimSuffix(b.String(), "-")
+	if slug == "" {
+		return "resultados"
+	}
+	return slug
+}
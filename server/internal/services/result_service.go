@@ -0,0 +1,249 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"server/internal/dto"
+	"server/internal/models"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+// Peso del voto DOCENTES frente al PUBLICO en posiciones de AUTORIDAD:
+// el total docente ponderado equivale al doble del total público.
+const AutoridadDocentesWeight = 2.0
+
+// Estados de validez de la elección de una posición
+const (
+	ValidityValid          = "VALID"
+	ValidityInvalidTurnout = "INVALID_TURNOUT"
+	ValidityInvalidNulls   = "INVALID_NULLS"
+)
+
+type ResultService interface {
+	GetAll(electionID string) (*dto.ResultsResponse, error)
+	GetByPosition(positionID string) (*dto.PositionResultResponse, error)
+}
+
+type resultServiceImpl struct {
+	db *gorm.DB
+}
+
+func NewResultService(db *gorm.DB) ResultService {
+	return &resultServiceImpl{db: db}
+}
+
+// voteTally: suma de votos por candidato y tipo de voto
+type voteTally struct {
+	CandidateID string
+	TypeVote    models.TypeVote
+	Total       int
+}
+
+func (s *resultServiceImpl) GetAll(electionID string) (*dto.ResultsResponse, error) {
+	var positions []models.Position
+	q := s.db.Preload("Candidates").Order("name")
+	if electionID != "" {
+		q = q.Where("election_id = ?", electionID)
+	}
+	if err := q.Find(&positions).Error; err != nil {
+		return nil, err
+	}
+
+	tallies, err := s.loadTallies(electionID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.ResultsResponse{
+		TotalPositions: len(positions),
+		Positions:      make([]dto.PositionResultResponse, len(positions)),
+	}
+
+	for i, p := range positions {
+		result := tabulatePosition(p, tallies)
+		res.Positions[i] = result
+		res.TotalVotes += result.EmittedVotes
+		res.TotalCandidates += len(result.Candidates)
+	}
+
+	return res, nil
+}
+
+func (s *resultServiceImpl) GetByPosition(positionID string) (*dto.PositionResultResponse, error) {
+	var position models.Position
+	if err := s.db.Preload("Candidates").First(&position, "id = ?", positionID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPositionNotFound
+		}
+		return nil, err
+	}
+
+	tallies, err := s.loadTallies(position.ElectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := tabulatePosition(position, tallies)
+	return &result, nil
+}
+
+// loadTallies: agrega el valor vigente de los votos por candidato y tipo de
+// voto, excluyendo votos y mesas anuladas. Sin electionID agrega todas.
+func (s *resultServiceImpl) loadTallies(electionID string) (map[string]map[models.TypeVote]int, error) {
+	var rows []voteTally
+	q := s.db.Model(&models.Vote{}).
+		Select("votes.candidate_id, votes.type_vote, SUM(votes.vote) AS total").
+		Joins("JOIN mesas ON mesas.id = votes.mesa_id").
+		Where("mesas.status <> ? AND votes.is_annulled = false", models.MSannulled)
+	if electionID != "" {
+		q = q.Where("mesas.election_id = ?", electionID)
+	}
+	if err := q.Group("votes.candidate_id, votes.type_vote").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	tallies := make(map[string]map[models.TypeVote]int)
+	for _, r := range rows {
+		if tallies[r.CandidateID] == nil {
+			tallies[r.CandidateID] = make(map[models.TypeVote]int)
+		}
+		tallies[r.CandidateID][r.TypeVote] += r.Total
+	}
+	return tallies, nil
+}
+
+// tabulatePosition: aplica la regla de ponderación del tipo de posición,
+// calcula porcentajes y marca al ganador.
+//
+// AUTORIDAD: votos = PUBLICO + DOCENTES * (2 * totalPublico / totalDocentes)
+// ORGANO:    votos = PUBLICO + DOCENTES
+func tabulatePosition(p models.Position, tallies map[string]map[models.TypeVote]int) dto.PositionResultResponse {
+	result := dto.PositionResultResponse{
+		ElectionID:      p.ElectionID,
+		PositionID:      p.ID,
+		PositionName:    p.Name,
+		TypePosition:    string(p.TypePosition),
+		TotalVotes:      p.TotalVotes,
+		ValidPercentage: p.ValidPercentage,
+		WeightFactor:    1,
+		Candidates:      []dto.CandidateResult{},
+	}
+
+	for _, c := range p.Candidates {
+		docentes := tallies[c.ID][models.TVpersonnel]
+		publico := tallies[c.ID][models.TVpublic]
+
+		if c.TypeCandidate == models.TCnull {
+			result.NullVotes += docentes + publico
+			continue
+		}
+
+		result.VotesDocentes += docentes
+		result.VotesPublico += publico
+		result.Candidates = append(result.Candidates, dto.CandidateResult{
+			ID:            c.ID,
+			Name:          c.Name,
+			ImageID:       c.ImageID,
+			VotesDocentes: docentes,
+			VotesPublico:  publico,
+		})
+	}
+
+	result.ValidVotes = result.VotesDocentes + result.VotesPublico
+	result.EmittedVotes = result.ValidVotes + result.NullVotes
+
+	if p.TypePosition == models.TAposition {
+		result.WeightFactor = 0
+		if result.VotesDocentes > 0 {
+			result.WeightFactor = AutoridadDocentesWeight * float64(result.VotesPublico) / float64(result.VotesDocentes)
+		}
+	}
+
+	var totalWeighted float64
+	for i := range result.Candidates {
+		c := &result.Candidates[i]
+		c.Votes = float64(c.VotesPublico) + float64(c.VotesDocentes)*result.WeightFactor
+		totalWeighted += c.Votes
+	}
+
+	// ganador y empate se deciden con el puntaje entero exacto y no con Votes:
+	// ni el redondeo a dos decimales ni el error de coma flotante pueden crear
+	// o romper un empate
+	score := weightedScore(p.TypePosition, result.VotesDocentes, result.VotesPublico)
+	sort.SliceStable(result.Candidates, func(i, j int) bool {
+		return score(result.Candidates[i]) > score(result.Candidates[j])
+	})
+
+	if len(result.Candidates) > 0 && score(result.Candidates[0]) > 0 {
+		top := score(result.Candidates[0])
+		winners := 0
+		for i := range result.Candidates {
+			if score(result.Candidates[i]) == top {
+				result.Candidates[i].IsWinner = true
+				winners++
+			}
+		}
+		result.IsTie = winners > 1
+	}
+
+	for i := range result.Candidates {
+		c := &result.Candidates[i]
+		if totalWeighted > 0 {
+			c.Percentage = round2(c.Votes / totalWeighted * 100)
+		}
+		c.Votes = round2(c.Votes)
+	}
+
+	result.WeightFactor = round2(result.WeightFactor)
+	result.Validity = evaluateValidity(p, result.ValidVotes, result.NullVotes)
+	return result
+}
+
+// weightedScore: voto ponderado de un candidato multiplicado por el total de
+// votos docentes, que lo vuelve entero. En AUTORIDAD es
+// publico·D + 2·docentes·P; en ORGANO (o sin docentes) la suma simple.
+func weightedScore(typePosition models.TypePositions, totalDocentes, totalPublico int) func(dto.CandidateResult) int64 {
+	return func(c dto.CandidateResult) int64 {
+		switch {
+		case typePosition != models.TAposition:
+			return int64(c.VotesPublico) + int64(c.VotesDocentes)
+		case totalDocentes == 0:
+			return int64(c.VotesPublico)
+		default:
+			return int64(c.VotesPublico)*int64(totalDocentes) +
+				int64(AutoridadDocentesWeight)*int64(c.VotesDocentes)*int64(totalPublico)
+		}
+	}
+}
+
+// evaluateValidity: la elección es válida si los votos válidos alcanzan
+// ValidPercentage del electorado (TotalVotes) y superan a los votos nulos.
+func evaluateValidity(p models.Position, validVotes, nullVotes int) dto.ValidityResult {
+	v := dto.ValidityResult{
+		Status:             ValidityValid,
+		Electorate:         p.TotalVotes,
+		ValidVotes:         validVotes,
+		NullVotes:          nullVotes,
+		RequiredPercentage: p.ValidPercentage,
+	}
+
+	if p.TotalVotes > 0 {
+		v.Turnout = math.Round(float64(validVotes)/float64(p.TotalVotes)*10000) / 10000
+	}
+
+	switch {
+	case p.TotalVotes <= 0 || float64(validVotes) < p.ValidPercentage*float64(p.TotalVotes):
+		v.Status = ValidityInvalidTurnout
+	case nullVotes >= validVotes:
+		v.Status = ValidityInvalidNulls
+	}
+
+	return v
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"testing"
+
+	"server/internal/dto"
+	"server/internal/models"
+)
+
+// tally: votos de un candidato en la mesa de prueba
+type tally struct {
+	id       string
+	null     bool
+	docentes int
+	publico  int
+}
+
+func tabulateFixture(typePosition models.TypePositions, tallies ...tally) dto.PositionResultResponse {
+	p := models.Position{
+		Name:            "Rectorado",
+		TypePosition:    typePosition,
+		TotalVotes:      1000,
+		ValidPercentage: 0.5,
+	}
+	votes := map[string]map[models.TypeVote]int{}
+	for _, t := range tallies {
+		c := models.Candidate{Base: models.Base{ID: t.id}, Name: t.id, TypeCandidate: models.TCcandidate}
+		if t.null {
+			c.TypeCandidate = models.TCnull
+		}
+		p.Candidates = append(p.Candidates, c)
+		votes[t.id] = map[models.TypeVote]int{
+			models.TVpersonnel: t.docentes,
+			models.TVpublic:    t.publico,
+		}
+	}
+	return tabulatePosition(p, votes)
+}
+
+func TestTabulatePosition(t *testing.T) {
+	// want: por candidato en el orden del resultado
+	type want struct {
+		id         string
+		votes      float64
+		percentage float64
+		winner     bool
+	}
+
+	tests := []struct {
+		name         string
+		typePosition models.TypePositions
+		tallies      []tally
+		weightFactor float64
+		valid, nulls int
+		tie          bool
+		want         []want
+	}{
+		{
+			name:         "ORGANO suma docentes y público",
+			typePosition: models.TIposition,
+			tallies: []tally{
+				{id: "B", docentes: 3, publico: 2},
+				{id: "A", docentes: 10, publico: 5},
+				{id: "nulo", null: true, docentes: 1, publico: 3},
+			},
+			weightFactor: 1,
+			valid:        20,
+			nulls:        4,
+			want: []want{
+				{"A", 15, 75, true},
+				{"B", 5, 25, false},
+			},
+		},
+		{
+			name:         "AUTORIDAD pondera docentes por 2·público/docentes",
+			typePosition: models.TAposition,
+			// D = 20, P = 60 → factor 6
+			tallies: []tally{
+				{id: "A", docentes: 15, publico: 20},
+				{id: "B", docentes: 5, publico: 40},
+			},
+			weightFactor: 6,
+			valid:        80,
+			want: []want{
+				{"A", 110, 61.11, true},
+				{"B", 70, 38.89, false},
+			},
+		},
+		{
+			name:         "AUTORIDAD sin votos docentes cuenta solo el público",
+			typePosition: models.TAposition,
+			tallies: []tally{
+				{id: "A", publico: 10},
+				{id: "B", publico: 30},
+			},
+			weightFactor: 0,
+			valid:        40,
+			want: []want{
+				{"B", 30, 75, true},
+				{"A", 10, 25, false},
+			},
+		},
+		{
+			name:         "AUTORIDAD con factor no entero",
+			typePosition: models.TAposition,
+			// D = 3, P = 2 → factor 4/3
+			tallies: []tally{
+				{id: "A", docentes: 2, publico: 0},
+				{id: "B", docentes: 1, publico: 2},
+			},
+			weightFactor: 1.33,
+			valid:        5,
+			want: []want{
+				{"B", 3.33, 55.56, true},
+				{"A", 2.67, 44.44, false},
+			},
+		},
+		{
+			name:         "empate",
+			typePosition: models.TIposition,
+			tallies: []tally{
+				{id: "A", docentes: 5, publico: 5},
+				{id: "B", docentes: 7, publico: 3},
+				{id: "C", docentes: 1, publico: 1},
+			},
+			weightFactor: 1,
+			valid:        22,
+			tie:          true,
+			want: []want{
+				{"A", 10, 45.45, true},
+				{"B", 10, 45.45, true},
+				{"C", 2, 9.09, false},
+			},
+		},
+		{
+			name:         "empate exacto que en coma flotante difiere",
+			typePosition: models.TAposition,
+			// D = 28, P = 9: 9 + 7·18/28 = 21·18/28 = 13.5, pero en float64
+			// uno de los dos queda en 13.500000000000002
+			tallies: []tally{
+				{id: "A", docentes: 7, publico: 9},
+				{id: "B", docentes: 21, publico: 0},
+			},
+			weightFactor: 0.64,
+			valid:        37,
+			tie:          true,
+			want: []want{
+				{"A", 13.5, 50, true},
+				{"B", 13.5, 50, true},
+			},
+		},
+		{
+			name:         "diferencia menor que el redondeo no es empate",
+			typePosition: models.TAposition,
+			// D = 1000, P = 499 → factor 0.998: A = 649.200 y B = 649.198,
+			// ambos se muestran como 649.2
+			tallies: []tally{
+				{id: "B", docentes: 401, publico: 249},
+				{id: "A", docentes: 400, publico: 250},
+				{id: "C", docentes: 199, publico: 0},
+			},
+			weightFactor: 1,
+			valid:        1499,
+			want: []want{
+				{"A", 649.2, 43.37, true},
+				{"B", 649.2, 43.37, false},
+				{"C", 198.6, 13.27, false},
+			},
+		},
+		{
+			name:         "sin votos no hay ganador",
+			typePosition: models.TIposition,
+			tallies: []tally{
+				{id: "A"},
+				{id: "B"},
+				{id: "nulo", null: true, docentes: 2},
+			},
+			weightFactor: 1,
+			nulls:        2,
+			want: []want{
+				{"A", 0, 0, false},
+				{"B", 0, 0, false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := tabulateFixture(tt.typePosition, tt.tallies...)
+
+			if res.WeightFactor != tt.weightFactor {
+				t.Errorf("weightFactor = %v, want %v", res.WeightFactor, tt.weightFactor)
+			}
+			if res.ValidVotes != tt.valid || res.NullVotes != tt.nulls || res.EmittedVotes != tt.valid+tt.nulls {
+				t.Errorf("válidos/nulos/emitidos = %d/%d/%d, want %d/%d/%d",
+					res.ValidVotes, res.NullVotes, res.EmittedVotes, tt.valid, tt.nulls, tt.valid+tt.nulls)
+			}
+			if res.IsTie != tt.tie {
+				t.Errorf("isTie = %v, want %v", res.IsTie, tt.tie)
+			}
+			if len(res.Candidates) != len(tt.want) {
+				t.Fatalf("%d candidatos, want %d", len(res.Candidates), len(tt.want))
+			}
+			for i, w := range tt.want {
+				c := res.Candidates[i]
+				if c.ID != w.id || c.Votes != w.votes || c.Percentage != w.percentage || c.IsWinner != w.winner {
+					t.Errorf("#%d = {%s %v %v%% ganador=%v}, want {%s %v %v%% ganador=%v}",
+						i, c.ID, c.Votes, c.Percentage, c.IsWinner, w.id, w.votes, w.percentage, w.winner)
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluateValidity(t *testing.T) {
+	tests := []struct {
+		name        string
+		electorate  int
+		required    float64
+		valid, null int
+		wantStatus  string
+		wantTurnout float64
+	}{
+		{"justo en el mínimo", 100, 0.5, 50, 0, ValidityValid, 0.5},
+		{"un voto bajo el mínimo", 100, 0.5, 49, 0, ValidityInvalidTurnout, 0.49},
+		{"nulos uno menos que válidos", 100, 0.5, 50, 49, ValidityValid, 0.5},
+		{"nulos igual a válidos", 100, 0.5, 50, 50, ValidityInvalidNulls, 0.5},
+		{"nulos más que válidos", 100, 0.5, 60, 61, ValidityInvalidNulls, 0.6},
+		{"participación se evalúa antes que nulos", 100, 0.5, 40, 45, ValidityInvalidTurnout, 0.4},
+		{"electorado cero", 0, 0.5, 10, 0, ValidityInvalidTurnout, 0},
+		{"electorado negativo", -5, 0.5, 10, 0, ValidityInvalidTurnout, 0},
+		// 0.57·100 es 56.99999999999999 en float64: 57 válidos alcanzan
+		{"porcentaje no representable", 100, 0.57, 57, 0, ValidityValid, 0.57},
+		{"sin mínimo y sin votos", 100, 0, 0, 0, ValidityInvalidNulls, 0},
+		{"sin mínimo con votos", 100, 0, 1, 0, ValidityValid, 0.01},
+		{"participación redondeada a 4 decimales", 3, 0.5, 2, 0, ValidityValid, 0.6667},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := models.Position{TotalVotes: tt.electorate, ValidPercentage: tt.required}
+			v := evaluateValidity(p, tt.valid, tt.null)
+
+			if v.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", v.Status, tt.wantStatus)
+			}
+			if v.Turnout != tt.wantTurnout {
+				t.Errorf("turnout = %v, want %v", v.Turnout, tt.wantTurnout)
+			}
+			if v.Electorate != tt.electorate || v.ValidVotes != tt.valid || v.NullVotes != tt.null || v.RequiredPercentage != tt.required {
+				t.Errorf("datos de entrada alterados: %+v", v)
+			}
+		})
+	}
+}
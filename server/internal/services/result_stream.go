@@ -0,0 +1,282 @@
+package services
+
+import (
+	"errors"
+	"math"
+	"server/internal/dto"
+	"server/internal/models"
+	"server/pkgs/logger"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// resultBacklogSize: eventos recientes que se conservan para reanudar con Last-Event-ID
+const resultBacklogSize = 512
+
+// ResultNotifier: lo usan los servicios que escriben votos para avisar, tras
+// el commit, qué posiciones cambiaron
+type ResultNotifier interface {
+	VotesChanged(positionIDs ...string)
+}
+
+// ResultEvent: un evento del stream; Snapshot indica que Deltas es el estado
+// completo y no un cambio
+type ResultEvent struct {
+	ID       int64
+	Snapshot bool
+	Deltas   []dto.ResultDelta
+}
+
+type resultSubscriber struct {
+	electionID string
+	ch         chan ResultEvent
+}
+
+// ResultHub agrupa los avisos de votos y recalcula cada posición cambiada una
+// sola vez por ventana de coalescencia, sin importar cuántos clientes estén
+// conectados. Los eventos se numeran y los últimos se guardan en memoria para
+// que un cliente reconectado reciba solo lo que se perdió.
+type ResultHub struct {
+	db       *gorm.DB
+	coalesce time.Duration
+
+	mu      sync.Mutex
+	dirty   map[string]struct{}
+	lastID  int64
+	backlog []ResultEvent
+	subs    map[*resultSubscriber]struct{}
+	wake    chan struct{}
+}
+
+func NewResultHub(db *gorm.DB, coalesce time.Duration) *ResultHub {
+	h := &ResultHub{
+		db:       db,
+		coalesce: coalesce,
+		dirty:    make(map[string]struct{}),
+		subs:     make(map[*resultSubscriber]struct{}),
+		wake:     make(chan struct{}, 1),
+	}
+	go h.run()
+	return h
+}
+
+// VotesChanged marca las posiciones como pendientes; no bloquea ni consulta la BD
+func (h *ResultHub) VotesChanged(positionIDs ...string) {
+	h.mu.Lock()
+	for _, id := range positionIDs {
+		if id != "" {
+			h.dirty[id] = struct{}{}
+		}
+	}
+	h.mu.Unlock()
+
+	select {
+	case h.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Subscribe registra un cliente. Si lastEventID sigue en el backlog devuelve
+// los eventos posteriores; si no (cliente nuevo, hueco demasiado grande o
+// reinicio del servidor) devuelve resumed=false y el llamador debe enviar un
+// snapshot.
+func (h *ResultHub) Subscribe(electionID string, lastEventID int64) (<-chan ResultEvent, []ResultEvent, bool, func()) {
+	sub := &resultSubscriber{electionID: electionID, ch: make(chan ResultEvent, 64)}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.subs[sub] = struct{}{}
+	cancel := func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		if _, ok := h.subs[sub]; ok {
+			delete(h.subs, sub)
+			close(sub.ch)
+		}
+	}
+
+	resumed := lastEventID > 0 && lastEventID <= h.lastID
+	if resumed && len(h.backlog) > 0 && h.backlog[0].ID > lastEventID+1 {
+		resumed = false
+	}
+	if !resumed {
+		return sub.ch, nil, false, cancel
+	}
+
+	var missed []ResultEvent
+	for _, ev := range h.backlog {
+		if ev.ID > lastEventID {
+			if filtered, ok := filterResultEvent(ev, electionID); ok {
+				missed = append(missed, filtered)
+			}
+		}
+	}
+	return sub.ch, missed, true, cancel
+}
+
+// Snapshot: estado actual de todas las posiciones (de una elección si se
+// indica), con el id del último evento emitido para poder reanudar después
+func (h *ResultHub) Snapshot(electionID string) (ResultEvent, error) {
+	h.mu.Lock()
+	lastID := h.lastID
+	h.mu.Unlock()
+
+	results, err := (&resultServiceImpl{db: h.db}).GetAll(electionID)
+	if err != nil {
+		return ResultEvent{}, err
+	}
+
+	deltas, err := h.toDeltas(results.Positions)
+	if err != nil {
+		return ResultEvent{}, err
+	}
+	return ResultEvent{ID: lastID, Snapshot: true, Deltas: deltas}, nil
+}
+
+func (h *ResultHub) run() {
+	for range h.wake {
+		// se espera la ventana para agrupar ráfagas de votos en un solo cálculo
+		time.Sleep(h.coalesce)
+
+		h.mu.Lock()
+		positionIDs := make([]string, 0, len(h.dirty))
+		for id := range h.dirty {
+			positionIDs = append(positionIDs, id)
+		}
+		h.dirty = make(map[string]struct{})
+		h.mu.Unlock()
+
+		if len(positionIDs) == 0 {
+			continue
+		}
+
+		deltas, err := h.computeDeltas(positionIDs)
+		if err != nil {
+			logger.Log.Errorf("❌ Result stream: no se pudieron calcular los resultados: %v", err)
+			continue
+		}
+		if len(deltas) > 0 {
+			h.publish(deltas)
+		}
+	}
+}
+
+func (h *ResultHub) publish(deltas []dto.ResultDelta) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastID++
+	ev := ResultEvent{ID: h.lastID, Deltas: deltas}
+
+	h.backlog = append(h.backlog, ev)
+	if len(h.backlog) > resultBacklogSize {
+		h.backlog = h.backlog[len(h.backlog)-resultBacklogSize:]
+	}
+
+	for sub := range h.subs {
+		filtered, ok := filterResultEvent(ev, sub.electionID)
+		if !ok {
+			continue
+		}
+		select {
+		case sub.ch <- filtered:
+		default:
+			// cliente lento: se le desconecta y al reconectar se pone al día con Last-Event-ID
+			delete(h.subs, sub)
+			close(sub.ch)
+		}
+	}
+}
+
+// computeDeltas: resultados de las posiciones indicadas
+func (h *ResultHub) computeDeltas(positionIDs []string) ([]dto.ResultDelta, error) {
+	service := &resultServiceImpl{db: h.db}
+
+	results := make([]dto.PositionResultResponse, 0, len(positionIDs))
+	for _, id := range positionIDs {
+		result, err := service.GetByPosition(id)
+		if err != nil {
+			if errors.Is(err, ErrPositionNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		results = append(results, *result)
+	}
+	return h.toDeltas(results)
+}
+
+// toDeltas: añade a cada posición el avance de mesas escrutadas de su elección
+func (h *ResultHub) toDeltas(results []dto.PositionResultResponse) ([]dto.ResultDelta, error) {
+	progress := make(map[string]mesaProgress)
+	now := time.Now().Format(time.RFC3339Nano)
+
+	deltas := make([]dto.ResultDelta, 0, len(results))
+	for _, result := range results {
+		p, ok := progress[result.ElectionID]
+		if !ok {
+			var err error
+			if p, err = loadMesaProgress(h.db, result.ElectionID); err != nil {
+				return nil, err
+			}
+			progress[result.ElectionID] = p
+		}
+
+		deltas = append(deltas, dto.ResultDelta{
+			ElectionID:        result.ElectionID,
+			PositionID:        result.PositionID,
+			PositionName:      result.PositionName,
+			Candidates:        result.Candidates,
+			ValidVotes:        result.ValidVotes,
+			NullVotes:         result.NullVotes,
+			EmittedVotes:      result.EmittedVotes,
+			IsTie:             result.IsTie,
+			CountedMesas:      p.counted,
+			TotalMesas:        p.total,
+			CountedPercentage: p.percentage(),
+			UpdatedAt:         now,
+		})
+	}
+	return deltas, nil
+}
+
+type mesaProgress struct {
+	counted int
+	total   int
+}
+
+func (p mesaProgress) percentage() float64 {
+	if p.total == 0 {
+		return 0
+	}
+	return math.Round(float64(p.counted)/float64(p.total)*10000) / 100
+}
+
+// loadMesaProgress: mesas COUNTED frente al total, sin contar las anuladas
+func loadMesaProgress(db *gorm.DB, electionID string) (mesaProgress, error) {
+	var row struct {
+		Counted int
+		Total   int
+	}
+	err := db.Model(&models.Mesa{}).
+		Select("COUNT(*) FILTER (WHERE status = ?) AS counted, COUNT(*) AS total", models.MScounted).
+		Where("election_id = ? AND status <> ?", electionID, models.MSannulled).
+		Scan(&row).Error
+	return mesaProgress{counted: row.Counted, total: row.Total}, err
+}
+
+func filterResultEvent(ev ResultEvent, electionID string) (ResultEvent, bool) {
+	if electionID == "" {
+		return ev, true
+	}
+	filtered := ResultEvent{ID: ev.ID, Snapshot: ev.Snapshot}
+	for _, d := range ev.Deltas {
+		if d.ElectionID == electionID {
+			filtered.Deltas = append(filtered.Deltas, d)
+		}
+	}
+	return filtered, len(filtered.Deltas) > 0
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"server/internal/models"
+	"server/pkgs/security"
+)
+
+// SessionService vuelve a validar el access token de conexiones largas
+// (WebSocket), que solo pasan por el middleware al abrirse
+type SessionService interface {
+	Validate(token string) (*security.AccessClaims, error)
+}
+
+type sessionServiceImpl struct {
+	db  *gorm.DB
+	jwt *security.JWTService
+}
+
+func NewSessionService(db *gorm.DB, jwt *security.JWTService) SessionService {
+	return &sessionServiceImpl{db: db, jwt: jwt}
+}
+
+// Validate: el token es válido y su usuario sigue activo, con el mismo rol y
+// sin cambio de contraseña pendiente
+func (s *sessionServiceImpl) Validate(token string) (*security.AccessClaims, error) {
+	claims, err := s.jwt.ValidateToken(token)
+	if err != nil {
+		return nil, ErrSessionRevoked
+	}
+	if claims.MustChangePassword {
+		return nil, ErrSessionRevoked
+	}
+
+	var user models.User
+	if err := s.db.Select("id", "rol", "is_active", "must_change_password").
+		First(&user, "id = ?", claims.Subject).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSessionRevoked
+		}
+		return nil, err
+	}
+	if !user.IsActive || user.MustChangePassword || string(user.Rol) != claims.Role {
+		return nil, ErrSessionRevoked
+	}
+	return claims, nil
+}
@@ -0,0 +1,410 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"server/internal/dto"
+	"server/internal/models"
+	"server/pkgs/security"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UserService interface {
+	GetAll(rol string) ([]dto.UserResponse, error)
+	GetOne(id string) (*dto.UserResponse, error)
+	Create(req dto.CreateUserRequest, userID, userRole string) (*dto.UserResponse, error)
+	Update(id string, req dto.UpdateUserRequest, userID, userRole string) (*dto.UserResponse, error)
+	ResetPassword(id string, req dto.ResetPasswordRequest, userID, userRole string) error
+	Unlock(id, userID, userRole string) (*dto.UserResponse, error)
+	Invite(id, userID, userRole string) (*dto.UserResponse, error)
+}
+
+type userServiceImpl struct {
+	db        *gorm.DB
+	argon     *security.Argon2Service
+	policy    *security.PasswordPolicy
+	inviteTTL time.Duration
+}
+
+func NewUserService(db *gorm.DB, argon *security.Argon2Service, policy *security.PasswordPolicy, inviteTTL time.Duration) UserService {
+	return &userServiceImpl{db: db, argon: argon, policy: policy, inviteTTL: inviteTTL}
+}
+
+func mapUserToResponse(u models.User) dto.UserResponse {
+	return dto.UserResponse{
+		ID:       u.ID,
+		Name:     u.Name,
+		Email:    u.Email,
+		Rol:      string(u.Rol),
+		Party:    u.Party,
+		IsActive: u.IsActive,
+		Locked:   u.LockedUntil != nil && time.Now().Before(*u.LockedUntil),
+
+		MustChangePassword: u.MustChangePassword,
+		CreatedAt:          u.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:          u.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func (s *userServiceImpl) GetAll(rol string) ([]dto.UserResponse, error) {
+	var users []models.User
+	q := s.db.Order("name")
+	if rol != "" {
+		q = q.Where("rol = ?", strings.ToUpper(rol))
+	}
+	if err := q.Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.UserResponse, len(users))
+	for i, u := range users {
+		res[i] = mapUserToResponse(u)
+	}
+	return res, nil
+}
+
+func (s *userServiceImpl) GetOne(id string) (*dto.UserResponse, error) {
+	var user models.User
+	if err := s.db.First(&user, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	res := mapUserToResponse(user)
+	return &res, nil
+}
+
+func (s *userServiceImpl) Create(req dto.CreateUserRequest, userID, userRole string) (*dto.UserResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("nombre obligatorio")
+	}
+
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+	if !emailRx.MatchString(email) {
+		return nil, ErrSignupEmailInvalid
+	}
+
+	rol := models.Rol(strings.ToUpper(req.Rol))
+	if err := validateRoleAssignment(rol, userRole); err != nil {
+		return nil, err
+	}
+
+	party := normalizeParty(req.Party)
+	if rol == models.RolPersonero && party == nil {
+		return nil, ErrPartyRequired
+	}
+
+	if err := s.ensureEmailAvailable(email, ""); err != nil {
+		return nil, err
+	}
+
+	user := models.User{
+		Name:     name,
+		Email:    email,
+		Rol:      rol,
+		Party:    party,
+		IsActive: true,
+	}
+
+	// Con contraseña se obliga a cambiarla en el primer login; sin ella el
+	// usuario solo puede entrar mediante la invitación
+	if req.Password != "" {
+		if err := s.policy.Validate(req.Password, email); err != nil {
+			return nil, err
+		}
+		hashed, err := s.argon.HashPassword(req.Password)
+		if err != nil {
+			return nil, err
+		}
+		user.Password = hashed
+		user.MustChangePassword = true
+	}
+
+	var invitation *dto.InvitationResponse
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		if req.Password != "" {
+			return nil
+		}
+
+		var err error
+		invitation, err = s.createInvitation(tx, user.ID, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := mapUserToResponse(user)
+	res.Invitation = invitation
+	return &res, nil
+}
+
+func (s *userServiceImpl) Update(id string, req dto.UpdateUserRequest, userID, userRole string) (*dto.UserResponse, error) {
+	var user models.User
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&user, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		// Solo un SUPERADMIN puede modificar a otro SUPERADMIN
+		if err := validateRoleAssignment(user.Rol, userRole); err != nil {
+			return err
+		}
+
+		wasSuperAdmin := user.Rol == models.RolSuperAdmin && user.IsActive
+
+		if req.Name != nil {
+			name := strings.TrimSpace(*req.Name)
+			if name == "" {
+				return fmt.Errorf("el nombre no puede estar vacío")
+			}
+			user.Name = name
+		}
+
+		if req.Email != nil {
+			email := strings.ToLower(strings.TrimSpace(*req.Email))
+			if !emailRx.MatchString(email) {
+				return ErrSignupEmailInvalid
+			}
+			if err := s.ensureEmailAvailable(email, user.ID); err != nil {
+				return err
+			}
+			user.Email = email
+		}
+
+		if req.Rol != nil {
+			rol := models.Rol(strings.ToUpper(*req.Rol))
+			if err := validateRoleAssignment(rol, userRole); err != nil {
+				return err
+			}
+			user.Rol = rol
+		}
+
+		if req.Party != nil {
+			user.Party = normalizeParty(req.Party)
+		}
+		if user.Rol == models.RolPersonero && user.Party == nil {
+			return ErrPartyRequired
+		}
+
+		if req.IsActive != nil {
+			if !*req.IsActive && user.ID == userID {
+				return ErrUserSelfDeactivate
+			}
+			user.IsActive = *req.IsActive
+		}
+
+		if wasSuperAdmin && (user.Rol != models.RolSuperAdmin || !user.IsActive) {
+			if err := ensureAnotherSuperAdmin(tx, user.ID); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Save(&user).Error; err != nil {
+			return err
+		}
+
+		// Desactivar corta la renovación; el access token vigente caduca solo
+		if !user.IsActive {
+			return revokeRefreshTokens(tx.Where("user_id = ?", user.ID))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := mapUserToResponse(user)
+	return &res, nil
+}
+
+func (s *userServiceImpl) ResetPassword(id string, req dto.ResetPasswordRequest, userID, userRole string) error {
+	var user models.User
+	if err := s.db.Select("id", "email", "rol").First(&user, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	if err := validateRoleAssignment(user.Rol, userRole); err != nil {
+		return err
+	}
+
+	if err := s.policy.Validate(req.Password, user.Email); err != nil {
+		return err
+	}
+
+	hashed, err := s.argon.HashPassword(req.Password)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&user).Updates(map[string]interface{}{
+			"password":             hashed,
+			"must_change_password": true,
+		}).Error; err != nil {
+			return err
+		}
+		return revokeRefreshTokens(tx.Where("user_id = ?", user.ID))
+	})
+}
+
+// Unlock: levanta el bloqueo por intentos fallidos antes de que expire
+func (s *userServiceImpl) Unlock(id, userID, userRole string) (*dto.UserResponse, error) {
+	var user models.User
+	if err := s.db.First(&user, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	if err := validateRoleAssignment(user.Rol, userRole); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Model(&user).Updates(map[string]interface{}{
+		"failed_attempts": 0,
+		"locked_until":    nil,
+	}).Error; err != nil {
+		return nil, err
+	}
+	user.FailedAttempts = 0
+	user.LockedUntil = nil
+
+	res := mapUserToResponse(user)
+	return &res, nil
+}
+
+// Invite: genera una nueva invitación; las anteriores sin usar dejan de valer
+func (s *userServiceImpl) Invite(id, userID, userRole string) (*dto.UserResponse, error) {
+	var user models.User
+	if err := s.db.First(&user, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	if err := validateRoleAssignment(user.Rol, userRole); err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, ErrUserInactive
+	}
+
+	var invitation *dto.InvitationResponse
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		invitation, err = s.createInvitation(tx, user.ID, userID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := mapUserToResponse(user)
+	res.Invitation = invitation
+	return &res, nil
+}
+
+// createInvitation: el token en claro solo se devuelve aquí; en BD queda su hash
+func (s *userServiceImpl) createInvitation(tx *gorm.DB, userID, createdBy string) (*dto.InvitationResponse, error) {
+	if err := tx.Model(&models.Invitation{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("expires_at", time.Now()).Error; err != nil {
+		return nil, err
+	}
+
+	token := security.GenerateRandomToken(32)
+	if token == "" {
+		return nil, errors.New("no se pudo generar la invitación")
+	}
+
+	invite := models.Invitation{
+		UserID:    userID,
+		TokenHash: security.HashToken(token),
+		ExpiresAt: time.Now().Add(s.inviteTTL),
+		CreatedBy: createdBy,
+	}
+	if err := tx.Create(&invite).Error; err != nil {
+		return nil, err
+	}
+
+	return &dto.InvitationResponse{
+		Token:     token,
+		ExpiresAt: invite.ExpiresAt.Format(time.RFC3339),
+	}, nil
+}
+
+func (s *userServiceImpl) ensureEmailAvailable(email, exceptID string) error {
+	q := s.db.Model(&models.User{}).Where("email = ?", email)
+	if exceptID != "" {
+		q = q.Where("id <> ?", exceptID)
+	}
+
+	var count int64
+	if err := q.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrSignupEmailTaken
+	}
+	return nil
+}
+
+// validateRoleAssignment: el rol debe existir y solo un SUPERADMIN gestiona SUPERADMINs
+func validateRoleAssignment(rol models.Rol, actorRole string) error {
+	switch rol {
+	case models.RolSuperAdmin:
+		if models.Rol(actorRole) != models.RolSuperAdmin {
+			return ErrUnauthorizedAction
+		}
+	case models.RolAdmin, models.RolDigitador, models.RolSupervisor, models.RolPersonero, models.RolObserver:
+	default:
+		return ErrInvalidRole
+	}
+	return nil
+}
+
+// ensureAnotherSuperAdmin: bloquea los SUPERADMIN activos restantes para que
+// dos peticiones concurrentes no puedan dejar el sistema sin ninguno
+func ensureAnotherSuperAdmin(tx *gorm.DB, exceptID string) error {
+	var others []models.User
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("rol = ? AND is_active = true AND id <> ?", models.RolSuperAdmin, exceptID).
+		Find(&others).Error; err != nil {
+		return err
+	}
+	if len(others) == 0 {
+		return ErrLastSuperAdmin
+	}
+	return nil
+}
+
+func normalizeParty(party *string) *string {
+	if party == nil {
+		return nil
+	}
+	p := strings.TrimSpace(*party)
+	if p == "" {
+		return nil
+	}
+	return &p
+}
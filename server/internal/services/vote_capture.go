@@ -0,0 +1,191 @@
+package services
+
+import (
+	"errors"
+	"server/internal/dto"
+	"server/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// capture: registra una digitación en modo doble entrada. La primera queda
+// PENDING; la segunda, de otro usuario, confirma el voto si coincide o abre
+// una discrepancia si no.
+func (s *voteServiceImpl) capture(req dto.CreateVoteRequest, tv models.TypeVote, actor AuditActor) (*dto.VoteResponse, error) {
+	var captured models.VoteCapture
+	var voteID string
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockVoteTarget(tx, req.MesaID, req.CandidateID, tv); err != nil {
+			return err
+		}
+
+		var err error
+		captured, voteID, err = captureVote(tx, req.MesaID, req.CandidateID, tv, req.TotalVotes, nil, actor)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if voteID != "" {
+		var vote models.Vote
+		if err := s.db.Preload("Mesa").Preload("Candidate.Position").
+			First(&vote, "id = ?", voteID).Error; err != nil {
+			return nil, err
+		}
+
+		resp := mapVoteToResponse(vote)
+		resp.CaptureID = captured.ID
+		s.notifier.VotesChanged(resp.Position.ID)
+		return &resp, nil
+	}
+
+	if err := s.db.Preload("Mesa").Preload("Candidate.Position").
+		First(&captured, "id = ?", captured.ID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVoteNotFound
+		}
+		return nil, err
+	}
+
+	resp := dto.VoteResponse{
+		MesaID:        captured.MesaID,
+		Mesa:          captured.Mesa.Number,
+		CandidateID:   captured.CandidateID,
+		CandidateName: captured.Candidate.Name,
+		TotalVotes:    captured.Vote,
+		TypeVote:      string(captured.TypeVote),
+		Status:        string(captured.Status),
+		CaptureID:     captured.ID,
+	}
+
+	if captured.Candidate.Position != nil {
+		resp.Position = dto.PositionSimple{
+			ID:           captured.Candidate.Position.ID,
+			Name:         captured.Candidate.Position.Name,
+			TypePosition: string(captured.Candidate.Position.TypePosition),
+		}
+	}
+
+	return &resp, nil
+}
+
+// captureVote: registra una digitación dentro de tx, con la mesa ya bloqueada
+// por el llamador. Devuelve la captura y, si confirmó la de otro digitador, el
+// id del voto creado; actaID se asigna a ese voto cuando viene de un acta.
+func captureVote(tx *gorm.DB, mesaID, candidateID string, tv models.TypeVote, value int, actaID *string, actor AuditActor) (models.VoteCapture, string, error) {
+	captured := models.VoteCapture{
+		MesaID:      mesaID,
+		CandidateID: candidateID,
+		TypeVote:    tv,
+		Vote:        value,
+		UserID:      actor.UserID,
+		Status:      models.CSpending,
+	}
+
+	var open int64
+	if err := tx.Model(&models.Discrepancy{}).
+		Where("mesa_id = ? AND candidate_id = ? AND type_vote = ? AND status = ?", mesaID, candidateID, tv, models.DSopen).
+		Count(&open).Error; err != nil {
+		return captured, "", err
+	}
+	if open > 0 {
+		return captured, "", ErrDiscrepancyPending
+	}
+
+	var pending []models.VoteCapture
+	if err := tx.Where("mesa_id = ? AND candidate_id = ? AND type_vote = ? AND status = ?", mesaID, candidateID, tv, models.CSpending).
+		Order("created_at").
+		Find(&pending).Error; err != nil {
+		return captured, "", err
+	}
+
+	var other *models.VoteCapture
+	for i := range pending {
+		if pending[i].UserID == actor.UserID {
+			return captured, "", ErrCaptureAlreadySubmitted
+		}
+		if other == nil {
+			other = &pending[i]
+		}
+	}
+
+	if other == nil {
+		if err := tx.Create(&captured).Error; err != nil {
+			return captured, "", err
+		}
+		return captured, "", auditCreated(tx, actor, "vote.capture", &models.VoteCapture{}, AuditCapture, captured.ID)
+	}
+
+	if other.Vote == captured.Vote {
+		vote := models.Vote{
+			MesaID:      mesaID,
+			ActaID:      actaID,
+			CandidateID: candidateID,
+			TypeVote:    tv,
+			Vote:        captured.Vote,
+		}
+		if err := tx.Create(&vote).Error; err != nil {
+			return captured, "", err
+		}
+		if err := auditCreated(tx, actor, "vote.create", &models.Vote{}, AuditVote, vote.ID); err != nil {
+			return captured, "", err
+		}
+
+		captured.Status = models.CSconfirmed
+		captured.VoteID = &vote.ID
+		if err := tx.Create(&captured).Error; err != nil {
+			return captured, "", err
+		}
+		if err := auditCreated(tx, actor, "vote.capture", &models.VoteCapture{}, AuditCapture, captured.ID); err != nil {
+			return captured, "", err
+		}
+
+		err := auditUpdatedCapture(tx, actor, "vote.capture_confirm", other.ID, map[string]interface{}{"status": models.CSconfirmed, "vote_id": vote.ID})
+		return captured, vote.ID, err
+	}
+
+	discrepancy := models.Discrepancy{
+		MesaID:      mesaID,
+		CandidateID: candidateID,
+		TypeVote:    tv,
+		Status:      models.DSopen,
+	}
+	if err := tx.Create(&discrepancy).Error; err != nil {
+		return captured, "", err
+	}
+	if err := auditCreated(tx, actor, "discrepancy.open", &models.Discrepancy{}, AuditDiscrepancy, discrepancy.ID); err != nil {
+		return captured, "", err
+	}
+
+	captured.Status = models.CSdiscrepant
+	captured.DiscrepancyID = &discrepancy.ID
+	if err := tx.Create(&captured).Error; err != nil {
+		return captured, "", err
+	}
+	if err := auditCreated(tx, actor, "vote.capture", &models.VoteCapture{}, AuditCapture, captured.ID); err != nil {
+		return captured, "", err
+	}
+
+	err := auditUpdatedCapture(tx, actor, "vote.capture_discrepant", other.ID, map[string]interface{}{"status": models.CSdiscrepant, "discrepancy_id": discrepancy.ID})
+	return captured, "", err
+}
+
+// auditUpdatedCapture: actualiza la captura del otro digitador dejando el cambio auditado
+func auditUpdatedCapture(tx *gorm.DB, actor AuditActor, action, id string, updates map[string]interface{}) error {
+	before, err := auditSnapshot(tx, &models.VoteCapture{}, id)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Model(&models.VoteCapture{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		return err
+	}
+
+	after, err := auditSnapshot(tx, &models.VoteCapture{}, id)
+	if err != nil {
+		return err
+	}
+	return recordAudit(tx, actor, action, AuditCapture, id, before, after)
+}
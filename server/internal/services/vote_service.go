@@ -5,61 +5,106 @@ import (
 	"fmt"
 	"server/internal/dto"
 	"server/internal/models"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type VoteService interface {
-	Create(req dto.CreateVoteRequest, userID, userRole string) (*dto.VoteResponse, error)
-	GetAll() ([]dto.VoteResponse, error)
-	GetByCandidate(candidateID string) ([]dto.VoteResponse, error)
+	Create(req dto.CreateVoteRequest, userID, userRole, ip string) (*dto.VoteResponse, error)
+	GetAll(electionID, userID, userRole string) ([]dto.VoteResponse, error)
+	GetByCandidate(candidateID, userID, userRole string) ([]dto.VoteResponse, error)
+	Update(id string, req dto.UpdateVoteRequest, userID, userRole, ip string) (*dto.VoteResponse, error)
+	Annul(id string, req dto.AnnulVoteRequest, userID, userRole, ip string) (*dto.VoteResponse, error)
+	GetRevisions(id, userID, userRole string) ([]dto.VoteRevisionResponse, error)
 }
 
 type voteServiceImpl struct {
-	db *gorm.DB
+	db          *gorm.DB
+	doubleEntry bool
+	notifier    ResultNotifier
+	locks       MesaLockChecker
 }
 
-func NewVoteService(db *gorm.DB) VoteService {
-	return &voteServiceImpl{db: db}
+// NewVoteService: con doubleEntry activo cada registro queda como captura
+// pendiente hasta que un segundo digitador ingrese el mismo valor. notifier
+// recibe las posiciones afectadas tras cada commit que cambia resultados. Si
+// locks no es nil, Create exige el bloqueo de edición vigente de la mesa.
+func NewVoteService(db *gorm.DB, doubleEntry bool, notifier ResultNotifier, locks MesaLockChecker) VoteService {
+	return &voteServiceImpl{db: db, doubleEntry: doubleEntry, notifier: notifier, locks: locks}
 }
 
-func (s *voteServiceImpl) GetAll() ([]dto.VoteResponse, error) {
+func mapVoteToResponse(v models.Vote) dto.VoteResponse {
+	item := dto.VoteResponse{
+		ID:            v.ID,
+		MesaID:        v.MesaID,
+		Mesa:          v.Mesa.Number,
+		CandidateID:   v.CandidateID,
+		CandidateName: v.Candidate.Name,
+		TotalVotes:    v.Vote,
+		TypeVote:      string(v.TypeVote),
+		Revision:      v.Revision,
+		IsAnnulled:    v.IsAnnulled,
+		Status:        string(models.CSconfirmed),
+	}
+
+	if v.Candidate.Position != nil {
+		item.Position = dto.PositionSimple{
+			ID:           v.Candidate.Position.ID,
+			ElectionID:   v.Candidate.Position.ElectionID,
+			Name:         v.Candidate.Position.Name,
+			TypePosition: string(v.Candidate.Position.TypePosition),
+		}
+	}
+
+	return item
+}
+
+func (s *voteServiceImpl) GetAll(electionID, userID, userRole string) ([]dto.VoteResponse, error) {
+	party, scoped, err := personeroParty(s.db, userID, userRole)
+	if err != nil {
+		return nil, err
+	}
+
 	var votes []models.Vote
 
-	if err := s.db.Preload("Candidate.Position").Find(&votes).Error; err != nil {
+	q := s.db.Preload("Mesa").Preload("Candidate.Position")
+	if electionID != "" {
+		q = q.Where("mesa_id IN (?)", s.db.Model(&models.Mesa{}).Select("id").Where("election_id = ?", electionID))
+	}
+	if scoped {
+		q = q.Where("candidate_id IN (?)", s.db.Model(&models.Candidate{}).Select("id").Where("party = ?", party))
+	}
+	if err := q.Find(&votes).Error; err != nil {
 		return nil, err
 	}
 
 	res := make([]dto.VoteResponse, len(votes))
 
 	for i, v := range votes {
-		item := dto.VoteResponse{
-			ID:            v.ID,
-			Mesa:          v.Mesa,
-			CandidateID:   v.CandidateID,
-			CandidateName: v.Candidate.Name,
-			TotalVotes:    v.Vote,
-			TypeVote:      string(v.TypeVote),
-		}
-
-		if v.Candidate.Position != nil {
-			item.Position = dto.PositionSimple{
-				ID:           v.Candidate.Position.ID,
-				Name:         v.Candidate.Position.Name,
-				TypePosition: string(v.Candidate.Position.TypePosition),
-			}
-		}
-
-		res[i] = item
+		res[i] = mapVoteToResponse(v)
 	}
 
 	return res, nil
 }
 
-func (s *voteServiceImpl) GetByCandidate(candidateID string) ([]dto.VoteResponse, error) {
+func (s *voteServiceImpl) GetByCandidate(candidateID, userID, userRole string) ([]dto.VoteResponse, error) {
+	var candidate models.Candidate
+	if err := s.db.Select("id", "party").First(&candidate, "id = ?", candidateID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCandidateNotFound
+		}
+		return nil, err
+	}
+	if err := ensureCandidateParty(s.db, &candidate, userID, userRole); err != nil {
+		return nil, err
+	}
+
 	var votes []models.Vote
 
-	if err := s.db.Preload("Candidate.Position").
+	if err := s.db.Preload("Mesa").Preload("Candidate.Position").
 		Where("candidate_id = ?", candidateID).
 		Find(&votes).Error; err != nil {
 		return nil, err
@@ -68,42 +113,27 @@ func (s *voteServiceImpl) GetByCandidate(candidateID string) ([]dto.VoteResponse
 	res := make([]dto.VoteResponse, len(votes))
 
 	for i, v := range votes {
-		item := dto.VoteResponse{
-			ID:            v.ID,
-			Mesa:          v.Mesa,
-			CandidateID:   v.CandidateID,
-			CandidateName: v.Candidate.Name,
-			TotalVotes:    v.Vote,
-			TypeVote:      string(v.TypeVote),
-		}
-
-		if v.Candidate.Position != nil {
-			item.Position = dto.PositionSimple{
-				ID:           v.Candidate.Position.ID,
-				Name:         v.Candidate.Position.Name,
-				TypePosition: string(v.Candidate.Position.TypePosition),
-			}
-		}
-
-		res[i] = item
+		res[i] = mapVoteToResponse(v)
 	}
 
 	return res, nil
 }
 
-func (s *voteServiceImpl) Create(req dto.CreateVoteRequest, userID, userRole string) (*dto.VoteResponse, error) {
-	if userRole != "ADMIN" {
-		return nil, ErrUnauthorized
-	}
-
-	if req.Mesa == "" {
-		return nil, fmt.Errorf("nombre de la mesa obligatorio")
+func (s *voteServiceImpl) Create(req dto.CreateVoteRequest, userID, userRole, ip string) (*dto.VoteResponse, error) {
+	if req.MesaID == "" {
+		return nil, fmt.Errorf("mesa obligatoria")
 	}
 
 	if req.TotalVotes < 0 {
 		return nil, ErrInvalidVoteCount
 	}
 
+	if s.locks != nil {
+		if err := s.locks.Check(req.MesaID, userID, req.LockToken); err != nil {
+			return nil, err
+		}
+	}
+
 	validTypes := map[models.TypeVote]bool{
 		models.TVpersonnel: true,
 		models.TVpublic:    true,
@@ -115,56 +145,235 @@ func (s *voteServiceImpl) Create(req dto.CreateVoteRequest, userID, userRole str
 		return nil, fmt.Errorf("typeVote inválido: debe ser DOCENTES o PUBLICO")
 	}
 
+	actor := AuditActor{userID, userRole, ip}
+
+	if s.doubleEntry {
+		return s.capture(req, tv, actor)
+	}
+
+	var vote models.Vote
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockVoteTarget(tx, req.MesaID, req.CandidateID, tv); err != nil {
+			return err
+		}
+
+		vote = models.Vote{
+			MesaID:      req.MesaID,
+			CandidateID: req.CandidateID,
+			TypeVote:    tv,
+			Vote:        req.TotalVotes,
+		}
+		if err := tx.Create(&vote).Error; err != nil {
+			return err
+		}
+		return auditCreated(tx, actor, "vote.create", &models.Vote{}, AuditVote, vote.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Preload("Mesa").Preload("Candidate.Position").
+		First(&vote, "id = ?", vote.ID).Error; err != nil {
+		return nil, err
+	}
+
+	resp := mapVoteToResponse(vote)
+	s.notifier.VotesChanged(resp.Position.ID)
+	return &resp, nil
+}
+
+// lockVoteTarget: bloquea la mesa y comprueba que esté abierta, que el
+// candidato exista y que no haya un voto vigente para la misma combinación
+func lockVoteTarget(tx *gorm.DB, mesaID, candidateID string, tv models.TypeVote) error {
+	var mesa models.Mesa
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&mesa, "id = ?", mesaID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrMesaNotFound
+		}
+		return err
+	}
+
+	if err := ensureElectionPhase(tx, mesa.ElectionID, electionVotingPhases); err != nil {
+		return err
+	}
+
+	if mesa.Status != models.MSopen {
+		return ErrMesaNotOpen
+	}
+
 	var candidate models.Candidate
-	if err := s.db.First(&candidate, "id = ?", req.CandidateID).Error; err != nil {
+	if err := tx.Preload("Position").First(&candidate, "id = ?", candidateID).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrCandidateNotFound
+			return ErrCandidateNotFound
 		}
-		return nil, err
+		return err
+	}
+
+	if candidate.Position == nil || candidate.Position.ElectionID != mesa.ElectionID {
+		return ErrElectionMismatch
 	}
 
 	var existingVote models.Vote
-	err := s.db.Where("mesa = ? AND candidate_id = ?", req.Mesa, req.CandidateID).
+	err := tx.Where("mesa_id = ? AND candidate_id = ? AND type_vote = ? AND is_annulled = false", mesaID, candidateID, tv).
 		First(&existingVote).Error
 
 	if err == nil {
-		return nil, ErrDuplicateVote
+		return ErrDuplicateVote
 	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+// Update: corrige el valor de un voto guardando el valor anterior en vote_revisions
+func (s *voteServiceImpl) Update(id string, req dto.UpdateVoteRequest, userID, userRole, ip string) (*dto.VoteResponse, error) {
+	if req.TotalVotes == nil {
+		return nil, fmt.Errorf("totalVotes obligatorio")
+	}
+	if *req.TotalVotes < 0 {
+		return nil, ErrInvalidVoteCount
+	}
+
+	return s.revise(id, models.RAcorrection, *req.TotalVotes, req.Reason, AuditActor{userID, userRole, ip})
+}
+
+// Annul: anula un voto; deja de contar en resultados y permite volver a registrarlo
+func (s *voteServiceImpl) Annul(id string, req dto.AnnulVoteRequest, userID, userRole, ip string) (*dto.VoteResponse, error) {
+	return s.revise(id, models.RAannulment, 0, req.Reason, AuditActor{userID, userRole, ip})
+}
+
+func (s *voteServiceImpl) revise(id string, action models.RevisionAction, newValue int, reason string, actor AuditActor) (*dto.VoteResponse, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrReasonRequired
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var vote models.Vote
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Preload("Mesa").
+			First(&vote, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrVoteNotFound
+			}
+			return err
+		}
+
+		if vote.IsAnnulled {
+			return ErrVoteAnnulled
+		}
+		if err := ensureElectionPhase(tx, vote.Mesa.ElectionID, electionCorrectionPhases); err != nil {
+			return err
+		}
+		if vote.Mesa.Status == models.MSannulled {
+			return fmt.Errorf("%w: la mesa %s está anulada", ErrUnauthorizedAction, vote.Mesa.Number)
+		}
+		if action == models.RAcorrection && vote.Vote == newValue {
+			return ErrVoteUnchanged
+		}
+
+		revision := models.VoteRevision{
+			VoteID:       vote.ID,
+			Revision:     vote.Revision,
+			PreviousVote: vote.Vote,
+			NewVote:      newValue,
+			Action:       action,
+			Reason:       reason,
+			UserID:       actor.UserID,
+		}
+		if err := tx.Create(&revision).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{"revision": vote.Revision + 1}
+		if action == models.RAannulment {
+			updates["is_annulled"] = true
+		} else {
+			updates["vote"] = newValue
+		}
+
+		before, err := auditSnapshot(tx, &models.Vote{}, vote.ID)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&vote).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		after, err := auditSnapshot(tx, &models.Vote{}, vote.ID)
+		if err != nil {
+			return err
+		}
+
+		auditAction, eventType := "vote.correct", WebhookVoteCorrected
+		if action == models.RAannulment {
+			auditAction, eventType = "vote.annul", WebhookVoteAnnulled
+		}
+		if err := recordAudit(tx, actor, auditAction, AuditVote, vote.ID, before, after); err != nil {
+			return err
+		}
+
+		return enqueueWebhookEvent(tx, eventType, map[string]interface{}{
+			"voteId":       vote.ID,
+			"mesaId":       vote.MesaID,
+			"electionId":   vote.Mesa.ElectionID,
+			"candidateId":  vote.CandidateID,
+			"typeVote":     vote.TypeVote,
+			"previousVote": revision.PreviousVote,
+			"newVote":      revision.NewVote,
+			"revision":     vote.Revision + 1,
+			"reason":       reason,
+		})
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	vote := models.Vote{
-		Mesa:        req.Mesa,
-		CandidateID: req.CandidateID,
-		TypeVote:    tv,
-		Vote:        req.TotalVotes,
+	var vote models.Vote
+	if err := s.db.Preload("Mesa").Preload("Candidate.Position").
+		First(&vote, "id = ?", id).Error; err != nil {
+		return nil, err
 	}
 
-	if err := s.db.Create(&vote).Error; err != nil {
+	resp := mapVoteToResponse(vote)
+	s.notifier.VotesChanged(resp.Position.ID)
+	return &resp, nil
+}
+
+func (s *voteServiceImpl) GetRevisions(id, userID, userRole string) ([]dto.VoteRevisionResponse, error) {
+	var vote models.Vote
+	if err := s.db.Preload("Candidate").First(&vote, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrVoteNotFound
+		}
 		return nil, err
 	}
 
-	if err := s.db.Preload("Candidate.Position").
-		First(&vote, "id = ?", vote.ID).Error; err != nil {
+	if err := ensureCandidateParty(s.db, &vote.Candidate, userID, userRole); err != nil {
 		return nil, err
 	}
 
-	resp := dto.VoteResponse{
-		ID:            vote.ID,
-		Mesa:          vote.Mesa,
-		CandidateID:   vote.CandidateID,
-		CandidateName: vote.Candidate.Name,
-		TotalVotes:    vote.Vote,
-		TypeVote:      string(vote.TypeVote),
+	var revisions []models.VoteRevision
+	if err := s.db.Where("vote_id = ?", id).Order("revision").Find(&revisions).Error; err != nil {
+		return nil, err
 	}
 
-	if vote.Candidate.Position != nil {
-		resp.Position = dto.PositionSimple{
-			ID:           vote.Candidate.Position.ID,
-			Name:         vote.Candidate.Position.Name,
-			TypePosition: string(vote.Candidate.Position.TypePosition),
+	res := make([]dto.VoteRevisionResponse, len(revisions))
+	for i, r := range revisions {
+		res[i] = dto.VoteRevisionResponse{
+			ID:           r.ID,
+			VoteID:       r.VoteID,
+			Revision:     r.Revision,
+			PreviousVote: r.PreviousVote,
+			NewVote:      r.NewVote,
+			Action:       string(r.Action),
+			Reason:       r.Reason,
+			UserID:       r.UserID,
+			CreatedAt:    r.CreatedAt.Format(time.RFC3339),
 		}
 	}
-
-	return &resp, nil
+	return res, nil
 }
@@ -0,0 +1,258 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"server/internal/dto"
+	"server/internal/models"
+	"server/pkgs/logger"
+	"server/pkgs/security"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Eventos que se pueden suscribir
+const (
+	WebhookMesaCounted       = "mesa.counted"
+	WebhookVoteCorrected     = "vote.corrected"
+	WebhookVoteAnnulled      = "vote.annulled"
+	WebhookElectionCertified = "election.certified"
+	WebhookPing              = "ping"
+)
+
+var webhookEventTypes = []string{
+	WebhookMesaCounted,
+	WebhookVoteCorrected,
+	WebhookVoteAnnulled,
+	WebhookElectionCertified,
+}
+
+const (
+	// webhookMaxErrorBody: bytes de la respuesta del receptor guardados como error
+	webhookMaxErrorBody = 512
+	webhookUserAgent    = "conteovotos-webhooks/1"
+)
+
+// WebhookDispatcherConfig: ver los campos Webhook* de config.AppConfig
+type WebhookDispatcherConfig struct {
+	Timeout      time.Duration
+	PollInterval time.Duration
+	RetryBase    time.Duration
+	RetryMax     time.Duration
+	MaxAttempts  int
+	AllowPrivate bool
+}
+
+// enqueueWebhookEvent: crea una entrega por cada webhook activo suscrito al
+// evento, dentro de la transacción del cambio que lo origina. Así el evento
+// existe si y solo si el cambio se confirmó, y la petición HTTP no espera a
+// ningún receptor.
+func enqueueWebhookEvent(tx *gorm.DB, eventType string, data interface{}) error {
+	var hooks []models.Webhook
+	if err := tx.Where("is_active = ?", true).Find(&hooks).Error; err != nil {
+		return err
+	}
+
+	event := newWebhookEvent(eventType, data)
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	var deliveries []models.WebhookDelivery
+	for _, hook := range hooks {
+		if !webhookSubscribed(hook, eventType) {
+			continue
+		}
+		deliveries = append(deliveries, models.WebhookDelivery{
+			WebhookID:     hook.ID,
+			EventID:       event.ID,
+			EventType:     eventType,
+			Payload:       string(payload),
+			Status:        models.WDpending,
+			NextAttemptAt: time.Now(),
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return tx.Create(&deliveries).Error
+}
+
+func newWebhookEvent(eventType string, data interface{}) dto.WebhookEvent {
+	return dto.WebhookEvent{
+		ID:        uuid.NewString(),
+		Type:      eventType,
+		CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		Data:      data,
+	}
+}
+
+func webhookSubscribed(hook models.Webhook, eventType string) bool {
+	if hook.Events == "" {
+		return true
+	}
+	for _, e := range strings.Split(hook.Events, ",") {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDispatcher entrega la cola de webhook_deliveries. Reclama de a una
+// entrega con FOR UPDATE SKIP LOCKED y aplaza su próximo intento justo antes de
+// enviarla, de modo que varias instancias del servidor no repiten entregas y
+// una caída a mitad de envío solo provoca un reintento (entrega al menos una vez).
+type WebhookDispatcher struct {
+	db     *gorm.DB
+	cfg    WebhookDispatcherConfig
+	client *http.Client
+}
+
+func NewWebhookDispatcher(db *gorm.DB, cfg WebhookDispatcherConfig) *WebhookDispatcher {
+	d := &WebhookDispatcher{
+		db:     db,
+		cfg:    cfg,
+		client: newWebhookClient(cfg.Timeout, cfg.AllowPrivate),
+	}
+	go d.run()
+	return d
+}
+
+func (d *WebhookDispatcher) run() {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		for {
+			delivery, err := d.claim()
+			if err != nil {
+				logger.Log.Errorf("❌ Webhooks: no se pudo procesar la cola: %v", err)
+				break
+			}
+			if delivery == nil {
+				break
+			}
+			d.deliver(delivery)
+		}
+	}
+}
+
+// claim: reclama la entrega vencida más antigua; nil si no hay ninguna
+func (d *WebhookDispatcher) claim() (*models.WebhookDelivery, error) {
+	var claimed []models.WebhookDelivery
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Preload("Webhook").
+			Where("status = ? AND next_attempt_at <= ?", models.WDpending, time.Now()).
+			Order("next_attempt_at").
+			Limit(1).
+			Find(&claimed).Error; err != nil {
+			return err
+		}
+		if len(claimed) == 0 {
+			return nil
+		}
+
+		// lease: si el proceso cae durante el envío, la entrega vuelve a vencer
+		lease := time.Now().Add(2 * d.cfg.Timeout)
+		return tx.Model(&models.WebhookDelivery{}).
+			Where("id = ?", claimed[0].ID).
+			Update("next_attempt_at", lease).Error
+	})
+	if err != nil || len(claimed) == 0 {
+		return nil, err
+	}
+	return &claimed[0], nil
+}
+
+// deliver: un intento de entrega; programa el siguiente con backoff o la
+// pasa a DEAD al agotar los intentos
+func (d *WebhookDispatcher) deliver(delivery *models.WebhookDelivery) {
+	if !delivery.Webhook.IsActive {
+		d.update(delivery.ID, map[string]interface{}{
+			"status":     models.WDdead,
+			"last_error": "webhook desactivado",
+		})
+		return
+	}
+
+	attempts := delivery.Attempts + 1
+	statusCode, err := d.send(delivery.Webhook, delivery.ID, delivery.EventType, []byte(delivery.Payload))
+	updates := map[string]interface{}{"attempts": attempts, "last_status_code": statusCode}
+
+	switch {
+	case err == nil:
+		updates["status"] = models.WDdelivered
+		updates["delivered_at"] = time.Now()
+		updates["last_error"] = nil
+	case attempts >= d.cfg.MaxAttempts:
+		updates["status"] = models.WDdead
+		updates["last_error"] = err.Error()
+		logger.Log.Warnf("⚠️ Webhook %s: entrega %s descartada tras %d intentos: %v", delivery.WebhookID, delivery.ID, attempts, err)
+	default:
+		updates["last_error"] = err.Error()
+		updates["next_attempt_at"] = time.Now().Add(d.backoff(attempts))
+	}
+	d.update(delivery.ID, updates)
+}
+
+func (d *WebhookDispatcher) update(id string, updates map[string]interface{}) {
+	if err := d.db.Model(&models.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(updates).Error; err != nil {
+		logger.Log.Errorf("❌ Webhooks: no se pudo actualizar la entrega %s: %v", id, err)
+	}
+}
+
+// backoff: RetryBase · 2^(intentos-1), con tope RetryMax
+func (d *WebhookDispatcher) backoff(attempts int) time.Duration {
+	wait := d.cfg.RetryBase
+	for i := 1; i < attempts && wait < d.cfg.RetryMax; i++ {
+		wait *= 2
+	}
+	if wait > d.cfg.RetryMax {
+		wait = d.cfg.RetryMax
+	}
+	return wait
+}
+
+// send: POST firmado; cualquier respuesta fuera de 2xx cuenta como fallo
+func (d *WebhookDispatcher) send(hook models.Webhook, deliveryID, eventType string, payload []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", webhookUserAgent)
+	req.Header.Set("X-Webhook-Id", hook.ID)
+	req.Header.Set("X-Webhook-Delivery", deliveryID)
+	req.Header.Set("X-Webhook-Event", eventType)
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", security.WebhookSignature(hook.Secret, timestamp, payload))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, webhookMaxErrorBody))
+		return res.StatusCode, fmt.Errorf("el receptor respondió %d: %s", res.StatusCode, strings.TrimSpace(string(body)))
+	}
+	io.Copy(io.Discard, io.LimitReader(res.Body, webhookMaxErrorBody))
+	return res.StatusCode, nil
+}
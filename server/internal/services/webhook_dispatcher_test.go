@@ -0,0 +1,164 @@
+package services
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"server/internal/models"
+	"server/pkgs/security"
+)
+
+// testDispatcher: sin goroutine de la cola, solo para probar send
+func testDispatcher(allowPrivate bool) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		cfg:    WebhookDispatcherConfig{Timeout: 2 * time.Second, AllowPrivate: allowPrivate},
+		client: newWebhookClient(2*time.Second, allowPrivate),
+	}
+}
+
+func TestWebhookSendSignature(t *testing.T) {
+	payload := []byte(`{"id":"evt-1","type":"mesa.counted","data":{"mesaId":"m1"}}`)
+
+	var got *http.Request
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	hook := models.Webhook{Base: models.Base{ID: "hook-1"}, URL: srv.URL, Secret: "whsec_test"}
+	// el servidor de test escucha en loopback: hace falta AllowPrivate
+	status, err := testDispatcher(true).send(hook, "delivery-1", WebhookMesaCounted, payload)
+	if err != nil || status != http.StatusNoContent {
+		t.Fatalf("send: %d %v", status, err)
+	}
+
+	timestamp, err := strconv.ParseInt(got.Header.Get("X-Webhook-Timestamp"), 10, 64)
+	if err != nil {
+		t.Fatalf("timestamp: %v", err)
+	}
+	if time.Since(time.Unix(timestamp, 0)) > time.Minute {
+		t.Errorf("timestamp %d demasiado antiguo", timestamp)
+	}
+
+	// el receptor recalcula la firma con el cuerpo tal como lo recibió
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      []byte
+		valid     bool
+	}{
+		{"firma correcta", hook.Secret, timestamp, body, true},
+		{"secreto de otro webhook", "whsec_other", timestamp, body, false},
+		{"timestamp reemplazado", hook.Secret, timestamp - 3600, body, false},
+		{"cuerpo alterado", hook.Secret, timestamp, append(append([]byte{}, body...), ' '), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok := security.WebhookSignature(tt.secret, tt.timestamp, tt.body) == got.Header.Get("X-Webhook-Signature")
+			if ok != tt.valid {
+				t.Errorf("firma válida = %v, want %v", ok, tt.valid)
+			}
+		})
+	}
+
+	for header, want := range map[string]string{
+		"X-Webhook-Id":       hook.ID,
+		"X-Webhook-Delivery": "delivery-1",
+		"X-Webhook-Event":    WebhookMesaCounted,
+		"Content-Type":       "application/json",
+	} {
+		if v := got.Header.Get(header); v != want {
+			t.Errorf("%s = %q, want %q", header, v, want)
+		}
+	}
+}
+
+func TestWebhookSendErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "caído", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	hook := models.Webhook{Base: models.Base{ID: "hook-1"}, URL: srv.URL, Secret: "whsec_test"}
+	status, err := testDispatcher(true).send(hook, "delivery-1", WebhookPing, []byte(`{}`))
+	if status != http.StatusBadGateway || err == nil {
+		t.Errorf("got %d %v, want 502 con error", status, err)
+	}
+}
+
+func TestWebhookBlockedTargets(t *testing.T) {
+	tests := []struct {
+		addr    string
+		blocked bool
+	}{
+		{"127.0.0.1", true},
+		{"::1", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"192.168.1.10", true},
+		{"169.254.169.254", true},
+		{"fe80::1", true},
+		{"fc00::1", true},
+		{"100.64.0.1", true},
+		{"0.0.0.0", true},
+		{"::", true},
+		{"224.0.0.1", true},
+		{"::ffff:127.0.0.1", true},
+		{"::ffff:10.0.0.1", true},
+		{"8.8.8.8", false},
+		{"172.32.0.1", false},
+		{"2001:4860:4860::8888", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := webhookBlockedAddr(netip.MustParseAddr(tt.addr)); got != tt.blocked {
+				t.Errorf("bloqueada = %v, want %v", got, tt.blocked)
+			}
+		})
+	}
+
+	for host, blocked := range map[string]bool{
+		"localhost":         true,
+		"api.localhost":     true,
+		"LOCALHOST.":        true,
+		"127.0.0.1":         true,
+		"::1":               true,
+		"hooks.example.com": false,
+		"93.184.216.34":     false,
+		"localhost.example": false,
+	} {
+		if got := webhookHostBlocked(host); got != blocked {
+			t.Errorf("webhookHostBlocked(%q) = %v, want %v", host, got, blocked)
+		}
+	}
+}
+
+func TestWebhookSendBlockedAtDial(t *testing.T) {
+	var hit atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit.Store(true)
+	}))
+	defer srv.Close()
+
+	// la URL pasa la validación sintáctica; el bloqueo ocurre al conectar
+	hook := models.Webhook{Base: models.Base{ID: "hook-1"}, URL: srv.URL, Secret: "whsec_test"}
+	_, err := testDispatcher(false).send(hook, "delivery-1", WebhookPing, []byte(`{}`))
+	if !errors.Is(err, ErrWebhookTargetBlocked) {
+		t.Errorf("got %v, want ErrWebhookTargetBlocked", err)
+	}
+	if hit.Load() {
+		t.Error("la petición llegó al receptor")
+	}
+}
@@ -0,0 +1,339 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"server/internal/dto"
+	"server/internal/models"
+	"server/pkgs/security"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// webhookDeliveryPage: entregas devueltas como máximo por consulta
+const webhookDeliveryPage = 200
+
+type WebhookService interface {
+	GetAll() ([]dto.WebhookResponse, error)
+	GetOne(id string) (*dto.WebhookResponse, error)
+	Create(req dto.CreateWebhookRequest, userID string) (*dto.WebhookResponse, error)
+	Update(id string, req dto.UpdateWebhookRequest) (*dto.WebhookResponse, error)
+	Delete(id string) error
+	Test(id string) (*dto.WebhookTestResponse, error)
+	GetDeliveries(id, status string) ([]dto.WebhookDeliveryResponse, error)
+	GetDeadLetters() ([]dto.WebhookDeliveryResponse, error)
+	RetryDelivery(id string) (*dto.WebhookDeliveryResponse, error)
+}
+
+type webhookServiceImpl struct {
+	db         *gorm.DB
+	dispatcher *WebhookDispatcher
+}
+
+func NewWebhookService(db *gorm.DB, dispatcher *WebhookDispatcher) WebhookService {
+	return &webhookServiceImpl{db: db, dispatcher: dispatcher}
+}
+
+func mapWebhookToResponse(w models.Webhook) dto.WebhookResponse {
+	events := []string{}
+	if w.Events != "" {
+		events = strings.Split(w.Events, ",")
+	}
+	return dto.WebhookResponse{
+		ID:        w.ID,
+		Name:      w.Name,
+		URL:       w.URL,
+		Events:    events,
+		IsActive:  w.IsActive,
+		CreatedAt: w.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: w.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func mapWebhookDeliveryToResponse(d models.WebhookDelivery) dto.WebhookDeliveryResponse {
+	res := dto.WebhookDeliveryResponse{
+		ID:             d.ID,
+		WebhookID:      d.WebhookID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        json.RawMessage(d.Payload),
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		NextAttemptAt:  d.NextAttemptAt.Format(time.RFC3339),
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		CreatedAt:      d.CreatedAt.Format(time.RFC3339),
+	}
+	if d.DeliveredAt != nil {
+		deliveredAt := d.DeliveredAt.Format(time.RFC3339)
+		res.DeliveredAt = &deliveredAt
+	}
+	return res
+}
+
+// normalizeWebhookURL: solo http(s) con host y, salvo allowPrivate, que no
+// sea una dirección interna evidente
+func normalizeWebhookURL(raw string, allowPrivate bool) (string, error) {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", ErrInvalidWebhookURL
+	}
+	if !allowPrivate && webhookHostBlocked(u.Hostname()) {
+		return "", ErrWebhookTargetBlocked
+	}
+	return raw, nil
+}
+
+// normalizeWebhookEvents: valida y deduplica; lista vacía = todos los eventos
+func normalizeWebhookEvents(events []string) (string, error) {
+	seen := make(map[string]bool, len(events))
+	var list []string
+	for _, e := range events {
+		e = strings.TrimSpace(e)
+		if !containsEvent(webhookEventTypes, e) {
+			return "", fmt.Errorf("%w: %q", ErrInvalidWebhookEvent, e)
+		}
+		if !seen[e] {
+			seen[e] = true
+			list = append(list, e)
+		}
+	}
+	return strings.Join(list, ","), nil
+}
+
+func containsEvent(list []string, e string) bool {
+	for _, v := range list {
+		if v == e {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *webhookServiceImpl) find(id string) (*models.Webhook, error) {
+	var hook models.Webhook
+	if err := s.db.First(&hook, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookNotFound
+		}
+		return nil, err
+	}
+	return &hook, nil
+}
+
+func (s *webhookServiceImpl) GetAll() ([]dto.WebhookResponse, error) {
+	var hooks []models.Webhook
+	if err := s.db.Order("created_at").Find(&hooks).Error; err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.WebhookResponse, len(hooks))
+	for i, h := range hooks {
+		res[i] = mapWebhookToResponse(h)
+	}
+	return res, nil
+}
+
+func (s *webhookServiceImpl) GetOne(id string) (*dto.WebhookResponse, error) {
+	hook, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+	res := mapWebhookToResponse(*hook)
+	return &res, nil
+}
+
+// Create: el secreto de firma se genera aquí y solo se muestra en esta respuesta
+func (s *webhookServiceImpl) Create(req dto.CreateWebhookRequest, userID string) (*dto.WebhookResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, fmt.Errorf("el nombre del webhook es obligatorio")
+	}
+	target, err := normalizeWebhookURL(req.URL, s.dispatcher.cfg.AllowPrivate)
+	if err != nil {
+		return nil, err
+	}
+	events, err := normalizeWebhookEvents(req.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := security.GenerateRandomToken(32)
+	if secret == "" {
+		return nil, fmt.Errorf("no se pudo generar el secreto del webhook")
+	}
+
+	hook := models.Webhook{
+		Name:      name,
+		URL:       target,
+		Secret:    secret,
+		Events:    events,
+		IsActive:  true,
+		CreatedBy: userID,
+	}
+	if err := s.db.Create(&hook).Error; err != nil {
+		return nil, err
+	}
+	// IsActive tiene default en la BD: el false explícito se guarda aparte
+	if req.IsActive != nil && !*req.IsActive {
+		if err := s.db.Model(&hook).Update("is_active", false).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	res := mapWebhookToResponse(hook)
+	res.Secret = secret
+	return &res, nil
+}
+
+func (s *webhookServiceImpl) Update(id string, req dto.UpdateWebhookRequest) (*dto.WebhookResponse, error) {
+	hook, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, fmt.Errorf("el nombre del webhook es obligatorio")
+		}
+		updates["name"] = name
+	}
+	if req.URL != nil {
+		target, err := normalizeWebhookURL(*req.URL, s.dispatcher.cfg.AllowPrivate)
+		if err != nil {
+			return nil, err
+		}
+		updates["url"] = target
+	}
+	if req.Events != nil {
+		events, err := normalizeWebhookEvents(req.Events)
+		if err != nil {
+			return nil, err
+		}
+		updates["events"] = events
+	}
+	if req.IsActive != nil {
+		updates["is_active"] = *req.IsActive
+	}
+
+	if len(updates) > 0 {
+		if err := s.db.Model(hook).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return s.GetOne(id)
+}
+
+// Delete: las entregas pendientes se borran en cascada
+func (s *webhookServiceImpl) Delete(id string) error {
+	result := s.db.Delete(&models.Webhook{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// Test: envía un evento ping firmado en el momento, aunque el webhook esté
+// desactivado, para comprobar URL y secreto sin esperar a la cola
+func (s *webhookServiceImpl) Test(id string) (*dto.WebhookTestResponse, error) {
+	hook, err := s.find(id)
+	if err != nil {
+		return nil, err
+	}
+
+	event := newWebhookEvent(WebhookPing, map[string]string{
+		"webhookId": hook.ID,
+		"name":      hook.Name,
+	})
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	statusCode, err := s.dispatcher.send(*hook, event.ID, WebhookPing, payload)
+	res := &dto.WebhookTestResponse{
+		Delivered:  err == nil,
+		StatusCode: statusCode,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		res.Error = err.Error()
+	}
+	return res, nil
+}
+
+func (s *webhookServiceImpl) GetDeliveries(id, status string) ([]dto.WebhookDeliveryResponse, error) {
+	if _, err := s.find(id); err != nil {
+		return nil, err
+	}
+
+	q := s.db.Where("webhook_id = ?", id)
+	if status != "" {
+		q = q.Where("status = ?", strings.ToUpper(status))
+	}
+	return s.listDeliveries(q)
+}
+
+// GetDeadLetters: entregas que agotaron los reintentos, de todos los webhooks
+func (s *webhookServiceImpl) GetDeadLetters() ([]dto.WebhookDeliveryResponse, error) {
+	return s.listDeliveries(s.db.Where("status = ?", models.WDdead))
+}
+
+func (s *webhookServiceImpl) listDeliveries(q *gorm.DB) ([]dto.WebhookDeliveryResponse, error) {
+	var deliveries []models.WebhookDelivery
+	if err := q.Order("created_at DESC").Limit(webhookDeliveryPage).Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+
+	res := make([]dto.WebhookDeliveryResponse, len(deliveries))
+	for i, d := range deliveries {
+		res[i] = mapWebhookDeliveryToResponse(d)
+	}
+	return res, nil
+}
+
+// RetryDelivery: devuelve a la cola una entrega DEAD con los intentos a cero
+func (s *webhookServiceImpl) RetryDelivery(id string) (*dto.WebhookDeliveryResponse, error) {
+	var delivery models.WebhookDelivery
+	if err := s.db.First(&delivery, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookDeliveryNotFound
+		}
+		return nil, err
+	}
+	if delivery.Status != models.WDdead {
+		return nil, ErrWebhookDeliveryNotDead
+	}
+
+	result := s.db.Model(&delivery).
+		Where("status = ?", models.WDdead).
+		Updates(map[string]interface{}{
+			"status":          models.WDpending,
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrWebhookDeliveryNotDead
+	}
+
+	if err := s.db.First(&delivery, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	res := mapWebhookDeliveryToResponse(delivery)
+	return &res, nil
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// webhookCGNAT: espacio compartido de operadores (RFC 6598), interno en la práctica
+var webhookCGNAT = netip.MustParsePrefix("100.64.0.0/10")
+
+// webhookBlockedAddr: direcciones que un webhook no puede alcanzar (loopback,
+// privadas, link-local, sin especificar y multicast)
+func webhookBlockedAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() ||
+		addr.IsUnspecified() ||
+		webhookCGNAT.Contains(addr)
+}
+
+// webhookHostBlocked: chequeo temprano al guardar el webhook, solo para hosts
+// que ya son una IP o localhost. El que cuenta es webhookDialControl, porque
+// un nombre puede resolver a otra dirección en cada entrega.
+func webhookHostBlocked(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	addr, err := netip.ParseAddr(host)
+	return err == nil && webhookBlockedAddr(addr)
+}
+
+// webhookDialControl: se ejecuta con la IP ya resuelta justo antes de cada
+// conexión, también en las redirecciones
+func webhookDialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	if webhookBlockedAddr(addr) {
+		return fmt.Errorf("%w: %s", ErrWebhookTargetBlocked, addr)
+	}
+	return nil
+}
+
+// newWebhookClient: sin proxy del entorno, para que el Control vea la IP real
+// del receptor; allowPrivate desactiva el filtro (receptores en la red interna)
+func newWebhookClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = webhookDialControl
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
@@ -2,47 +2,141 @@
 package middleware
 
 import (
-	"os"
 	"strings"
 
+	"server/internal/config"
+	"server/pkgs/security"
+
 	"github.com/gofiber/fiber/v3"
-	"github.com/golang-jwt/jwt/v5"
 )
 
+// WebSocketAuthProtocol: subprotocolo con el que el cliente WebSocket envía
+// el token; el servidor debe aceptarlo en el handshake
+const WebSocketAuthProtocol = "bearer"
+
 func AuthRequired() fiber.Handler {
+	return authenticate(false, bearerToken)
+}
+
+// AuthAllowPasswordChange acepta también tokens con MustChangePassword; solo
+// para las rutas que el usuario necesita antes de fijar su contraseña
+func AuthAllowPasswordChange() fiber.Handler {
+	return authenticate(true, bearerToken)
+}
+
+// AuthWebSocket: como AuthRequired, pero el navegador no puede enviar
+// Authorization al abrir un WebSocket, así que acepta también el token como
+// subprotocolo: new WebSocket(url, ["bearer", token])
+func AuthWebSocket() fiber.Handler {
+	return authenticate(false, webSocketToken)
+}
+
+// bearerToken: token de la cabecera Authorization, o el mensaje de error
+func bearerToken(c fiber.Ctx) (string, string) {
+	authHeader := c.Get("Authorization")
+	if authHeader == "" {
+		return "", "Token no proporcionado"
+	}
+
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+	if tokenString == authHeader {
+		return "", "Formato de token inválido"
+	}
+	return tokenString, ""
+}
+
+// WebSocketAccessToken: token con el que se abrió el WebSocket; el handler lo
+// conserva para volver a validarlo mientras la conexión siga abierta
+func WebSocketAccessToken(c fiber.Ctx) string {
+	token, _ := webSocketToken(c)
+	return token
+}
+
+func webSocketToken(c fiber.Ctx) (string, string) {
+	if c.Get("Authorization") != "" {
+		return bearerToken(c)
+	}
+
+	protocols := strings.Split(c.Get("Sec-WebSocket-Protocol"), ",")
+	if len(protocols) == 2 && strings.TrimSpace(protocols[0]) == WebSocketAuthProtocol {
+		if token := strings.TrimSpace(protocols[1]); token != "" {
+			return token, ""
+		}
+	}
+	return "", "Token no proporcionado"
+}
+
+func authenticate(allowPasswordChange bool, extract func(fiber.Ctx) (string, string)) fiber.Handler {
 	return func(c fiber.Ctx) error {
-		authHeader := c.Get("Authorization")
-		if authHeader == "" {
+		tokenString, message := extract(c)
+		if tokenString == "" {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"data":    nil,
-				"message": "Token no proporcionado",
+				"message": message,
+				"status":  fiber.StatusUnauthorized,
+			})
+		}
+
+		jwtSvc, err := config.GetJWTService()
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"data":    nil,
+				"message": "Error interno: JWT no configurado",
+				"status":  fiber.StatusInternalServerError,
+			})
+		}
+
+		claims, err := jwtSvc.ValidateToken(tokenString)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"data":    nil,
+				"message": "Token inválido o expirado",
 				"status":  fiber.StatusUnauthorized,
 			})
 		}
 
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		if tokenString == authHeader {
+		if claims.MustChangePassword && !allowPasswordChange {
+			return passwordChangeRequired(c)
+		}
+
+		setClaimsLocals(c, claims)
+		return c.Next()
+	}
+}
+
+// AuthOrMFAEnrollment acepta un access token o el challenge de enrolamiento
+// 2FA obligatorio que devuelve Signin; en el segundo caso marca la petición
+// con "mfaEnrollment" y no expone rol, así que no sirve para otras rutas.
+func AuthOrMFAEnrollment() fiber.Handler {
+	return func(c fiber.Ctx) error {
+		tokenString := strings.TrimPrefix(c.Get("Authorization"), "Bearer ")
+		if tokenString == "" || tokenString == c.Get("Authorization") {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"data":    nil,
-				"message": "Formato de token inválido",
+				"message": "Token no proporcionado",
 				"status":  fiber.StatusUnauthorized,
 			})
 		}
 
-		secret := os.Getenv("JWT_SECRET")
-		if secret == "" {
+		jwtSvc, err := config.GetJWTService()
+		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 				"data":    nil,
-				"message": "Error interno: JWT_SECRET no configurado",
+				"message": "Error interno: JWT no configurado",
 				"status":  fiber.StatusInternalServerError,
 			})
 		}
 
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			return []byte(secret), nil
-		})
+		if claims, err := jwtSvc.ValidateToken(tokenString); err == nil {
+			if claims.MustChangePassword {
+				return passwordChangeRequired(c)
+			}
+			setClaimsLocals(c, claims)
+			return c.Next()
+		}
 
-		if err != nil || !token.Valid {
+		userID, err := jwtSvc.ValidateChallengeToken(tokenString, security.ChallengeMFAEnroll)
+		if err != nil {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"data":    nil,
 				"message": "Token inválido o expirado",
@@ -50,16 +144,22 @@ func AuthRequired() fiber.Handler {
 			})
 		}
 
-		claims := token.Claims.(jwt.MapClaims)
-
-		c.Locals("userID", claims["id"])
-		c.Locals("userEmail", claims["email"])
-		c.Locals("userRole", claims["role"])
-
-		if office, ok := claims["office"]; ok {
-			c.Locals("userOffice", office)
-		}
-
+		c.Locals("userID", userID)
+		c.Locals("mfaEnrollment", true)
 		return c.Next()
 	}
 }
+
+func setClaimsLocals(c fiber.Ctx, claims *security.AccessClaims) {
+	c.Locals("userID", claims.Subject)
+	c.Locals("userEmail", claims.Email)
+	c.Locals("userRole", claims.Role)
+}
+
+func passwordChangeRequired(c fiber.Ctx) error {
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"data":    nil,
+		"message": "Debe cambiar su contraseña antes de continuar",
+		"status":  fiber.StatusForbidden,
+	})
+}
@@ -0,0 +1,103 @@
+// server/pkgs/middleware/rbac.go
+package middleware
+
+import (
+	"server/internal/models"
+
+	"github.com/gofiber/fiber/v3"
+)
+
+type Permission string
+
+const (
+	PermRead                 Permission = "read"
+	PermElectionsManage      Permission = "elections:manage"
+	PermPositionsManage      Permission = "positions:manage"
+	PermCandidatesManage     Permission = "candidates:manage"
+	PermMesasManage          Permission = "mesas:manage"
+	PermVotesCreate          Permission = "votes:create"
+	PermVotesCorrect         Permission = "votes:correct"
+	PermDiscrepanciesRead    Permission = "discrepancies:read"
+	PermDiscrepanciesResolve Permission = "discrepancies:resolve"
+	PermUsersManage          Permission = "users:manage"
+	PermAuditRead            Permission = "audit:read"
+	PermWebhooksManage       Permission = "webhooks:manage"
+)
+
+// Policy: tabla única de permisos por rol. SUPERADMIN tiene todos.
+// PERSONERO solo lee, y los servicios limitan sus lecturas de candidatos, votos,
+// actas y discrepancias a su propio partido; los resultados agregados no.
+var Policy = map[models.Rol][]Permission{
+	models.RolAdmin: {
+		PermRead,
+		PermElectionsManage,
+		PermPositionsManage,
+		PermCandidatesManage,
+		PermMesasManage,
+		PermVotesCreate,
+		PermVotesCorrect,
+		PermDiscrepanciesRead,
+		PermDiscrepanciesResolve,
+		PermUsersManage,
+		PermAuditRead,
+		PermWebhooksManage,
+	},
+	models.RolDigitador: {
+		PermRead,
+		PermVotesCreate,
+	},
+	models.RolSupervisor: {
+		PermRead,
+		PermVotesCorrect,
+		PermDiscrepanciesRead,
+		PermDiscrepanciesResolve,
+		PermAuditRead,
+	},
+	models.RolPersonero: {
+		PermRead,
+		PermDiscrepanciesRead,
+	},
+	models.RolObserver: {
+		PermRead,
+	},
+}
+
+// Can indica si el rol tiene el permiso según Policy
+func Can(role string, perm Permission) bool {
+	if models.Rol(role) == models.RolSuperAdmin {
+		return true
+	}
+	for _, p := range Policy[models.Rol(role)] {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// RequirePermission debe montarse después de AuthRequired; exige que el rol
+// del token tenga todos los permisos indicados.
+func RequirePermission(perms ...Permission) fiber.Handler {
+	return func(c fiber.Ctx) error {
+		role, _ := c.Locals("userRole").(string)
+		if role == "" {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"data":    nil,
+				"message": "Rol no disponible",
+				"status":  fiber.StatusUnauthorized,
+			})
+		}
+
+		for _, perm := range perms {
+			if !Can(role, perm) {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"data":    nil,
+					"message": "No tienes permisos para esta acción",
+					"status":  fiber.StatusForbidden,
+				})
+			}
+		}
+
+		return c.Next()
+	}
+}
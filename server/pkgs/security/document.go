@@ -0,0 +1,133 @@
+package security
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var (
+	ErrSignatureInvalid = errors.New("la firma no corresponde al documento")
+	ErrInvalidKey       = errors.New("clave Ed25519 inválida")
+)
+
+// DocumentSigner firma documentos JSON (p. ej. resultados certificados) con
+// Ed25519. Lo que se firma es siempre la forma canónica del documento.
+type DocumentSigner struct {
+	KeyID string
+	priv  ed25519.PrivateKey
+}
+
+func NewDocumentSigner(priv ed25519.PrivateKey) *DocumentSigner {
+	return &DocumentSigner{
+		KeyID: KeyFingerprint(priv.Public().(ed25519.PublicKey)),
+		priv:  priv,
+	}
+}
+
+func (s *DocumentSigner) PublicKey() ed25519.PublicKey {
+	return s.priv.Public().(ed25519.PublicKey)
+}
+
+// Sign canonicaliza el documento y devuelve esa forma junto con la firma en base64
+func (s *DocumentSigner) Sign(doc []byte) ([]byte, string, error) {
+	canonical, err := CanonicalJSON(doc)
+	if err != nil {
+		return nil, "", err
+	}
+	sig := ed25519.Sign(s.priv, canonical)
+	return canonical, base64.StdEncoding.EncodeToString(sig), nil
+}
+
+// VerifyDocument comprueba una firma base64 sobre la forma canónica del
+// documento, de modo que reformatear el JSON no invalida la verificación
+func VerifyDocument(pub ed25519.PublicKey, doc []byte, signature string) error {
+	canonical, err := CanonicalJSON(doc)
+	if err != nil {
+		return err
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(signature))
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return fmt.Errorf("%w: firma mal codificada", ErrSignatureInvalid)
+	}
+	if !ed25519.Verify(pub, canonical, sig) {
+		return ErrSignatureInvalid
+	}
+	return nil
+}
+
+// CanonicalJSON: claves de objeto ordenadas, sin espacios y con los números
+// tal como aparecen en el original
+func CanonicalJSON(doc []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("documento JSON inválido: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("documento JSON inválido: datos después del objeto")
+	}
+
+	return json.Marshal(v)
+}
+
+// DocumentHash: SHA-256 en hex de la forma canónica
+func DocumentHash(canonical []byte) string {
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:])
+}
+
+// KeyFingerprint identifica una clave pública por los primeros 8 bytes de su SHA-256
+func KeyFingerprint(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:8])
+}
+
+// ParseEd25519PrivateKey lee una clave privada PKCS#8 en PEM
+func ParseEd25519PrivateKey(data []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no está en formato PEM", ErrInvalidKey)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+	}
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: la clave privada no es Ed25519", ErrInvalidKey)
+	}
+	return priv, nil
+}
+
+// ParseEd25519PublicKey acepta una clave PKIX en PEM o los 32 bytes en base64
+func ParseEd25519PublicKey(data []byte) (ed25519.PublicKey, error) {
+	if block, _ := pem.Decode(data); block != nil {
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidKey, err)
+		}
+		pub, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("%w: la clave pública no es Ed25519", ErrInvalidKey)
+		}
+		return pub, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("%w: se esperaba PEM o 32 bytes en base64", ErrInvalidKey)
+	}
+	return ed25519.PublicKey(raw), nil
+}
@@ -0,0 +1,115 @@
+package security
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestCanonicalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{"claves ordenadas", `{"b":1,"a":2}`, `{"a":2,"b":1}`},
+		{"objetos anidados", `{"z":{"y":1,"x":[{"d":1,"c":2}]},"a":null}`, `{"a":null,"z":{"x":[{"c":2,"d":1}],"y":1}}`},
+		{"sin espacios", "{\n  \"a\" : [ 1 , 2 ],\n\t\"b\": \"x y\"\n}", `{"a":[1,2],"b":"x y"}`},
+		{"números tal cual", `{"n":1.50,"e":1e3,"big":12345678901234567890}`, `{"big":12345678901234567890,"e":1e3,"n":1.50}`},
+		{"unicode", `{"nombre":"Elección Ñandú"}`, `{"nombre":"Elección Ñandú"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CanonicalJSON([]byte(tt.doc))
+			if err != nil {
+				t.Fatalf("CanonicalJSON: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonicalJSONInvalid(t *testing.T) {
+	for _, doc := range []string{``, `{`, `{"a":1} {"b":2}`, `{"a":1}x`} {
+		if _, err := CanonicalJSON([]byte(doc)); err == nil {
+			t.Errorf("%q: se esperaba error", doc)
+		}
+	}
+}
+
+func TestDocumentSignVerify(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer := NewDocumentSigner(priv)
+	canonical, sig, err := signer.Sign([]byte(`{"electionId":"e1","positions":[{"id":"p1","votes":10}],"certifiedAt":"2026-10-18T12:00:00Z"}`))
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if signer.KeyID != KeyFingerprint(pub) {
+		t.Errorf("KeyID %s, want %s", signer.KeyID, KeyFingerprint(pub))
+	}
+
+	tampered := []byte(sig)
+	tampered[0] ^= 1
+
+	tests := []struct {
+		name    string
+		pub     ed25519.PublicKey
+		doc     string
+		sig     string
+		wantErr bool
+	}{
+		{"forma canónica", pub, string(canonical), sig, false},
+		{"reformateado", pub, "{\n  \"certifiedAt\": \"2026-10-18T12:00:00Z\",\n  \"positions\": [ { \"votes\": 10, \"id\": \"p1\" } ],\n  \"electionId\": \"e1\"\n}\n", sig, false},
+		{"firma con salto de línea", pub, string(canonical), sig + "\n", false},
+		{"voto alterado", pub, `{"electionId":"e1","positions":[{"id":"p1","votes":11}],"certifiedAt":"2026-10-18T12:00:00Z"}`, sig, true},
+		{"número reescrito", pub, `{"electionId":"e1","positions":[{"id":"p1","votes":10.0}],"certifiedAt":"2026-10-18T12:00:00Z"}`, sig, true},
+		{"otra clave", otherPub, string(canonical), sig, true},
+		{"firma alterada", pub, string(canonical), string(tampered), true},
+		{"firma no base64", pub, string(canonical), "no-es-base64", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyDocument(tt.pub, []byte(tt.doc), tt.sig)
+			if tt.wantErr {
+				if !errors.Is(err, ErrSignatureInvalid) {
+					t.Errorf("got %v, want ErrSignatureInvalid", err)
+				}
+			} else if err != nil {
+				t.Errorf("VerifyDocument: %v", err)
+			}
+		})
+	}
+}
+
+func TestParseEd25519PublicKey(t *testing.T) {
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ParseEd25519PublicKey([]byte(base64.StdEncoding.EncodeToString(pub) + "\n"))
+	if err != nil {
+		t.Fatalf("base64: %v", err)
+	}
+	if !got.Equal(pub) {
+		t.Error("la clave leída no coincide")
+	}
+
+	if _, err := ParseEd25519PublicKey([]byte("AAAA")); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("got %v, want ErrInvalidKey", err)
+	}
+}
@@ -0,0 +1,211 @@
+package security
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// GoogleJWKSURL: claves públicas con las que Google firma sus ID tokens
+	GoogleJWKSURL = "https://www.googleapis.com/oauth2/v3/certs"
+
+	// jwksRefreshInterval: tiempo mínimo entre recargas del JWKS
+	jwksRefreshInterval = time.Hour
+	// jwksRetryInterval: evita recargar en cada petición si llega un kid desconocido
+	jwksRetryInterval = time.Minute
+)
+
+var googleIssuers = []string{"accounts.google.com", "https://accounts.google.com"}
+
+var (
+	ErrGoogleTokenInvalid     = errors.New("google id token is invalid")
+	ErrGoogleEmailNotVerified = errors.New("google email is not verified")
+	ErrGoogleNotConfigured    = errors.New("google signin is not configured")
+)
+
+// GoogleClaims: datos del ID token que usa el login
+type GoogleClaims struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Name          string `json:"name"`
+	jwt.RegisteredClaims
+}
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// GoogleVerifier valida ID tokens de Google contra un JWKS remoto o local.
+// Con jwksFile definido no se hace ninguna petición de red.
+type GoogleVerifier struct {
+	clientID string
+	jwksURL  string
+	jwksFile string
+	client   *http.Client
+
+	mu        sync.RWMutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+func NewGoogleVerifier(clientID, jwksURL, jwksFile string) *GoogleVerifier {
+	if jwksURL == "" {
+		jwksURL = GoogleJWKSURL
+	}
+	return &GoogleVerifier{
+		clientID: clientID,
+		jwksURL:  jwksURL,
+		jwksFile: jwksFile,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Verify comprueba firma, audiencia, emisor, expiración y email_verified
+func (g *GoogleVerifier) Verify(idToken string) (*GoogleClaims, error) {
+	if g == nil || g.clientID == "" {
+		return nil, ErrGoogleNotConfigured
+	}
+
+	claims := &GoogleClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims, g.keyFunc,
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithAudience(g.clientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrGoogleTokenInvalid, err)
+	}
+
+	if !validGoogleIssuer(claims.Issuer) {
+		return nil, fmt.Errorf("%w: issuer %q", ErrGoogleTokenInvalid, claims.Issuer)
+	}
+	if claims.Subject == "" || claims.Email == "" {
+		return nil, fmt.Errorf("%w: missing sub or email", ErrGoogleTokenInvalid)
+	}
+	if !claims.EmailVerified {
+		return nil, ErrGoogleEmailNotVerified
+	}
+
+	return claims, nil
+}
+
+func (g *GoogleVerifier) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	if kid == "" {
+		return nil, errors.New("missing kid header")
+	}
+
+	if key := g.cachedKey(kid, jwksRefreshInterval); key != nil {
+		return key, nil
+	}
+
+	// kid desconocido o caché vencida: Google rota sus claves, se recarga el JWKS
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if key, ok := g.keys[kid]; ok && time.Since(g.fetchedAt) < jwksRefreshInterval {
+		return key, nil
+	}
+	if g.keys == nil || time.Since(g.fetchedAt) >= jwksRetryInterval {
+		keys, err := g.loadKeys()
+		if err != nil {
+			return nil, err
+		}
+		g.keys = keys
+		g.fetchedAt = time.Now()
+	}
+
+	if key, ok := g.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown kid %q", kid)
+}
+
+func (g *GoogleVerifier) cachedKey(kid string, maxAge time.Duration) *rsa.PublicKey {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if time.Since(g.fetchedAt) >= maxAge {
+		return nil
+	}
+	return g.keys[kid]
+}
+
+func (g *GoogleVerifier) loadKeys() (map[string]*rsa.PublicKey, error) {
+	var raw []byte
+	if g.jwksFile != "" {
+		data, err := os.ReadFile(g.jwksFile)
+		if err != nil {
+			return nil, fmt.Errorf("read jwks file: %w", err)
+		}
+		raw = data
+	} else {
+		resp, err := g.client.Get(g.jwksURL)
+		if err != nil {
+			return nil, fmt.Errorf("fetch jwks: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("fetch jwks: status %d", resp.StatusCode)
+		}
+		if raw, err = io.ReadAll(resp.Body); err != nil {
+			return nil, fmt.Errorf("fetch jwks: %w", err)
+		}
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(raw, &set); err != nil {
+		return nil, fmt.Errorf("decode jwks: %w", err)
+	}
+	return parseJWKS(set.Keys)
+}
+
+func parseJWKS(set []jwk) (map[string]*rsa.PublicKey, error) {
+	keys := make(map[string]*rsa.PublicKey, len(set))
+	for _, k := range set {
+		if k.Kty != "RSA" || k.Kid == "" {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("jwk %s: invalid modulus: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("jwk %s: invalid exponent: %w", k.Kid, err)
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("jwks has no usable RSA keys")
+	}
+	return keys, nil
+}
+
+func validGoogleIssuer(iss string) bool {
+	for _, allowed := range googleIssuers {
+		if iss == allowed {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,206 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const googleTestClientID = "client-123.apps.googleusercontent.com"
+
+// googleTestJWKS: escribe un JWKS con la clave pública bajo kid y devuelve la ruta
+func googleTestJWKS(t *testing.T, kid string, pub *rsa.PublicKey) string {
+	t.Helper()
+
+	set := map[string][]jwk{"keys": {{
+		Kid: kid,
+		Kty: "RSA",
+		Alg: "RS256",
+		N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}}}
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func googleTestClaims() GoogleClaims {
+	now := time.Now()
+	return GoogleClaims{
+		Email:         "ana@example.com",
+		EmailVerified: true,
+		Name:          "Ana",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "https://accounts.google.com",
+			Subject:   "1234567890",
+			Audience:  jwt.ClaimStrings{googleTestClientID},
+			IssuedAt:  jwt.NewNumericDate(now.Add(-time.Minute)),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+	}
+}
+
+func TestGoogleVerify(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier := NewGoogleVerifier(googleTestClientID, "", googleTestJWKS(t, "kid-1", &key.PublicKey))
+
+	tests := []struct {
+		name    string
+		mutate  func(c *GoogleClaims)
+		signer  *rsa.PrivateKey
+		kid     string
+		wantErr error
+	}{
+		{name: "token válido", mutate: func(c *GoogleClaims) {}},
+		{name: "emisor sin esquema", mutate: func(c *GoogleClaims) { c.Issuer = "accounts.google.com" }},
+		{name: "firmado con otra clave", mutate: func(c *GoogleClaims) {}, signer: other, wantErr: ErrGoogleTokenInvalid},
+		{name: "kid desconocido", mutate: func(c *GoogleClaims) {}, kid: "kid-2", wantErr: ErrGoogleTokenInvalid},
+		{name: "otra audiencia", mutate: func(c *GoogleClaims) {
+			c.Audience = jwt.ClaimStrings{"otro-cliente.apps.googleusercontent.com"}
+		}, wantErr: ErrGoogleTokenInvalid},
+		{name: "otro emisor", mutate: func(c *GoogleClaims) { c.Issuer = "https://evil.example.com" }, wantErr: ErrGoogleTokenInvalid},
+		{name: "vencido", mutate: func(c *GoogleClaims) {
+			c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+		}, wantErr: ErrGoogleTokenInvalid},
+		{name: "sin expiración", mutate: func(c *GoogleClaims) { c.ExpiresAt = nil }, wantErr: ErrGoogleTokenInvalid},
+		{name: "emitido en el futuro", mutate: func(c *GoogleClaims) {
+			c.IssuedAt = jwt.NewNumericDate(time.Now().Add(time.Hour))
+		}, wantErr: ErrGoogleTokenInvalid},
+		{name: "sin email", mutate: func(c *GoogleClaims) { c.Email = "" }, wantErr: ErrGoogleTokenInvalid},
+		{name: "sin sub", mutate: func(c *GoogleClaims) { c.Subject = "" }, wantErr: ErrGoogleTokenInvalid},
+		{name: "email no verificado", mutate: func(c *GoogleClaims) { c.EmailVerified = false }, wantErr: ErrGoogleEmailNotVerified},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := googleTestClaims()
+			tt.mutate(&claims)
+			signer, kid := key, "kid-1"
+			if tt.signer != nil {
+				signer = tt.signer
+			}
+			if tt.kid != "" {
+				kid = tt.kid
+			}
+
+			token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+			token.Header["kid"] = kid
+			idToken, err := token.SignedString(signer)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := verifier.Verify(idToken)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if got.Email != claims.Email || got.Subject != claims.Subject || got.Name != claims.Name {
+				t.Errorf("claims = %+v", got)
+			}
+		})
+	}
+}
+
+func TestGoogleVerifyRejectsOtherAlgorithms(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier := NewGoogleVerifier(googleTestClientID, "", googleTestJWKS(t, "kid-1", &key.PublicKey))
+
+	// HS256 firmado con el módulo público como secreto: confusión de algoritmo
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, googleTestClaims())
+	token.Header["kid"] = "kid-1"
+	idToken, err := token.SignedString(key.PublicKey.N.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifier.Verify(idToken); !errors.Is(err, ErrGoogleTokenInvalid) {
+		t.Errorf("HS256: got %v, want ErrGoogleTokenInvalid", err)
+	}
+
+	// sin kid no se busca ninguna clave
+	token = jwt.NewWithClaims(jwt.SigningMethodRS256, googleTestClaims())
+	idToken, err = token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := verifier.Verify(idToken); !errors.Is(err, ErrGoogleTokenInvalid) {
+		t.Errorf("sin kid: got %v, want ErrGoogleTokenInvalid", err)
+	}
+}
+
+func TestGoogleVerifyNotConfigured(t *testing.T) {
+	var nilVerifier *GoogleVerifier
+	for name, v := range map[string]*GoogleVerifier{
+		"nil":          nilVerifier,
+		"sin clientID": NewGoogleVerifier("", "", "no-existe.json"),
+	} {
+		if _, err := v.Verify("x.y.z"); !errors.Is(err, ErrGoogleNotConfigured) {
+			t.Errorf("%s: got %v, want ErrGoogleNotConfigured", name, err)
+		}
+	}
+}
+
+func TestParseJWKS(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     []jwk
+		wantErr bool
+		wantKid []string
+	}{
+		{"ignora claves no RSA y sin kid", []jwk{
+			{Kid: "ec", Kty: "EC"},
+			{Kty: "RSA", N: "AQAB", E: "AQAB"},
+			{Kid: "rsa", Kty: "RSA", N: "AQAB", E: "AQAB"},
+		}, false, []string{"rsa"}},
+		{"sin claves utilizables", []jwk{{Kid: "ec", Kty: "EC"}}, true, nil},
+		{"módulo inválido", []jwk{{Kid: "rsa", Kty: "RSA", N: "no*base64", E: "AQAB"}}, true, nil},
+		{"exponente inválido", []jwk{{Kid: "rsa", Kty: "RSA", N: "AQAB", E: "no*base64"}}, true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := parseJWKS(tt.set)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(keys) != len(tt.wantKid) {
+				t.Fatalf("%d claves, want %d", len(keys), len(tt.wantKid))
+			}
+			for _, kid := range tt.wantKid {
+				if k := keys[kid]; k == nil || k.E != 65537 {
+					t.Errorf("clave %s = %+v", kid, k)
+				}
+			}
+		})
+	}
+}
@@ -1,36 +1,199 @@
 package security
 
 import (
+	"crypto/ed25519"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
-	"github.com/golang-jwt/jwt/v5"
+	"errors"
+	"fmt"
 	"log"
 	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Propósitos de los challenge tokens del login
+const (
+	ChallengeMFA       = "mfa"
+	ChallengeMFAEnroll = "mfa_enroll"
+)
+
+var (
+	ErrTokenInvalid   = errors.New("token inválido o expirado")
+	ErrNoSigningKey   = errors.New("no hay clave de firma JWT configurada")
+	ErrDuplicateKeyID = errors.New("kid de clave JWT duplicado")
 )
 
+// JWTKey: clave identificada por kid. Sign es nil para claves anteriores de
+// EdDSA de las que solo se conserva la parte pública.
+type JWTKey struct {
+	ID     string
+	Method jwt.SigningMethod
+	Sign   interface{}
+	Verify interface{}
+}
+
+// NewHMACKey crea una clave HS256 a partir de un secreto compartido
+func NewHMACKey(kid, secret string) JWTKey {
+	return JWTKey{ID: kid, Method: jwt.SigningMethodHS256, Sign: []byte(secret), Verify: []byte(secret)}
+}
+
+// NewEd25519Key crea una clave EdDSA de firma
+func NewEd25519Key(kid string, priv ed25519.PrivateKey) JWTKey {
+	return JWTKey{ID: kid, Method: jwt.SigningMethodEdDSA, Sign: priv, Verify: priv.Public()}
+}
+
+// NewEd25519VerifyKey crea una clave EdDSA que solo se acepta al verificar
+func NewEd25519VerifyKey(kid string, pub ed25519.PublicKey) JWTKey {
+	return JWTKey{ID: kid, Method: jwt.SigningMethodEdDSA, Verify: pub}
+}
+
+// AccessClaims: claims del access token emitido por la API
+type AccessClaims struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+	// MustChangePassword limita el token al cambio de contraseña
+	MustChangePassword bool `json:"mcp,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// JWTService es el único emisor y verificador de access tokens. Firma con la
+// clave activa y acepta también las anteriores, de modo que rotar la clave no
+// invalida las sesiones abiertas.
 type JWTService struct {
-	SecretKey string
-	TTL       time.Duration
+	Issuer   string
+	Audience string
+	TTL      time.Duration
+
+	active  JWTKey
+	keys    map[string]JWTKey
+	methods []string
 }
 
-func NewJWTService(secret string, ttl time.Duration) *JWTService {
-	return &JWTService{
-		SecretKey: secret,
-		TTL:       ttl,
+func NewJWTService(issuer, audience string, ttl time.Duration, active JWTKey, previous ...JWTKey) (*JWTService, error) {
+	if active.ID == "" || active.Sign == nil {
+		return nil, ErrNoSigningKey
+	}
+
+	j := &JWTService{
+		Issuer:   issuer,
+		Audience: audience,
+		TTL:      ttl,
+		active:   active,
+		keys:     make(map[string]JWTKey, len(previous)+1),
+	}
+
+	for _, k := range append([]JWTKey{active}, previous...) {
+		if _, ok := j.keys[k.ID]; ok {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateKeyID, k.ID)
+		}
+		j.keys[k.ID] = k
+		if !containsString(j.methods, k.Method.Alg()) {
+			j.methods = append(j.methods, k.Method.Alg())
+		}
 	}
+
+	return j, nil
 }
 
-func (j *JWTService) GenerateToken(userID string, email string, role string) (string, error) {
-	claims := jwt.MapClaims{
-		"sub":   userID,
-		"email": email,
-		"role":  role,
-		"exp":   time.Now().Add(j.TTL).Unix(),
-		"iat":   time.Now().Unix(),
+// GenerateToken firma un access token con la clave activa
+func (j *JWTService) GenerateToken(userID string, email string, role string, mustChangePassword bool) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(j.TTL)
+
+	claims := AccessClaims{
+		Email:              email,
+		Role:               role,
+		MustChangePassword: mustChangePassword,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			Issuer:    j.Issuer,
+			Audience:  jwt.ClaimStrings{j.Audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(j.SecretKey))
+	token := jwt.NewWithClaims(j.active.Method, claims)
+	token.Header["kid"] = j.active.ID
+
+	signed, err := token.SignedString(j.active.Sign)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return signed, expiresAt, nil
+}
+
+// ValidateToken verifica firma, kid, algoritmo, emisor, audiencia y expiración
+func (j *JWTService) ValidateToken(tokenString string) (*AccessClaims, error) {
+	claims := &AccessClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, j.keyFunc,
+		jwt.WithValidMethods(j.methods),
+		jwt.WithIssuer(j.Issuer),
+		jwt.WithAudience(j.Audience),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrTokenInvalid, err)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing sub", ErrTokenInvalid)
+	}
+	return claims, nil
+}
+
+// GenerateChallengeToken firma un token de corta vida para un paso intermedio
+// del login (p. ej. "mfa"). Su audiencia incluye el propósito, así que nunca
+// es aceptado por ValidateToken como access token.
+func (j *JWTService) GenerateChallengeToken(userID, purpose string, ttl time.Duration) (string, error) {
+	now := time.Now()
+	claims := jwt.RegisteredClaims{
+		Subject:   userID,
+		Issuer:    j.Issuer,
+		Audience:  jwt.ClaimStrings{j.challengeAudience(purpose)},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+	}
+
+	token := jwt.NewWithClaims(j.active.Method, claims)
+	token.Header["kid"] = j.active.ID
+	return token.SignedString(j.active.Sign)
+}
+
+// ValidateChallengeToken verifica un token de GenerateChallengeToken y devuelve el usuario
+func (j *JWTService) ValidateChallengeToken(tokenString, purpose string) (string, error) {
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, j.keyFunc,
+		jwt.WithValidMethods(j.methods),
+		jwt.WithIssuer(j.Issuer),
+		jwt.WithAudience(j.challengeAudience(purpose)),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrTokenInvalid, err)
+	}
+	if claims.Subject == "" {
+		return "", fmt.Errorf("%w: missing sub", ErrTokenInvalid)
+	}
+	return claims.Subject, nil
+}
+
+func (j *JWTService) challengeAudience(purpose string) string {
+	return j.Audience + ":" + purpose
+}
+
+func (j *JWTService) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := j.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown kid %q", kid)
+	}
+	// el algoritmo debe ser el de la clave, no solo uno de los admitidos
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected alg %s for kid %q", token.Method.Alg(), kid)
+	}
+	return key.Verify, nil
 }
 
 func GenerateRandomToken(length int) string {
@@ -41,3 +204,18 @@ func GenerateRandomToken(length int) string {
 	}
 	return hex.EncodeToString(bytes)
 }
+
+// HashToken devuelve el SHA-256 en hex de un token opaco para guardarlo en BD
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
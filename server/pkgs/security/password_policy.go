@@ -0,0 +1,68 @@
+package security
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrPasswordTooShort = errors.New("la contraseña es demasiado corta")
+	ErrPasswordCommon   = errors.New("la contraseña es demasiado común o aparece en filtraciones conocidas")
+	ErrPasswordIsEmail  = errors.New("la contraseña no puede ser igual al email")
+)
+
+// PasswordPolicy: reglas que debe cumplir toda contraseña nueva
+type PasswordPolicy struct {
+	MinLength int
+	blocklist map[string]struct{}
+}
+
+// NewPasswordPolicy carga la lista de contraseñas prohibidas (una por línea,
+// se ignoran vacías y las que empiezan por #). listFile puede estar vacío.
+func NewPasswordPolicy(minLength int, listFile string) (*PasswordPolicy, error) {
+	p := &PasswordPolicy{MinLength: minLength, blocklist: map[string]struct{}{}}
+	if listFile == "" {
+		return p, nil
+	}
+
+	f, err := os.Open(listFile)
+	if err != nil {
+		return nil, fmt.Errorf("abriendo lista de contraseñas: %w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p.blocklist[strings.ToLower(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("leyendo lista de contraseñas: %w", err)
+	}
+
+	return p, nil
+}
+
+// Validate comprueba longitud mínima, lista de prohibidas y que no sea el email
+func (p *PasswordPolicy) Validate(password, email string) error {
+	if utf8.RuneCountInString(password) < p.MinLength {
+		return fmt.Errorf("%w: mínimo %d caracteres", ErrPasswordTooShort, p.MinLength)
+	}
+
+	lower := strings.ToLower(password)
+	if _, blocked := p.blocklist[lower]; blocked {
+		return ErrPasswordCommon
+	}
+	if email != "" && lower == strings.ToLower(email) {
+		return ErrPasswordIsEmail
+	}
+
+	return nil
+}
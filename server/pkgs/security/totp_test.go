@@ -0,0 +1,93 @@
+package security
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret: el secreto ASCII "12345678901234567890" de los vectores de
+// prueba del RFC 6238 (SHA1), en base32
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestHOTPVectors(t *testing.T) {
+	key := []byte("12345678901234567890")
+	// RFC 6238, apéndice B: los 6 últimos dígitos de cada código de 8
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		if got := hotp(key, tt.unix/TOTPPeriod); got != tt.want {
+			t.Errorf("T=%d: got %s, want %s", tt.unix, got, tt.want)
+		}
+	}
+}
+
+func TestVerifyTOTPWindow(t *testing.T) {
+	// 287082 es el código del paso 1 (segundos 30 a 59)
+	const code = "287082"
+	const step = int64(1)
+
+	tests := []struct {
+		name   string
+		secret string
+		code   string
+		at     int64
+		want   bool
+	}{
+		{"paso actual", rfc6238Secret, code, 59, true},
+		{"inicio del paso", rfc6238Secret, code, 30, true},
+		{"un paso antes", rfc6238Secret, code, 0, true},
+		{"un paso después", rfc6238Secret, code, 89, true},
+		{"dos pasos después", rfc6238Secret, code, 90, false},
+		{"fuera de la ventana", rfc6238Secret, code, 1111111109, false},
+		{"con espacios", rfc6238Secret, " " + code + "\n", 59, true},
+		{"secreto en minúsculas", strings.ToLower(rfc6238Secret), code, 59, true},
+		{"código incorrecto", rfc6238Secret, "287083", 59, false},
+		{"código corto", rfc6238Secret, "28708", 59, false},
+		{"código de 8 dígitos", rfc6238Secret, "94287082", 59, false},
+		{"secreto inválido", "no-es-base32!", code, 59, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := VerifyTOTP(tt.secret, tt.code, time.Unix(tt.at, 0))
+			if ok != tt.want {
+				t.Fatalf("ok = %v, want %v", ok, tt.want)
+			}
+			// el paso devuelto es el del código, no el del reloj, para que
+			// el llamador detecte la reutilización dentro de la ventana
+			if ok && got != step {
+				t.Errorf("paso %d, want %d", got, step)
+			}
+		})
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("secreto no es base32: %v", err)
+	}
+	if len(key) != 20 {
+		t.Errorf("%d bytes, want 20", len(key))
+	}
+
+	code := hotp(key, time.Now().Unix()/TOTPPeriod)
+	if _, ok := VerifyTOTP(secret, code, time.Now()); !ok {
+		t.Error("el código generado con el secreto nuevo no verifica")
+	}
+}
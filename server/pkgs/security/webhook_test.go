@@ -0,0 +1,42 @@
+package security
+
+import "testing"
+
+func TestWebhookSignature(t *testing.T) {
+	const (
+		secret    = "whsec_test"
+		timestamp = int64(1760788800)
+		body      = `{"id":"evt-1","type":"ping"}`
+	)
+	// calculado aparte: HMAC-SHA256("whsec_test", "1760788800.<body>")
+	const want = "sha256=1befc8adfb0abee9d6d3ee4bfd06c0389167458e838d3aa6bf6f6ca56ae4a95f"
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      string
+		same      bool
+	}{
+		{"vector conocido", secret, timestamp, body, true},
+		{"otro secreto", "whsec_other", timestamp, body, false},
+		{"otro timestamp", secret, timestamp + 1, body, false},
+		{"cuerpo alterado", secret, timestamp, `{"id":"evt-2","type":"ping"}`, false},
+		{"cuerpo reformateado", secret, timestamp, `{"type":"ping","id":"evt-1"}`, false},
+		{"espacio final", secret, timestamp, body + "\n", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := WebhookSignature(tt.secret, tt.timestamp, []byte(tt.body))
+			if (got == want) != tt.same {
+				t.Errorf("got %s, igual a la esperada = %v, want %v", got, got == want, tt.same)
+			}
+		})
+	}
+
+	// cuerpo y secreto vacíos siguen firmando "<timestamp>."
+	if got := WebhookSignature("", 0, nil); got != "sha256=b849d5a581847b281957065739df36df2463d1977ea8d6e1e4e6cf33fadc68c3" {
+		t.Errorf("vacío: got %s", got)
+	}
+}